@@ -30,7 +30,7 @@ func main() {
 
 	config, err := coordinator.InitConfig(*configFilePath)
 	if err != nil {
-		log.Printf("Error reading/parsing config : %w", err)
+		log.Printf("Error reading/parsing config : %v", err)
 		return
 	}
 
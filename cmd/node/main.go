@@ -11,7 +11,6 @@ import (
 
 /*
 TODOs
-Elasticity
 Read operation could be exposed as an iterator
 Each server may implement thread pool
 */
@@ -4,12 +4,13 @@
 // - protoc             v5.28.3
 // source: proto/coordinator.proto
 
-// TODOOOOOOOO
-
 package gen
 
 import (
+	context "context"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -17,12 +18,43 @@ import (
 // Requires gRPC-Go v1.64.0 or later.
 const _ = grpc.SupportPackageIsVersion9
 
+const (
+	Coordinator_AddNode_FullMethodName       = "/coordinator.Coordinator/AddNode"
+	Coordinator_RemoveNode_FullMethodName    = "/coordinator.Coordinator/RemoveNode"
+	Coordinator_ListNodes_FullMethodName     = "/coordinator.Coordinator/ListNodes"
+	Coordinator_DrainNode_FullMethodName     = "/coordinator.Coordinator/DrainNode"
+	Coordinator_SetNodeWeight_FullMethodName = "/coordinator.Coordinator/SetNodeWeight"
+	Coordinator_GetRing_FullMethodName       = "/coordinator.Coordinator/GetRing"
+	Coordinator_GetOwner_FullMethodName      = "/coordinator.Coordinator/GetOwner"
+	Coordinator_GetBalance_FullMethodName    = "/coordinator.Coordinator/GetBalance"
+	Coordinator_Repair_FullMethodName        = "/coordinator.Coordinator/Repair"
+)
+
 // CoordinatorClient is the client API for Coordinator service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 //
-// The Coordinator service definition
+// The Coordinator service definition, administration of the cluster
 type CoordinatorClient interface {
+	// Dials the node and adds it to the ring, an ID already in the cluster fails with AlreadyExists
+	AddNode(ctx context.Context, in *CoordinatorAddNodeRequest, opts ...grpc.CallOption) (*CoordinatorAddNodeResponse, error)
+	// Removes the node from the ring and moves its keys to the new owners, an unknown ID fails with NotFound
+	RemoveNode(ctx context.Context, in *CoordinatorRemoveNodeRequest, opts ...grpc.CallOption) (*CoordinatorRemoveNodeResponse, error)
+	ListNodes(ctx context.Context, in *CoordinatorListNodesRequest, opts ...grpc.CallOption) (*CoordinatorListNodesResponse, error)
+	// Moves the keys of the node to the rest of the ring and shuts it down, returns once it is done
+	DrainNode(ctx context.Context, in *CoordinatorDrainNodeRequest, opts ...grpc.CallOption) (*CoordinatorDrainNodeResponse, error)
+	// Changes the share of the keys of the node, only the ranges of its added or removed virtual nodes move
+	SetNodeWeight(ctx context.Context, in *CoordinatorSetNodeWeightRequest, opts ...grpc.CallOption) (*CoordinatorSetNodeWeightResponse, error)
+	// Virtual node tokens and their owners at the ring version, or the weights of the nodes when the
+	// placement is not a ring
+	GetRing(ctx context.Context, in *CoordinatorGetRingRequest, opts ...grpc.CallOption) (*CoordinatorGetRingResponse, error)
+	// Position, owning virtual node and replicas of a key
+	GetOwner(ctx context.Context, in *CoordinatorGetOwnerRequest, opts ...grpc.CallOption) (*CoordinatorGetOwnerResponse, error)
+	// Share of the hash space and of the keys of each node, the key counts come from the node Stats
+	GetBalance(ctx context.Context, in *CoordinatorGetBalanceRequest, opts ...grpc.CallOption) (*CoordinatorGetBalanceResponse, error)
+	// Compares the Merkle trees of the replicas of every range and exchanges the keys that differ,
+	// FailedPrecondition when the placement is not a ring or keys are moving
+	Repair(ctx context.Context, in *CoordinatorRepairRequest, opts ...grpc.CallOption) (*CoordinatorRepairResponse, error)
 }
 
 type coordinatorClient struct {
@@ -33,12 +65,121 @@ func NewCoordinatorClient(cc grpc.ClientConnInterface) CoordinatorClient {
 	return &coordinatorClient{cc}
 }
 
+func (c *coordinatorClient) AddNode(ctx context.Context, in *CoordinatorAddNodeRequest, opts ...grpc.CallOption) (*CoordinatorAddNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorAddNodeResponse)
+	err := c.cc.Invoke(ctx, Coordinator_AddNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) RemoveNode(ctx context.Context, in *CoordinatorRemoveNodeRequest, opts ...grpc.CallOption) (*CoordinatorRemoveNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorRemoveNodeResponse)
+	err := c.cc.Invoke(ctx, Coordinator_RemoveNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) ListNodes(ctx context.Context, in *CoordinatorListNodesRequest, opts ...grpc.CallOption) (*CoordinatorListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorListNodesResponse)
+	err := c.cc.Invoke(ctx, Coordinator_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) DrainNode(ctx context.Context, in *CoordinatorDrainNodeRequest, opts ...grpc.CallOption) (*CoordinatorDrainNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorDrainNodeResponse)
+	err := c.cc.Invoke(ctx, Coordinator_DrainNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) SetNodeWeight(ctx context.Context, in *CoordinatorSetNodeWeightRequest, opts ...grpc.CallOption) (*CoordinatorSetNodeWeightResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorSetNodeWeightResponse)
+	err := c.cc.Invoke(ctx, Coordinator_SetNodeWeight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) GetRing(ctx context.Context, in *CoordinatorGetRingRequest, opts ...grpc.CallOption) (*CoordinatorGetRingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorGetRingResponse)
+	err := c.cc.Invoke(ctx, Coordinator_GetRing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) GetOwner(ctx context.Context, in *CoordinatorGetOwnerRequest, opts ...grpc.CallOption) (*CoordinatorGetOwnerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorGetOwnerResponse)
+	err := c.cc.Invoke(ctx, Coordinator_GetOwner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) GetBalance(ctx context.Context, in *CoordinatorGetBalanceRequest, opts ...grpc.CallOption) (*CoordinatorGetBalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorGetBalanceResponse)
+	err := c.cc.Invoke(ctx, Coordinator_GetBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *coordinatorClient) Repair(ctx context.Context, in *CoordinatorRepairRequest, opts ...grpc.CallOption) (*CoordinatorRepairResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorRepairResponse)
+	err := c.cc.Invoke(ctx, Coordinator_Repair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CoordinatorServer is the server API for Coordinator service.
 // All implementations must embed UnimplementedCoordinatorServer
 // for forward compatibility.
 //
-// The Coordinator service definition
+// The Coordinator service definition, administration of the cluster
 type CoordinatorServer interface {
+	// Dials the node and adds it to the ring, an ID already in the cluster fails with AlreadyExists
+	AddNode(context.Context, *CoordinatorAddNodeRequest) (*CoordinatorAddNodeResponse, error)
+	// Removes the node from the ring and moves its keys to the new owners, an unknown ID fails with NotFound
+	RemoveNode(context.Context, *CoordinatorRemoveNodeRequest) (*CoordinatorRemoveNodeResponse, error)
+	ListNodes(context.Context, *CoordinatorListNodesRequest) (*CoordinatorListNodesResponse, error)
+	// Moves the keys of the node to the rest of the ring and shuts it down, returns once it is done
+	DrainNode(context.Context, *CoordinatorDrainNodeRequest) (*CoordinatorDrainNodeResponse, error)
+	// Changes the share of the keys of the node, only the ranges of its added or removed virtual nodes move
+	SetNodeWeight(context.Context, *CoordinatorSetNodeWeightRequest) (*CoordinatorSetNodeWeightResponse, error)
+	// Virtual node tokens and their owners at the ring version, or the weights of the nodes when the
+	// placement is not a ring
+	GetRing(context.Context, *CoordinatorGetRingRequest) (*CoordinatorGetRingResponse, error)
+	// Position, owning virtual node and replicas of a key
+	GetOwner(context.Context, *CoordinatorGetOwnerRequest) (*CoordinatorGetOwnerResponse, error)
+	// Share of the hash space and of the keys of each node, the key counts come from the node Stats
+	GetBalance(context.Context, *CoordinatorGetBalanceRequest) (*CoordinatorGetBalanceResponse, error)
+	// Compares the Merkle trees of the replicas of every range and exchanges the keys that differ,
+	// FailedPrecondition when the placement is not a ring or keys are moving
+	Repair(context.Context, *CoordinatorRepairRequest) (*CoordinatorRepairResponse, error)
 	mustEmbedUnimplementedCoordinatorServer()
 }
 
@@ -49,6 +190,33 @@ type CoordinatorServer interface {
 // pointer dereference when methods are called.
 type UnimplementedCoordinatorServer struct{}
 
+func (UnimplementedCoordinatorServer) AddNode(context.Context, *CoordinatorAddNodeRequest) (*CoordinatorAddNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNode not implemented")
+}
+func (UnimplementedCoordinatorServer) RemoveNode(context.Context, *CoordinatorRemoveNodeRequest) (*CoordinatorRemoveNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNode not implemented")
+}
+func (UnimplementedCoordinatorServer) ListNodes(context.Context, *CoordinatorListNodesRequest) (*CoordinatorListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedCoordinatorServer) DrainNode(context.Context, *CoordinatorDrainNodeRequest) (*CoordinatorDrainNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainNode not implemented")
+}
+func (UnimplementedCoordinatorServer) SetNodeWeight(context.Context, *CoordinatorSetNodeWeightRequest) (*CoordinatorSetNodeWeightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNodeWeight not implemented")
+}
+func (UnimplementedCoordinatorServer) GetRing(context.Context, *CoordinatorGetRingRequest) (*CoordinatorGetRingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRing not implemented")
+}
+func (UnimplementedCoordinatorServer) GetOwner(context.Context, *CoordinatorGetOwnerRequest) (*CoordinatorGetOwnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOwner not implemented")
+}
+func (UnimplementedCoordinatorServer) GetBalance(context.Context, *CoordinatorGetBalanceRequest) (*CoordinatorGetBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedCoordinatorServer) Repair(context.Context, *CoordinatorRepairRequest) (*CoordinatorRepairResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Repair not implemented")
+}
 func (UnimplementedCoordinatorServer) mustEmbedUnimplementedCoordinatorServer() {}
 func (UnimplementedCoordinatorServer) testEmbeddedByValue()                     {}
 
@@ -70,13 +238,398 @@ func RegisterCoordinatorServer(s grpc.ServiceRegistrar, srv CoordinatorServer) {
 	s.RegisterService(&Coordinator_ServiceDesc, srv)
 }
 
+func _Coordinator_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorAddNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).AddNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_AddNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).AddNode(ctx, req.(*CoordinatorAddNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorRemoveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).RemoveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_RemoveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).RemoveNode(ctx, req.(*CoordinatorRemoveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).ListNodes(ctx, req.(*CoordinatorListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_DrainNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorDrainNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).DrainNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_DrainNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).DrainNode(ctx, req.(*CoordinatorDrainNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_SetNodeWeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorSetNodeWeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).SetNodeWeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_SetNodeWeight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).SetNodeWeight(ctx, req.(*CoordinatorSetNodeWeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_GetRing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorGetRingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).GetRing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_GetRing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).GetRing(ctx, req.(*CoordinatorGetRingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_GetOwner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorGetOwnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).GetOwner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_GetOwner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).GetOwner(ctx, req.(*CoordinatorGetOwnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorGetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).GetBalance(ctx, req.(*CoordinatorGetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Coordinator_Repair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorRepairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoordinatorServer).Repair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Coordinator_Repair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoordinatorServer).Repair(ctx, req.(*CoordinatorRepairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Coordinator_ServiceDesc is the grpc.ServiceDesc for Coordinator service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var Coordinator_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "coordinator.Coordinator",
 	HandlerType: (*CoordinatorServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams:     []grpc.StreamDesc{},
-	Metadata:    "proto/coordinator.proto",
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddNode",
+			Handler:    _Coordinator_AddNode_Handler,
+		},
+		{
+			MethodName: "RemoveNode",
+			Handler:    _Coordinator_RemoveNode_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Coordinator_ListNodes_Handler,
+		},
+		{
+			MethodName: "DrainNode",
+			Handler:    _Coordinator_DrainNode_Handler,
+		},
+		{
+			MethodName: "SetNodeWeight",
+			Handler:    _Coordinator_SetNodeWeight_Handler,
+		},
+		{
+			MethodName: "GetRing",
+			Handler:    _Coordinator_GetRing_Handler,
+		},
+		{
+			MethodName: "GetOwner",
+			Handler:    _Coordinator_GetOwner_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _Coordinator_GetBalance_Handler,
+		},
+		{
+			MethodName: "Repair",
+			Handler:    _Coordinator_Repair_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/coordinator.proto",
+}
+
+const (
+	Raft_RequestVote_FullMethodName     = "/coordinator.Raft/RequestVote"
+	Raft_AppendEntries_FullMethodName   = "/coordinator.Raft/AppendEntries"
+	Raft_InstallSnapshot_FullMethodName = "/coordinator.Raft/InstallSnapshot"
+)
+
+// RaftClient is the client API for Raft service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Raft among the coordinator replicas, served on the admin port. The replicated log holds the
+// cluster metadata: membership, ring version, virtual nodes and replication factor.
+type RaftClient interface {
+	RequestVote(ctx context.Context, in *CoordinatorRequestVoteRequest, opts ...grpc.CallOption) (*CoordinatorRequestVoteResponse, error)
+	AppendEntries(ctx context.Context, in *CoordinatorAppendEntriesRequest, opts ...grpc.CallOption) (*CoordinatorAppendEntriesResponse, error)
+	// Sent instead of the entries a follower misses once the leader compacted them
+	InstallSnapshot(ctx context.Context, in *CoordinatorInstallSnapshotRequest, opts ...grpc.CallOption) (*CoordinatorInstallSnapshotResponse, error)
+}
+
+type raftClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRaftClient(cc grpc.ClientConnInterface) RaftClient {
+	return &raftClient{cc}
+}
+
+func (c *raftClient) RequestVote(ctx context.Context, in *CoordinatorRequestVoteRequest, opts ...grpc.CallOption) (*CoordinatorRequestVoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorRequestVoteResponse)
+	err := c.cc.Invoke(ctx, Raft_RequestVote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raftClient) AppendEntries(ctx context.Context, in *CoordinatorAppendEntriesRequest, opts ...grpc.CallOption) (*CoordinatorAppendEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorAppendEntriesResponse)
+	err := c.cc.Invoke(ctx, Raft_AppendEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *raftClient) InstallSnapshot(ctx context.Context, in *CoordinatorInstallSnapshotRequest, opts ...grpc.CallOption) (*CoordinatorInstallSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoordinatorInstallSnapshotResponse)
+	err := c.cc.Invoke(ctx, Raft_InstallSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RaftServer is the server API for Raft service.
+// All implementations must embed UnimplementedRaftServer
+// for forward compatibility.
+//
+// Raft among the coordinator replicas, served on the admin port. The replicated log holds the
+// cluster metadata: membership, ring version, virtual nodes and replication factor.
+type RaftServer interface {
+	RequestVote(context.Context, *CoordinatorRequestVoteRequest) (*CoordinatorRequestVoteResponse, error)
+	AppendEntries(context.Context, *CoordinatorAppendEntriesRequest) (*CoordinatorAppendEntriesResponse, error)
+	// Sent instead of the entries a follower misses once the leader compacted them
+	InstallSnapshot(context.Context, *CoordinatorInstallSnapshotRequest) (*CoordinatorInstallSnapshotResponse, error)
+	mustEmbedUnimplementedRaftServer()
+}
+
+// UnimplementedRaftServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRaftServer struct{}
+
+func (UnimplementedRaftServer) RequestVote(context.Context, *CoordinatorRequestVoteRequest) (*CoordinatorRequestVoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestVote not implemented")
+}
+func (UnimplementedRaftServer) AppendEntries(context.Context, *CoordinatorAppendEntriesRequest) (*CoordinatorAppendEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendEntries not implemented")
+}
+func (UnimplementedRaftServer) InstallSnapshot(context.Context, *CoordinatorInstallSnapshotRequest) (*CoordinatorInstallSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallSnapshot not implemented")
+}
+func (UnimplementedRaftServer) mustEmbedUnimplementedRaftServer() {}
+func (UnimplementedRaftServer) testEmbeddedByValue()              {}
+
+// UnsafeRaftServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RaftServer will
+// result in compilation errors.
+type UnsafeRaftServer interface {
+	mustEmbedUnimplementedRaftServer()
+}
+
+func RegisterRaftServer(s grpc.ServiceRegistrar, srv RaftServer) {
+	// If the following call pancis, it indicates UnimplementedRaftServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Raft_ServiceDesc, srv)
+}
+
+func _Raft_RequestVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorRequestVoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftServer).RequestVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Raft_RequestVote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftServer).RequestVote(ctx, req.(*CoordinatorRequestVoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Raft_AppendEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorAppendEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftServer).AppendEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Raft_AppendEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftServer).AppendEntries(ctx, req.(*CoordinatorAppendEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Raft_InstallSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoordinatorInstallSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RaftServer).InstallSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Raft_InstallSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RaftServer).InstallSnapshot(ctx, req.(*CoordinatorInstallSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Raft_ServiceDesc is the grpc.ServiceDesc for Raft service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Raft_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.Raft",
+	HandlerType: (*RaftServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequestVote",
+			Handler:    _Raft_RequestVote_Handler,
+		},
+		{
+			MethodName: "AppendEntries",
+			Handler:    _Raft_AppendEntries_Handler,
+		},
+		{
+			MethodName: "InstallSnapshot",
+			Handler:    _Raft_InstallSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/coordinator.proto",
 }
@@ -19,10 +19,49 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Storage_Get_FullMethodName    = "/node.Storage/Get"
-	Storage_Put_FullMethodName    = "/node.Storage/Put"
-	Storage_Update_FullMethodName = "/node.Storage/Update"
-	Storage_Delete_FullMethodName = "/node.Storage/Delete"
+	Storage_Get_FullMethodName            = "/node.Storage/Get"
+	Storage_Put_FullMethodName            = "/node.Storage/Put"
+	Storage_Update_FullMethodName         = "/node.Storage/Update"
+	Storage_Delete_FullMethodName         = "/node.Storage/Delete"
+	Storage_Stats_FullMethodName          = "/node.Storage/Stats"
+	Storage_Range_FullMethodName          = "/node.Storage/Range"
+	Storage_QueryIndex_FullMethodName     = "/node.Storage/QueryIndex"
+	Storage_Increment_FullMethodName      = "/node.Storage/Increment"
+	Storage_Decrement_FullMethodName      = "/node.Storage/Decrement"
+	Storage_ListPush_FullMethodName       = "/node.Storage/ListPush"
+	Storage_ListPop_FullMethodName        = "/node.Storage/ListPop"
+	Storage_ListRange_FullMethodName      = "/node.Storage/ListRange"
+	Storage_SetAdd_FullMethodName         = "/node.Storage/SetAdd"
+	Storage_SetRemove_FullMethodName      = "/node.Storage/SetRemove"
+	Storage_SetMembers_FullMethodName     = "/node.Storage/SetMembers"
+	Storage_SetIsMember_FullMethodName    = "/node.Storage/SetIsMember"
+	Storage_HashSet_FullMethodName        = "/node.Storage/HashSet"
+	Storage_HashGet_FullMethodName        = "/node.Storage/HashGet"
+	Storage_HashDelete_FullMethodName     = "/node.Storage/HashDelete"
+	Storage_Watch_FullMethodName          = "/node.Storage/Watch"
+	Storage_StreamChanges_FullMethodName  = "/node.Storage/StreamChanges"
+	Storage_LeaseGrant_FullMethodName     = "/node.Storage/LeaseGrant"
+	Storage_LeaseKeepAlive_FullMethodName = "/node.Storage/LeaseKeepAlive"
+	Storage_LeaseRevoke_FullMethodName    = "/node.Storage/LeaseRevoke"
+	Storage_TxnPrepare_FullMethodName     = "/node.Storage/TxnPrepare"
+	Storage_TxnCommit_FullMethodName      = "/node.Storage/TxnCommit"
+	Storage_TxnAbort_FullMethodName       = "/node.Storage/TxnAbort"
+	Storage_Txn_FullMethodName            = "/node.Storage/Txn"
+	Storage_TransferKeys_FullMethodName   = "/node.Storage/TransferKeys"
+	Storage_ImportKeys_FullMethodName     = "/node.Storage/ImportKeys"
+	Storage_DropKeys_FullMethodName       = "/node.Storage/DropKeys"
+	Storage_Drain_FullMethodName          = "/node.Storage/Drain"
+	Storage_SetRing_FullMethodName        = "/node.Storage/SetRing"
+	Storage_GossipPing_FullMethodName     = "/node.Storage/GossipPing"
+	Storage_GossipPingReq_FullMethodName  = "/node.Storage/GossipPingReq"
+	Storage_GossipSync_FullMethodName     = "/node.Storage/GossipSync"
+	Storage_MerkleTree_FullMethodName     = "/node.Storage/MerkleTree"
+	Storage_RepairExport_FullMethodName   = "/node.Storage/RepairExport"
+	Storage_RepairImport_FullMethodName   = "/node.Storage/RepairImport"
+	Storage_Repair_FullMethodName         = "/node.Storage/Repair"
+	Storage_GetSiblings_FullMethodName    = "/node.Storage/GetSiblings"
+	Storage_PutSibling_FullMethodName     = "/node.Storage/PutSibling"
+	Storage_MergeSibling_FullMethodName   = "/node.Storage/MergeSibling"
 )
 
 // StorageClient is the client API for Storage service.
@@ -33,6 +72,68 @@ type StorageClient interface {
 	Put(ctx context.Context, in *StoragePutRequest, opts ...grpc.CallOption) (*StoragePutResponse, error)
 	Update(ctx context.Context, in *StorageUpdateRequest, opts ...grpc.CallOption) (*StorageUpdateResponse, error)
 	Delete(ctx context.Context, in *StorageDeleteRequest, opts ...grpc.CallOption) (*StorageDeleteResponse, error)
+	Stats(ctx context.Context, in *StorageStatsRequest, opts ...grpc.CallOption) (*StorageStatsResponse, error)
+	Range(ctx context.Context, in *StorageRangeRequest, opts ...grpc.CallOption) (*StorageRangeResponse, error)
+	QueryIndex(ctx context.Context, in *StorageQueryIndexRequest, opts ...grpc.CallOption) (*StorageQueryIndexResponse, error)
+	Increment(ctx context.Context, in *StorageIncrementRequest, opts ...grpc.CallOption) (*StorageIncrementResponse, error)
+	Decrement(ctx context.Context, in *StorageDecrementRequest, opts ...grpc.CallOption) (*StorageDecrementResponse, error)
+	// Lists, sets and hashes. Operations on a key holding another type fail with FailedPrecondition (WRONGTYPE).
+	ListPush(ctx context.Context, in *StorageListPushRequest, opts ...grpc.CallOption) (*StorageListPushResponse, error)
+	ListPop(ctx context.Context, in *StorageListPopRequest, opts ...grpc.CallOption) (*StorageListPopResponse, error)
+	ListRange(ctx context.Context, in *StorageListRangeRequest, opts ...grpc.CallOption) (*StorageListRangeResponse, error)
+	SetAdd(ctx context.Context, in *StorageSetAddRequest, opts ...grpc.CallOption) (*StorageSetAddResponse, error)
+	SetRemove(ctx context.Context, in *StorageSetRemoveRequest, opts ...grpc.CallOption) (*StorageSetRemoveResponse, error)
+	SetMembers(ctx context.Context, in *StorageSetMembersRequest, opts ...grpc.CallOption) (*StorageSetMembersResponse, error)
+	SetIsMember(ctx context.Context, in *StorageSetIsMemberRequest, opts ...grpc.CallOption) (*StorageSetIsMemberResponse, error)
+	HashSet(ctx context.Context, in *StorageHashSetRequest, opts ...grpc.CallOption) (*StorageHashSetResponse, error)
+	HashGet(ctx context.Context, in *StorageHashGetRequest, opts ...grpc.CallOption) (*StorageHashGetResponse, error)
+	HashDelete(ctx context.Context, in *StorageHashDeleteRequest, opts ...grpc.CallOption) (*StorageHashDeleteResponse, error)
+	// Streams changes of a key or key prefix, fails with OutOfRange when StartLSN is no longer retained
+	Watch(ctx context.Context, in *StorageWatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageWatchEvent], error)
+	// Streams every mutation of the node in LSN order for change data capture
+	StreamChanges(ctx context.Context, in *StorageStreamChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageChangeEvent], error)
+	// Leases delete their keys unless kept alive, unknown or expired leases fail with NotFound
+	LeaseGrant(ctx context.Context, in *StorageLeaseGrantRequest, opts ...grpc.CallOption) (*StorageLeaseGrantResponse, error)
+	LeaseKeepAlive(ctx context.Context, in *StorageLeaseKeepAliveRequest, opts ...grpc.CallOption) (*StorageLeaseKeepAliveResponse, error)
+	LeaseRevoke(ctx context.Context, in *StorageLeaseRevokeRequest, opts ...grpc.CallOption) (*StorageLeaseRevokeResponse, error)
+	// Two-phase commit of the part of a transaction owned by this node. Prepare locks the keys and
+	// evaluates the compares, a key locked by another transaction fails with Aborted.
+	TxnPrepare(ctx context.Context, in *StorageTxnPrepareRequest, opts ...grpc.CallOption) (*StorageTxnPrepareResponse, error)
+	TxnCommit(ctx context.Context, in *StorageTxnCommitRequest, opts ...grpc.CallOption) (*StorageTxnCommitResponse, error)
+	TxnAbort(ctx context.Context, in *StorageTxnAbortRequest, opts ...grpc.CallOption) (*StorageTxnAbortResponse, error)
+	// Evaluates the compares and applies Then or Else atomically, every key has to live on this node
+	Txn(ctx context.Context, in *StorageTxnRequest, opts ...grpc.CallOption) (*StorageTxnResponse, error)
+	// Moving keys between nodes when the ring changes. TransferKeys streams the keys of the ranges,
+	// ImportKeys writes them unless the key is already present and DropKeys deletes the old copies.
+	TransferKeys(ctx context.Context, in *StorageTransferKeysRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageEntry], error)
+	ImportKeys(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StorageEntry, StorageImportKeysResponse], error)
+	DropKeys(ctx context.Context, in *StorageDropKeysRequest, opts ...grpc.CallOption) (*StorageDropKeysResponse, error)
+	// Shuts the node down once its keys moved away, fails with FailedPrecondition while it holds keys
+	Drain(ctx context.Context, in *StorageDrainRequest, opts ...grpc.CallOption) (*StorageDrainResponse, error)
+	// Ownership of the keys pushed by the coordinator at every ring change. Once set the node
+	// rejects the keys it does not own with FailedPrecondition and a StorageWrongOwner detail.
+	SetRing(ctx context.Context, in *StorageSetRingRequest, opts ...grpc.CallOption) (*StorageSetRingResponse, error)
+	// SWIM gossip among the nodes. A ping is acked with the updates of the receiver, a ping request
+	// asks the receiver to ping Target on behalf of a node that could not reach it. Both carry
+	// membership updates. GossipSync exchanges the full membership, an empty request only reads it.
+	GossipPing(ctx context.Context, in *StorageGossipPingRequest, opts ...grpc.CallOption) (*StorageGossipPingResponse, error)
+	GossipPingReq(ctx context.Context, in *StorageGossipPingReqRequest, opts ...grpc.CallOption) (*StorageGossipPingReqResponse, error)
+	GossipSync(ctx context.Context, in *StorageGossipSyncRequest, opts ...grpc.CallOption) (*StorageGossipSyncResponse, error)
+	// Anti-entropy between replicas. MerkleTree returns digests of a level of the Merkle tree of
+	// the keys in the ranges, RepairExport streams the keys and tombstones of leaves of the tree and
+	// RepairImport keeps the newer of each streamed key and the local one. Repair compares the tree
+	// of the node with the one of Peer level by level and exchanges the keys of the leaves that differ.
+	MerkleTree(ctx context.Context, in *StorageMerkleTreeRequest, opts ...grpc.CallOption) (*StorageMerkleTreeResponse, error)
+	RepairExport(ctx context.Context, in *StorageRepairExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageEntry], error)
+	RepairImport(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StorageEntry, StorageRepairImportResponse], error)
+	Repair(ctx context.Context, in *StorageRepairRequest, opts ...grpc.CallOption) (*StorageRepairResponse, error)
+	// Keys versioned with vector clocks keep concurrent writes as siblings. GetSiblings returns
+	// them, a string written without vector clocks is a single sibling with an empty dot. PutSibling
+	// writes a value as the coordinator of the write, it replaces the siblings covered by Context.
+	// MergeSibling adds a sibling written by another replica.
+	GetSiblings(ctx context.Context, in *StorageGetSiblingsRequest, opts ...grpc.CallOption) (*StorageGetSiblingsResponse, error)
+	PutSibling(ctx context.Context, in *StoragePutSiblingRequest, opts ...grpc.CallOption) (*StoragePutSiblingResponse, error)
+	MergeSibling(ctx context.Context, in *StorageMergeSiblingRequest, opts ...grpc.CallOption) (*StorageMergeSiblingResponse, error)
 }
 
 type storageClient struct {
@@ -83,6 +184,438 @@ func (c *storageClient) Delete(ctx context.Context, in *StorageDeleteRequest, op
 	return out, nil
 }
 
+func (c *storageClient) Stats(ctx context.Context, in *StorageStatsRequest, opts ...grpc.CallOption) (*StorageStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageStatsResponse)
+	err := c.cc.Invoke(ctx, Storage_Stats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Range(ctx context.Context, in *StorageRangeRequest, opts ...grpc.CallOption) (*StorageRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageRangeResponse)
+	err := c.cc.Invoke(ctx, Storage_Range_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) QueryIndex(ctx context.Context, in *StorageQueryIndexRequest, opts ...grpc.CallOption) (*StorageQueryIndexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageQueryIndexResponse)
+	err := c.cc.Invoke(ctx, Storage_QueryIndex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Increment(ctx context.Context, in *StorageIncrementRequest, opts ...grpc.CallOption) (*StorageIncrementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageIncrementResponse)
+	err := c.cc.Invoke(ctx, Storage_Increment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Decrement(ctx context.Context, in *StorageDecrementRequest, opts ...grpc.CallOption) (*StorageDecrementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageDecrementResponse)
+	err := c.cc.Invoke(ctx, Storage_Decrement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListPush(ctx context.Context, in *StorageListPushRequest, opts ...grpc.CallOption) (*StorageListPushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageListPushResponse)
+	err := c.cc.Invoke(ctx, Storage_ListPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListPop(ctx context.Context, in *StorageListPopRequest, opts ...grpc.CallOption) (*StorageListPopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageListPopResponse)
+	err := c.cc.Invoke(ctx, Storage_ListPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListRange(ctx context.Context, in *StorageListRangeRequest, opts ...grpc.CallOption) (*StorageListRangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageListRangeResponse)
+	err := c.cc.Invoke(ctx, Storage_ListRange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetAdd(ctx context.Context, in *StorageSetAddRequest, opts ...grpc.CallOption) (*StorageSetAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageSetAddResponse)
+	err := c.cc.Invoke(ctx, Storage_SetAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetRemove(ctx context.Context, in *StorageSetRemoveRequest, opts ...grpc.CallOption) (*StorageSetRemoveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageSetRemoveResponse)
+	err := c.cc.Invoke(ctx, Storage_SetRemove_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetMembers(ctx context.Context, in *StorageSetMembersRequest, opts ...grpc.CallOption) (*StorageSetMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageSetMembersResponse)
+	err := c.cc.Invoke(ctx, Storage_SetMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetIsMember(ctx context.Context, in *StorageSetIsMemberRequest, opts ...grpc.CallOption) (*StorageSetIsMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageSetIsMemberResponse)
+	err := c.cc.Invoke(ctx, Storage_SetIsMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) HashSet(ctx context.Context, in *StorageHashSetRequest, opts ...grpc.CallOption) (*StorageHashSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageHashSetResponse)
+	err := c.cc.Invoke(ctx, Storage_HashSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) HashGet(ctx context.Context, in *StorageHashGetRequest, opts ...grpc.CallOption) (*StorageHashGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageHashGetResponse)
+	err := c.cc.Invoke(ctx, Storage_HashGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) HashDelete(ctx context.Context, in *StorageHashDeleteRequest, opts ...grpc.CallOption) (*StorageHashDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageHashDeleteResponse)
+	err := c.cc.Invoke(ctx, Storage_HashDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Watch(ctx context.Context, in *StorageWatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageWatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[0], Storage_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageWatchRequest, StorageWatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_WatchClient = grpc.ServerStreamingClient[StorageWatchEvent]
+
+func (c *storageClient) StreamChanges(ctx context.Context, in *StorageStreamChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[1], Storage_StreamChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageStreamChangesRequest, StorageChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_StreamChangesClient = grpc.ServerStreamingClient[StorageChangeEvent]
+
+func (c *storageClient) LeaseGrant(ctx context.Context, in *StorageLeaseGrantRequest, opts ...grpc.CallOption) (*StorageLeaseGrantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageLeaseGrantResponse)
+	err := c.cc.Invoke(ctx, Storage_LeaseGrant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) LeaseKeepAlive(ctx context.Context, in *StorageLeaseKeepAliveRequest, opts ...grpc.CallOption) (*StorageLeaseKeepAliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageLeaseKeepAliveResponse)
+	err := c.cc.Invoke(ctx, Storage_LeaseKeepAlive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) LeaseRevoke(ctx context.Context, in *StorageLeaseRevokeRequest, opts ...grpc.CallOption) (*StorageLeaseRevokeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageLeaseRevokeResponse)
+	err := c.cc.Invoke(ctx, Storage_LeaseRevoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) TxnPrepare(ctx context.Context, in *StorageTxnPrepareRequest, opts ...grpc.CallOption) (*StorageTxnPrepareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageTxnPrepareResponse)
+	err := c.cc.Invoke(ctx, Storage_TxnPrepare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) TxnCommit(ctx context.Context, in *StorageTxnCommitRequest, opts ...grpc.CallOption) (*StorageTxnCommitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageTxnCommitResponse)
+	err := c.cc.Invoke(ctx, Storage_TxnCommit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) TxnAbort(ctx context.Context, in *StorageTxnAbortRequest, opts ...grpc.CallOption) (*StorageTxnAbortResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageTxnAbortResponse)
+	err := c.cc.Invoke(ctx, Storage_TxnAbort_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Txn(ctx context.Context, in *StorageTxnRequest, opts ...grpc.CallOption) (*StorageTxnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageTxnResponse)
+	err := c.cc.Invoke(ctx, Storage_Txn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) TransferKeys(ctx context.Context, in *StorageTransferKeysRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[2], Storage_TransferKeys_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageTransferKeysRequest, StorageEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_TransferKeysClient = grpc.ServerStreamingClient[StorageEntry]
+
+func (c *storageClient) ImportKeys(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StorageEntry, StorageImportKeysResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[3], Storage_ImportKeys_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageEntry, StorageImportKeysResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_ImportKeysClient = grpc.ClientStreamingClient[StorageEntry, StorageImportKeysResponse]
+
+func (c *storageClient) DropKeys(ctx context.Context, in *StorageDropKeysRequest, opts ...grpc.CallOption) (*StorageDropKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageDropKeysResponse)
+	err := c.cc.Invoke(ctx, Storage_DropKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Drain(ctx context.Context, in *StorageDrainRequest, opts ...grpc.CallOption) (*StorageDrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageDrainResponse)
+	err := c.cc.Invoke(ctx, Storage_Drain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetRing(ctx context.Context, in *StorageSetRingRequest, opts ...grpc.CallOption) (*StorageSetRingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageSetRingResponse)
+	err := c.cc.Invoke(ctx, Storage_SetRing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GossipPing(ctx context.Context, in *StorageGossipPingRequest, opts ...grpc.CallOption) (*StorageGossipPingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageGossipPingResponse)
+	err := c.cc.Invoke(ctx, Storage_GossipPing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GossipPingReq(ctx context.Context, in *StorageGossipPingReqRequest, opts ...grpc.CallOption) (*StorageGossipPingReqResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageGossipPingReqResponse)
+	err := c.cc.Invoke(ctx, Storage_GossipPingReq_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GossipSync(ctx context.Context, in *StorageGossipSyncRequest, opts ...grpc.CallOption) (*StorageGossipSyncResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageGossipSyncResponse)
+	err := c.cc.Invoke(ctx, Storage_GossipSync_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MerkleTree(ctx context.Context, in *StorageMerkleTreeRequest, opts ...grpc.CallOption) (*StorageMerkleTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageMerkleTreeResponse)
+	err := c.cc.Invoke(ctx, Storage_MerkleTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) RepairExport(ctx context.Context, in *StorageRepairExportRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StorageEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[4], Storage_RepairExport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageRepairExportRequest, StorageEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_RepairExportClient = grpc.ServerStreamingClient[StorageEntry]
+
+func (c *storageClient) RepairImport(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StorageEntry, StorageRepairImportResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[5], Storage_RepairImport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StorageEntry, StorageRepairImportResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_RepairImportClient = grpc.ClientStreamingClient[StorageEntry, StorageRepairImportResponse]
+
+func (c *storageClient) Repair(ctx context.Context, in *StorageRepairRequest, opts ...grpc.CallOption) (*StorageRepairResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageRepairResponse)
+	err := c.cc.Invoke(ctx, Storage_Repair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetSiblings(ctx context.Context, in *StorageGetSiblingsRequest, opts ...grpc.CallOption) (*StorageGetSiblingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageGetSiblingsResponse)
+	err := c.cc.Invoke(ctx, Storage_GetSiblings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) PutSibling(ctx context.Context, in *StoragePutSiblingRequest, opts ...grpc.CallOption) (*StoragePutSiblingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StoragePutSiblingResponse)
+	err := c.cc.Invoke(ctx, Storage_PutSibling_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MergeSibling(ctx context.Context, in *StorageMergeSiblingRequest, opts ...grpc.CallOption) (*StorageMergeSiblingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageMergeSiblingResponse)
+	err := c.cc.Invoke(ctx, Storage_MergeSibling_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StorageServer is the server API for Storage service.
 // All implementations must embed UnimplementedStorageServer
 // for forward compatibility.
@@ -91,117 +624,948 @@ type StorageServer interface {
 	Put(context.Context, *StoragePutRequest) (*StoragePutResponse, error)
 	Update(context.Context, *StorageUpdateRequest) (*StorageUpdateResponse, error)
 	Delete(context.Context, *StorageDeleteRequest) (*StorageDeleteResponse, error)
+	Stats(context.Context, *StorageStatsRequest) (*StorageStatsResponse, error)
+	Range(context.Context, *StorageRangeRequest) (*StorageRangeResponse, error)
+	QueryIndex(context.Context, *StorageQueryIndexRequest) (*StorageQueryIndexResponse, error)
+	Increment(context.Context, *StorageIncrementRequest) (*StorageIncrementResponse, error)
+	Decrement(context.Context, *StorageDecrementRequest) (*StorageDecrementResponse, error)
+	// Lists, sets and hashes. Operations on a key holding another type fail with FailedPrecondition (WRONGTYPE).
+	ListPush(context.Context, *StorageListPushRequest) (*StorageListPushResponse, error)
+	ListPop(context.Context, *StorageListPopRequest) (*StorageListPopResponse, error)
+	ListRange(context.Context, *StorageListRangeRequest) (*StorageListRangeResponse, error)
+	SetAdd(context.Context, *StorageSetAddRequest) (*StorageSetAddResponse, error)
+	SetRemove(context.Context, *StorageSetRemoveRequest) (*StorageSetRemoveResponse, error)
+	SetMembers(context.Context, *StorageSetMembersRequest) (*StorageSetMembersResponse, error)
+	SetIsMember(context.Context, *StorageSetIsMemberRequest) (*StorageSetIsMemberResponse, error)
+	HashSet(context.Context, *StorageHashSetRequest) (*StorageHashSetResponse, error)
+	HashGet(context.Context, *StorageHashGetRequest) (*StorageHashGetResponse, error)
+	HashDelete(context.Context, *StorageHashDeleteRequest) (*StorageHashDeleteResponse, error)
+	// Streams changes of a key or key prefix, fails with OutOfRange when StartLSN is no longer retained
+	Watch(*StorageWatchRequest, grpc.ServerStreamingServer[StorageWatchEvent]) error
+	// Streams every mutation of the node in LSN order for change data capture
+	StreamChanges(*StorageStreamChangesRequest, grpc.ServerStreamingServer[StorageChangeEvent]) error
+	// Leases delete their keys unless kept alive, unknown or expired leases fail with NotFound
+	LeaseGrant(context.Context, *StorageLeaseGrantRequest) (*StorageLeaseGrantResponse, error)
+	LeaseKeepAlive(context.Context, *StorageLeaseKeepAliveRequest) (*StorageLeaseKeepAliveResponse, error)
+	LeaseRevoke(context.Context, *StorageLeaseRevokeRequest) (*StorageLeaseRevokeResponse, error)
+	// Two-phase commit of the part of a transaction owned by this node. Prepare locks the keys and
+	// evaluates the compares, a key locked by another transaction fails with Aborted.
+	TxnPrepare(context.Context, *StorageTxnPrepareRequest) (*StorageTxnPrepareResponse, error)
+	TxnCommit(context.Context, *StorageTxnCommitRequest) (*StorageTxnCommitResponse, error)
+	TxnAbort(context.Context, *StorageTxnAbortRequest) (*StorageTxnAbortResponse, error)
+	// Evaluates the compares and applies Then or Else atomically, every key has to live on this node
+	Txn(context.Context, *StorageTxnRequest) (*StorageTxnResponse, error)
+	// Moving keys between nodes when the ring changes. TransferKeys streams the keys of the ranges,
+	// ImportKeys writes them unless the key is already present and DropKeys deletes the old copies.
+	TransferKeys(*StorageTransferKeysRequest, grpc.ServerStreamingServer[StorageEntry]) error
+	ImportKeys(grpc.ClientStreamingServer[StorageEntry, StorageImportKeysResponse]) error
+	DropKeys(context.Context, *StorageDropKeysRequest) (*StorageDropKeysResponse, error)
+	// Shuts the node down once its keys moved away, fails with FailedPrecondition while it holds keys
+	Drain(context.Context, *StorageDrainRequest) (*StorageDrainResponse, error)
+	// Ownership of the keys pushed by the coordinator at every ring change. Once set the node
+	// rejects the keys it does not own with FailedPrecondition and a StorageWrongOwner detail.
+	SetRing(context.Context, *StorageSetRingRequest) (*StorageSetRingResponse, error)
+	// SWIM gossip among the nodes. A ping is acked with the updates of the receiver, a ping request
+	// asks the receiver to ping Target on behalf of a node that could not reach it. Both carry
+	// membership updates. GossipSync exchanges the full membership, an empty request only reads it.
+	GossipPing(context.Context, *StorageGossipPingRequest) (*StorageGossipPingResponse, error)
+	GossipPingReq(context.Context, *StorageGossipPingReqRequest) (*StorageGossipPingReqResponse, error)
+	GossipSync(context.Context, *StorageGossipSyncRequest) (*StorageGossipSyncResponse, error)
+	// Anti-entropy between replicas. MerkleTree returns digests of a level of the Merkle tree of
+	// the keys in the ranges, RepairExport streams the keys and tombstones of leaves of the tree and
+	// RepairImport keeps the newer of each streamed key and the local one. Repair compares the tree
+	// of the node with the one of Peer level by level and exchanges the keys of the leaves that differ.
+	MerkleTree(context.Context, *StorageMerkleTreeRequest) (*StorageMerkleTreeResponse, error)
+	RepairExport(*StorageRepairExportRequest, grpc.ServerStreamingServer[StorageEntry]) error
+	RepairImport(grpc.ClientStreamingServer[StorageEntry, StorageRepairImportResponse]) error
+	Repair(context.Context, *StorageRepairRequest) (*StorageRepairResponse, error)
+	// Keys versioned with vector clocks keep concurrent writes as siblings. GetSiblings returns
+	// them, a string written without vector clocks is a single sibling with an empty dot. PutSibling
+	// writes a value as the coordinator of the write, it replaces the siblings covered by Context.
+	// MergeSibling adds a sibling written by another replica.
+	GetSiblings(context.Context, *StorageGetSiblingsRequest) (*StorageGetSiblingsResponse, error)
+	PutSibling(context.Context, *StoragePutSiblingRequest) (*StoragePutSiblingResponse, error)
+	MergeSibling(context.Context, *StorageMergeSiblingRequest) (*StorageMergeSiblingResponse, error)
+	mustEmbedUnimplementedStorageServer()
+}
+
+// UnimplementedStorageServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStorageServer struct{}
+
+func (UnimplementedStorageServer) Get(context.Context, *StorageGetRequest) (*StorageGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedStorageServer) Put(context.Context, *StoragePutRequest) (*StoragePutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedStorageServer) Update(context.Context, *StorageUpdateRequest) (*StorageUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedStorageServer) Delete(context.Context, *StorageDeleteRequest) (*StorageDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedStorageServer) Stats(context.Context, *StorageStatsRequest) (*StorageStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedStorageServer) Range(context.Context, *StorageRangeRequest) (*StorageRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Range not implemented")
+}
+func (UnimplementedStorageServer) QueryIndex(context.Context, *StorageQueryIndexRequest) (*StorageQueryIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryIndex not implemented")
+}
+func (UnimplementedStorageServer) Increment(context.Context, *StorageIncrementRequest) (*StorageIncrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Increment not implemented")
+}
+func (UnimplementedStorageServer) Decrement(context.Context, *StorageDecrementRequest) (*StorageDecrementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decrement not implemented")
+}
+func (UnimplementedStorageServer) ListPush(context.Context, *StorageListPushRequest) (*StorageListPushResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPush not implemented")
+}
+func (UnimplementedStorageServer) ListPop(context.Context, *StorageListPopRequest) (*StorageListPopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPop not implemented")
+}
+func (UnimplementedStorageServer) ListRange(context.Context, *StorageListRangeRequest) (*StorageListRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRange not implemented")
+}
+func (UnimplementedStorageServer) SetAdd(context.Context, *StorageSetAddRequest) (*StorageSetAddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAdd not implemented")
+}
+func (UnimplementedStorageServer) SetRemove(context.Context, *StorageSetRemoveRequest) (*StorageSetRemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRemove not implemented")
+}
+func (UnimplementedStorageServer) SetMembers(context.Context, *StorageSetMembersRequest) (*StorageSetMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMembers not implemented")
+}
+func (UnimplementedStorageServer) SetIsMember(context.Context, *StorageSetIsMemberRequest) (*StorageSetIsMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetIsMember not implemented")
+}
+func (UnimplementedStorageServer) HashSet(context.Context, *StorageHashSetRequest) (*StorageHashSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashSet not implemented")
+}
+func (UnimplementedStorageServer) HashGet(context.Context, *StorageHashGetRequest) (*StorageHashGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashGet not implemented")
+}
+func (UnimplementedStorageServer) HashDelete(context.Context, *StorageHashDeleteRequest) (*StorageHashDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HashDelete not implemented")
+}
+func (UnimplementedStorageServer) Watch(*StorageWatchRequest, grpc.ServerStreamingServer[StorageWatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedStorageServer) StreamChanges(*StorageStreamChangesRequest, grpc.ServerStreamingServer[StorageChangeEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamChanges not implemented")
+}
+func (UnimplementedStorageServer) LeaseGrant(context.Context, *StorageLeaseGrantRequest) (*StorageLeaseGrantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseGrant not implemented")
+}
+func (UnimplementedStorageServer) LeaseKeepAlive(context.Context, *StorageLeaseKeepAliveRequest) (*StorageLeaseKeepAliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseKeepAlive not implemented")
+}
+func (UnimplementedStorageServer) LeaseRevoke(context.Context, *StorageLeaseRevokeRequest) (*StorageLeaseRevokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseRevoke not implemented")
+}
+func (UnimplementedStorageServer) TxnPrepare(context.Context, *StorageTxnPrepareRequest) (*StorageTxnPrepareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnPrepare not implemented")
+}
+func (UnimplementedStorageServer) TxnCommit(context.Context, *StorageTxnCommitRequest) (*StorageTxnCommitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnCommit not implemented")
+}
+func (UnimplementedStorageServer) TxnAbort(context.Context, *StorageTxnAbortRequest) (*StorageTxnAbortResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnAbort not implemented")
+}
+func (UnimplementedStorageServer) Txn(context.Context, *StorageTxnRequest) (*StorageTxnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
+func (UnimplementedStorageServer) TransferKeys(*StorageTransferKeysRequest, grpc.ServerStreamingServer[StorageEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method TransferKeys not implemented")
+}
+func (UnimplementedStorageServer) ImportKeys(grpc.ClientStreamingServer[StorageEntry, StorageImportKeysResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportKeys not implemented")
+}
+func (UnimplementedStorageServer) DropKeys(context.Context, *StorageDropKeysRequest) (*StorageDropKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropKeys not implemented")
+}
+func (UnimplementedStorageServer) Drain(context.Context, *StorageDrainRequest) (*StorageDrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedStorageServer) SetRing(context.Context, *StorageSetRingRequest) (*StorageSetRingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRing not implemented")
+}
+func (UnimplementedStorageServer) GossipPing(context.Context, *StorageGossipPingRequest) (*StorageGossipPingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GossipPing not implemented")
+}
+func (UnimplementedStorageServer) GossipPingReq(context.Context, *StorageGossipPingReqRequest) (*StorageGossipPingReqResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GossipPingReq not implemented")
+}
+func (UnimplementedStorageServer) GossipSync(context.Context, *StorageGossipSyncRequest) (*StorageGossipSyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GossipSync not implemented")
+}
+func (UnimplementedStorageServer) MerkleTree(context.Context, *StorageMerkleTreeRequest) (*StorageMerkleTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MerkleTree not implemented")
+}
+func (UnimplementedStorageServer) RepairExport(*StorageRepairExportRequest, grpc.ServerStreamingServer[StorageEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method RepairExport not implemented")
+}
+func (UnimplementedStorageServer) RepairImport(grpc.ClientStreamingServer[StorageEntry, StorageRepairImportResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RepairImport not implemented")
+}
+func (UnimplementedStorageServer) Repair(context.Context, *StorageRepairRequest) (*StorageRepairResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Repair not implemented")
+}
+func (UnimplementedStorageServer) GetSiblings(context.Context, *StorageGetSiblingsRequest) (*StorageGetSiblingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSiblings not implemented")
+}
+func (UnimplementedStorageServer) PutSibling(context.Context, *StoragePutSiblingRequest) (*StoragePutSiblingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutSibling not implemented")
+}
+func (UnimplementedStorageServer) MergeSibling(context.Context, *StorageMergeSiblingRequest) (*StorageMergeSiblingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeSibling not implemented")
+}
+func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
+func (UnimplementedStorageServer) testEmbeddedByValue()                 {}
+
+// UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageServer will
+// result in compilation errors.
+type UnsafeStorageServer interface {
 	mustEmbedUnimplementedStorageServer()
 }
 
-// UnimplementedStorageServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedStorageServer struct{}
+func RegisterStorageServer(s grpc.ServiceRegistrar, srv StorageServer) {
+	// If the following call pancis, it indicates UnimplementedStorageServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Storage_ServiceDesc, srv)
+}
+
+func _Storage_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Get(ctx, req.(*StorageGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoragePutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Put_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Put(ctx, req.(*StoragePutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Update(ctx, req.(*StorageUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Delete(ctx, req.(*StorageDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Stats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Stats(ctx, req.(*StorageStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Range_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Range(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Range_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Range(ctx, req.(*StorageRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_QueryIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageQueryIndexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).QueryIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_QueryIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).QueryIndex(ctx, req.(*StorageQueryIndexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Increment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageIncrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Increment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Increment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Increment(ctx, req.(*StorageIncrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Decrement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageDecrementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Decrement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Decrement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Decrement(ctx, req.(*StorageDecrementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageListPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ListPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ListPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ListPush(ctx, req.(*StorageListPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageListPopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ListPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ListPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ListPop(ctx, req.(*StorageListPopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageListRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ListRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_ListRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ListRange(ctx, req.(*StorageListRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageSetAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetAdd(ctx, req.(*StorageSetAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetRemove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageSetRemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetRemove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetRemove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetRemove(ctx, req.(*StorageSetRemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageSetMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetMembers(ctx, req.(*StorageSetMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetIsMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageSetIsMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetIsMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetIsMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetIsMember(ctx, req.(*StorageSetIsMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_HashSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageHashSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).HashSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_HashSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).HashSet(ctx, req.(*StorageHashSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_HashGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageHashGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).HashGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_HashGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).HashGet(ctx, req.(*StorageHashGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_HashDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageHashDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).HashDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_HashDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).HashDelete(ctx, req.(*StorageHashDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageWatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).Watch(m, &grpc.GenericServerStream[StorageWatchRequest, StorageWatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_WatchServer = grpc.ServerStreamingServer[StorageWatchEvent]
+
+func _Storage_StreamChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageStreamChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).StreamChanges(m, &grpc.GenericServerStream[StorageStreamChangesRequest, StorageChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_StreamChangesServer = grpc.ServerStreamingServer[StorageChangeEvent]
+
+func _Storage_LeaseGrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageLeaseGrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).LeaseGrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_LeaseGrant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).LeaseGrant(ctx, req.(*StorageLeaseGrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_LeaseKeepAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageLeaseKeepAliveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).LeaseKeepAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_LeaseKeepAlive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).LeaseKeepAlive(ctx, req.(*StorageLeaseKeepAliveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_LeaseRevoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageLeaseRevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).LeaseRevoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_LeaseRevoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).LeaseRevoke(ctx, req.(*StorageLeaseRevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_TxnPrepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageTxnPrepareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).TxnPrepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_TxnPrepare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).TxnPrepare(ctx, req.(*StorageTxnPrepareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_TxnCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageTxnCommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).TxnCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_TxnCommit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).TxnCommit(ctx, req.(*StorageTxnCommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_TxnAbort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageTxnAbortRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).TxnAbort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_TxnAbort_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).TxnAbort(ctx, req.(*StorageTxnAbortRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedStorageServer) Get(context.Context, *StorageGetRequest) (*StorageGetResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+func _Storage_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageTxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Txn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Txn(ctx, req.(*StorageTxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedStorageServer) Put(context.Context, *StoragePutRequest) (*StoragePutResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+
+func _Storage_TransferKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageTransferKeysRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).TransferKeys(m, &grpc.GenericServerStream[StorageTransferKeysRequest, StorageEntry]{ServerStream: stream})
 }
-func (UnimplementedStorageServer) Update(context.Context, *StorageUpdateRequest) (*StorageUpdateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_TransferKeysServer = grpc.ServerStreamingServer[StorageEntry]
+
+func _Storage_ImportKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).ImportKeys(&grpc.GenericServerStream[StorageEntry, StorageImportKeysResponse]{ServerStream: stream})
 }
-func (UnimplementedStorageServer) Delete(context.Context, *StorageDeleteRequest) (*StorageDeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_ImportKeysServer = grpc.ClientStreamingServer[StorageEntry, StorageImportKeysResponse]
+
+func _Storage_DropKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageDropKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).DropKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_DropKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).DropKeys(ctx, req.(*StorageDropKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
-func (UnimplementedStorageServer) testEmbeddedByValue()                 {}
 
-// UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to StorageServer will
-// result in compilation errors.
-type UnsafeStorageServer interface {
-	mustEmbedUnimplementedStorageServer()
+func _Storage_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageDrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Drain(ctx, req.(*StorageDrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterStorageServer(s grpc.ServiceRegistrar, srv StorageServer) {
-	// If the following call pancis, it indicates UnimplementedStorageServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _Storage_SetRing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageSetRingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&Storage_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(StorageServer).SetRing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_SetRing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetRing(ctx, req.(*StorageSetRingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StorageGetRequest)
+func _Storage_GossipPing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGossipPingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Get(ctx, in)
+		return srv.(StorageServer).GossipPing(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Storage_Get_FullMethodName,
+		FullMethod: Storage_GossipPing_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Get(ctx, req.(*StorageGetRequest))
+		return srv.(StorageServer).GossipPing(ctx, req.(*StorageGossipPingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StoragePutRequest)
+func _Storage_GossipPingReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGossipPingReqRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Put(ctx, in)
+		return srv.(StorageServer).GossipPingReq(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Storage_Put_FullMethodName,
+		FullMethod: Storage_GossipPingReq_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Put(ctx, req.(*StoragePutRequest))
+		return srv.(StorageServer).GossipPingReq(ctx, req.(*StorageGossipPingReqRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StorageUpdateRequest)
+func _Storage_GossipSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGossipSyncRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Update(ctx, in)
+		return srv.(StorageServer).GossipSync(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Storage_Update_FullMethodName,
+		FullMethod: Storage_GossipSync_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Update(ctx, req.(*StorageUpdateRequest))
+		return srv.(StorageServer).GossipSync(ctx, req.(*StorageGossipSyncRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StorageDeleteRequest)
+func _Storage_MerkleTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageMerkleTreeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Delete(ctx, in)
+		return srv.(StorageServer).MerkleTree(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Storage_Delete_FullMethodName,
+		FullMethod: Storage_MerkleTree_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Delete(ctx, req.(*StorageDeleteRequest))
+		return srv.(StorageServer).MerkleTree(ctx, req.(*StorageMerkleTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_RepairExport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StorageRepairExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).RepairExport(m, &grpc.GenericServerStream[StorageRepairExportRequest, StorageEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_RepairExportServer = grpc.ServerStreamingServer[StorageEntry]
+
+func _Storage_RepairImport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).RepairImport(&grpc.GenericServerStream[StorageEntry, StorageRepairImportResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Storage_RepairImportServer = grpc.ClientStreamingServer[StorageEntry, StorageRepairImportResponse]
+
+func _Storage_Repair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageRepairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Repair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_Repair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Repair(ctx, req.(*StorageRepairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetSiblings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGetSiblingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetSiblings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_GetSiblings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetSiblings(ctx, req.(*StorageGetSiblingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_PutSibling_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoragePutSiblingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).PutSibling(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_PutSibling_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).PutSibling(ctx, req.(*StoragePutSiblingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MergeSibling_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageMergeSiblingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MergeSibling(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Storage_MergeSibling_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MergeSibling(ctx, req.(*StorageMergeSiblingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -229,8 +1593,171 @@ var Storage_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Storage_Delete_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _Storage_Stats_Handler,
+		},
+		{
+			MethodName: "Range",
+			Handler:    _Storage_Range_Handler,
+		},
+		{
+			MethodName: "QueryIndex",
+			Handler:    _Storage_QueryIndex_Handler,
+		},
+		{
+			MethodName: "Increment",
+			Handler:    _Storage_Increment_Handler,
+		},
+		{
+			MethodName: "Decrement",
+			Handler:    _Storage_Decrement_Handler,
+		},
+		{
+			MethodName: "ListPush",
+			Handler:    _Storage_ListPush_Handler,
+		},
+		{
+			MethodName: "ListPop",
+			Handler:    _Storage_ListPop_Handler,
+		},
+		{
+			MethodName: "ListRange",
+			Handler:    _Storage_ListRange_Handler,
+		},
+		{
+			MethodName: "SetAdd",
+			Handler:    _Storage_SetAdd_Handler,
+		},
+		{
+			MethodName: "SetRemove",
+			Handler:    _Storage_SetRemove_Handler,
+		},
+		{
+			MethodName: "SetMembers",
+			Handler:    _Storage_SetMembers_Handler,
+		},
+		{
+			MethodName: "SetIsMember",
+			Handler:    _Storage_SetIsMember_Handler,
+		},
+		{
+			MethodName: "HashSet",
+			Handler:    _Storage_HashSet_Handler,
+		},
+		{
+			MethodName: "HashGet",
+			Handler:    _Storage_HashGet_Handler,
+		},
+		{
+			MethodName: "HashDelete",
+			Handler:    _Storage_HashDelete_Handler,
+		},
+		{
+			MethodName: "LeaseGrant",
+			Handler:    _Storage_LeaseGrant_Handler,
+		},
+		{
+			MethodName: "LeaseKeepAlive",
+			Handler:    _Storage_LeaseKeepAlive_Handler,
+		},
+		{
+			MethodName: "LeaseRevoke",
+			Handler:    _Storage_LeaseRevoke_Handler,
+		},
+		{
+			MethodName: "TxnPrepare",
+			Handler:    _Storage_TxnPrepare_Handler,
+		},
+		{
+			MethodName: "TxnCommit",
+			Handler:    _Storage_TxnCommit_Handler,
+		},
+		{
+			MethodName: "TxnAbort",
+			Handler:    _Storage_TxnAbort_Handler,
+		},
+		{
+			MethodName: "Txn",
+			Handler:    _Storage_Txn_Handler,
+		},
+		{
+			MethodName: "DropKeys",
+			Handler:    _Storage_DropKeys_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _Storage_Drain_Handler,
+		},
+		{
+			MethodName: "SetRing",
+			Handler:    _Storage_SetRing_Handler,
+		},
+		{
+			MethodName: "GossipPing",
+			Handler:    _Storage_GossipPing_Handler,
+		},
+		{
+			MethodName: "GossipPingReq",
+			Handler:    _Storage_GossipPingReq_Handler,
+		},
+		{
+			MethodName: "GossipSync",
+			Handler:    _Storage_GossipSync_Handler,
+		},
+		{
+			MethodName: "MerkleTree",
+			Handler:    _Storage_MerkleTree_Handler,
+		},
+		{
+			MethodName: "Repair",
+			Handler:    _Storage_Repair_Handler,
+		},
+		{
+			MethodName: "GetSiblings",
+			Handler:    _Storage_GetSiblings_Handler,
+		},
+		{
+			MethodName: "PutSibling",
+			Handler:    _Storage_PutSibling_Handler,
+		},
+		{
+			MethodName: "MergeSibling",
+			Handler:    _Storage_MergeSibling_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Storage_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamChanges",
+			Handler:       _Storage_StreamChanges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TransferKeys",
+			Handler:       _Storage_TransferKeys_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportKeys",
+			Handler:       _Storage_ImportKeys_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RepairExport",
+			Handler:       _Storage_RepairExport_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RepairImport",
+			Handler:       _Storage_RepairImport_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/node.proto",
 }
 
@@ -0,0 +1,228 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminServer serves the Coordinator service used to administer the cluster
+type AdminServer struct {
+	pb.UnimplementedCoordinatorServer
+	coordinator *Coordinator
+}
+
+func toAdminStatus(err error) error {
+	// Forwarded to the leader, it already mapped the error
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, ErrNodeExists) {
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	}
+	if errors.Is(err, ErrUnknownNode) {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	if errors.Is(err, ErrLastNode) || errors.Is(err, ErrMigrating) || errors.Is(err, ErrDrainIncomplete) || errors.Is(err, ErrNotRing) {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return status.Errorf(codes.Unavailable, "%v", err)
+}
+
+func (s *AdminServer) AddNode(ctx context.Context, request *pb.CoordinatorAddNodeRequest) (*pb.CoordinatorAddNodeResponse, error) {
+	if nil == request || nil == request.Node {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.Node.NodeID == "" || request.Node.Host == "" || request.Node.Port == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Node ID, host and port are required")
+	}
+
+	log.Printf("Received AddNode request: Node[%s]/Address[%s:%d]", request.Node.NodeID, request.Node.Host, request.Node.Port)
+
+	if err := s.coordinator.AddNode(request.Node.NodeID, Node{Host: request.Node.Host, Port: request.Node.Port, Weight: int(request.Node.Weight)}); err != nil {
+		return nil, toAdminStatus(err)
+	}
+	return &pb.CoordinatorAddNodeResponse{}, nil
+}
+
+func (s *AdminServer) RemoveNode(ctx context.Context, request *pb.CoordinatorRemoveNodeRequest) (*pb.CoordinatorRemoveNodeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received RemoveNode request: Node[%s]", request.NodeID)
+
+	if err := s.coordinator.RemoveNode(request.NodeID); err != nil {
+		return nil, toAdminStatus(err)
+	}
+	return &pb.CoordinatorRemoveNodeResponse{}, nil
+}
+
+// toCoordinatorNodes converts the members sorted by ID
+func toCoordinatorNodes(members map[string]Node) []*pb.CoordinatorNode {
+	nodes := make([]*pb.CoordinatorNode, 0, len(members))
+	for nodeID, info := range members {
+		nodes = append(nodes, &pb.CoordinatorNode{NodeID: nodeID, Host: info.Host, Port: info.Port, Leaving: info.Leaving, Weight: int64(info.Weight)})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeID < nodes[j].NodeID
+	})
+	return nodes
+}
+
+func (s *AdminServer) ListNodes(ctx context.Context, request *pb.CoordinatorListNodesRequest) (*pb.CoordinatorListNodesResponse, error) {
+	metadata := s.coordinator.Metadata()
+	return &pb.CoordinatorListNodesResponse{
+		Nodes:             toCoordinatorNodes(metadata.Nodes),
+		RingVersion:       metadata.RingVersion,
+		VirtualNodes:      int64(metadata.VirtualNodes),
+		ReplicationFactor: int64(metadata.ReplicationFactor),
+		Leader:            metadata.Leader,
+	}, nil
+}
+
+func (s *AdminServer) DrainNode(ctx context.Context, request *pb.CoordinatorDrainNodeRequest) (*pb.CoordinatorDrainNodeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received DrainNode request: Node[%s]", request.NodeID)
+
+	if err := s.coordinator.DrainNode(ctx, request.NodeID); err != nil {
+		return nil, toAdminStatus(err)
+	}
+	return &pb.CoordinatorDrainNodeResponse{}, nil
+}
+
+func (s *AdminServer) SetNodeWeight(ctx context.Context, request *pb.CoordinatorSetNodeWeightRequest) (*pb.CoordinatorSetNodeWeightResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.Weight < 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "Weight must be at least 1")
+	}
+
+	log.Printf("Received SetNodeWeight request: Node[%s]/Weight[%d]", request.NodeID, request.Weight)
+
+	if err := s.coordinator.SetNodeWeight(request.NodeID, int(request.Weight)); err != nil {
+		return nil, toAdminStatus(err)
+	}
+	return &pb.CoordinatorSetNodeWeightResponse{}, nil
+}
+
+// serveAdmin serves the Coordinator service, and the Raft service of a replicated coordinator,
+// on port until the returned server is stopped
+func serveAdmin(coordinator *Coordinator, port uint64) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("Error starting up the admin server on port[%d] : %w", port, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterCoordinatorServer(grpcServer, &AdminServer{coordinator: coordinator})
+	if nil != coordinator.raft {
+		pb.RegisterRaftServer(grpcServer, coordinator.raft)
+	}
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Printf("Admin server failed : %v", err)
+		}
+	}()
+	return grpcServer, nil
+}
+
+func (s *AdminServer) GetRing(ctx context.Context, request *pb.CoordinatorGetRingRequest) (*pb.CoordinatorGetRingResponse, error) {
+	ring, placement, version := s.coordinator.Routing()
+	metadata := s.coordinator.Metadata()
+
+	response := &pb.CoordinatorGetRingResponse{
+		Ring:              ring,
+		Nodes:             toCoordinatorNodes(metadata.Nodes),
+		ReplicationFactor: int64(metadata.ReplicationFactor),
+		Placement:         placement,
+		RingVersion:       version,
+	}
+	for _, keyspace := range s.coordinator.Keyspaces() {
+		response.Keyspaces = append(response.Keyspaces, &pb.CoordinatorKeyspace{Prefix: keyspace.Prefix, VectorClocks: keyspace.Versioning == VersioningVectorClock})
+	}
+	return response, nil
+}
+
+func (s *AdminServer) GetOwner(ctx context.Context, request *pb.CoordinatorGetOwnerRequest) (*pb.CoordinatorGetOwnerResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	owner, err := s.coordinator.KeyOwner(request.Key)
+	if err != nil {
+		return nil, toAdminStatus(err)
+	}
+	response := &pb.CoordinatorGetOwnerResponse{
+		Position:    owner.Position,
+		Owner:       owner.Owner,
+		Replicas:    owner.Replicas,
+		RingVersion: owner.RingVersion,
+	}
+	if nil != owner.Token {
+		response.Token, response.TokenNode = owner.Token.Position, owner.Token.Node
+	}
+	return response, nil
+}
+
+func (s *AdminServer) GetBalance(ctx context.Context, request *pb.CoordinatorGetBalanceRequest) (*pb.CoordinatorGetBalanceResponse, error) {
+	balances, version := s.coordinator.Balance(ctx)
+
+	response := &pb.CoordinatorGetBalanceResponse{RingVersion: version}
+	for _, balance := range balances {
+		node := &pb.CoordinatorNodeBalance{
+			NodeID:   balance.NodeID,
+			Weight:   int64(balance.Weight),
+			Share:    balance.Share,
+			Keys:     balance.Keys,
+			KeyShare: balance.KeyShare,
+		}
+		if nil != balance.Err {
+			node.Error = balance.Err.Error()
+		}
+		response.Nodes = append(response.Nodes, node)
+	}
+	return response, nil
+}
+
+func (s *AdminServer) Repair(ctx context.Context, request *pb.CoordinatorRepairRequest) (*pb.CoordinatorRepairResponse, error) {
+	log.Printf("Received Repair request")
+
+	results, err := s.coordinator.Repair(ctx)
+	if err != nil {
+		return nil, toAdminStatus(err)
+	}
+	response := &pb.CoordinatorRepairResponse{}
+	for _, result := range results {
+		res := &pb.CoordinatorRepairResult{
+			Node:     result.Node,
+			Peer:     result.Peer,
+			Ranges:   int64(result.Ranges),
+			Leaves:   result.Leaves,
+			Received: result.Received,
+			Sent:     result.Sent,
+		}
+		if nil != result.Err {
+			res.Error = result.Err.Error()
+		}
+		response.Results = append(response.Results, res)
+	}
+	return response, nil
+}
@@ -0,0 +1,475 @@
+package coordinator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const cdcOffsetFlushSeconds = 1
+const cdcRetrySeconds = 1
+
+// ChangeEvent is a single line of the CDC feed. Events of a node are written in LSN order.
+type ChangeEvent struct {
+	NodeID    string            `json:"node_id"`
+	LSN       uint64            `json:"lsn"`
+	Operation string            `json:"operation"`
+	Key       string            `json:"key"`
+	Value     []byte            `json:"value,omitempty"`
+	ExpiresAt int64             `json:"expires_at,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Items     [][]byte          `json:"items,omitempty"`
+	Fields    map[string][]byte `json:"fields,omitempty"`
+	Count     uint32            `json:"count,omitempty"` // Values removed by LPOP and RPOP
+	Group     []ChangeEvent     `json:"group,omitempty"` // Writes of a TXN event, applied together
+}
+
+func toChangeEvent(nodeID string, event *pb.StorageChangeEvent) ChangeEvent {
+	changeEvent := ChangeEvent{
+		NodeID:    nodeID,
+		LSN:       event.LSN,
+		Operation: event.Operation,
+		Key:       event.Key,
+		Value:     event.Value,
+		ExpiresAt: event.ExpiresAt,
+		Type:      event.Type,
+		Items:     event.Items,
+		Fields:    event.Fields,
+		Count:     event.Count,
+	}
+	for _, change := range event.Group {
+		changeEvent.Group = append(changeEvent.Group, toChangeEvent(nodeID, change))
+	}
+	return changeEvent
+}
+
+// cdcSink is where the newline JSON events go
+type cdcSink interface {
+	Write(nodeID string, line []byte) error
+	Flush() error
+	Close() error
+}
+
+// fileSink appends the events of each node to <Directory>/<NodeID>.jsonl
+type fileSink struct {
+	mtx       sync.Mutex
+	directory string
+	files     map[string]*os.File
+	writers   map[string]*bufio.Writer
+}
+
+func (f *fileSink) Write(nodeID string, line []byte) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	writer, ok := f.writers[nodeID]
+	if !ok {
+		file, err := os.OpenFile(filepath.Join(f.directory, nodeID+".jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		f.files[nodeID] = file
+		writer = bufio.NewWriter(file)
+		f.writers[nodeID] = writer
+	}
+	if _, err := writer.Write(line); err != nil {
+		f.reset(nodeID)
+		return err
+	}
+	return nil
+}
+
+func (f *fileSink) Flush() error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for nodeID, writer := range f.writers {
+		err := writer.Flush()
+		if nil == err {
+			err = f.files[nodeID].Sync()
+		}
+		if err != nil {
+			f.reset(nodeID)
+			return err
+		}
+	}
+	return nil
+}
+
+// reset drops the file of a node and its buffered lines, it is opened again by the next write.
+// Caller must hold the lock.
+func (f *fileSink) reset(nodeID string) {
+	f.files[nodeID].Close()
+	delete(f.files, nodeID)
+	delete(f.writers, nodeID)
+}
+
+func (f *fileSink) Close() error {
+	err := f.Flush()
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, file := range f.files {
+		file.Close()
+	}
+	return err
+}
+
+// socketSink writes the events of every node to a local unix socket, redialing after errors
+type socketSink struct {
+	mtx    sync.Mutex
+	path   string
+	conn   net.Conn
+	writer *bufio.Writer
+}
+
+func (s *socketSink) Write(nodeID string, line []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if nil == s.conn {
+		conn, err := net.Dial("unix", s.path)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+		s.writer = bufio.NewWriter(conn)
+	}
+	if _, err := s.writer.Write(line); err != nil {
+		s.reset()
+		return err
+	}
+	return nil
+}
+
+func (s *socketSink) Flush() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if nil == s.conn {
+		return nil
+	}
+	if err := s.writer.Flush(); err != nil {
+		s.reset()
+		return err
+	}
+	return nil
+}
+
+func (s *socketSink) Close() error {
+	err := s.Flush()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.reset()
+	return err
+}
+
+// reset drops the connection. Caller must hold the lock.
+func (s *socketSink) reset() {
+	if nil != s.conn {
+		s.conn.Close()
+	}
+	s.conn, s.writer = nil, nil
+}
+
+// cdcCollector streams the changes of every node into the sink. Offsets are only saved once the
+// sink is flushed, so after a restart events are delivered at least once. A sink error drops the
+// buffered events, the streams are rewound to the saved offsets.
+type cdcCollector struct {
+	sink        cdcSink
+	offsetsFile string
+
+	mtx       sync.Mutex
+	offsets   map[string]uint64             // Node ID -> last LSN written to the sink
+	flushed   map[string]uint64             // Node ID -> last LSN saved in the offsets file
+	streams   map[string]context.CancelFunc // Node ID -> stream, cancelled when the node leaves
+	restarts  map[string]context.CancelFunc // Node ID -> current connection, cancelled to reconnect
+	failed    error                         // Last sink error, streams restart from the saved offset
+	rewinds   uint64                        // Sink errors so far, offsets taken before one are not saved
+	done      chan struct{}
+	waitGroup sync.WaitGroup
+}
+
+func newCDCCollector(config *Config) (*cdcCollector, error) {
+	collector := &cdcCollector{
+		offsetsFile: config.CDC.OffsetsFile,
+		offsets:     make(map[string]uint64),
+		flushed:     make(map[string]uint64),
+		streams:     make(map[string]context.CancelFunc),
+		restarts:    make(map[string]context.CancelFunc),
+		done:        make(chan struct{}),
+	}
+
+	switch config.CDC.Sink {
+	case "", "file":
+		if err := os.MkdirAll(config.CDC.Directory, 0755); err != nil {
+			return nil, fmt.Errorf("Error creating CDC directory : %w", err)
+		}
+		collector.sink = &fileSink{
+			directory: config.CDC.Directory,
+			files:     make(map[string]*os.File),
+			writers:   make(map[string]*bufio.Writer),
+		}
+	case "socket":
+		collector.sink = &socketSink{path: config.CDC.SocketPath}
+	default:
+		return nil, fmt.Errorf("Unknown CDC sink: %s", config.CDC.Sink)
+	}
+
+	if err := collector.loadOffsets(); err != nil {
+		return nil, err
+	}
+	return collector, nil
+}
+
+func (c *cdcCollector) loadOffsets() error {
+	if c.offsetsFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.offsetsFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading CDC offsets : %w", err)
+	}
+	if err := json.Unmarshal(data, &c.offsets); err != nil {
+		return fmt.Errorf("Error decoding CDC offsets : %w", err)
+	}
+	for nodeID, lsn := range c.offsets {
+		c.flushed[nodeID] = lsn
+	}
+	return nil
+}
+
+// saveOffsets flushes the sink and then atomically replaces the offsets file
+func (c *cdcCollector) saveOffsets() error {
+	// Offsets taken before the flush are covered by it
+	c.mtx.Lock()
+	offsets := make(map[string]uint64, len(c.offsets))
+	for nodeID, lsn := range c.offsets {
+		offsets[nodeID] = lsn
+	}
+	rewinds := c.rewinds
+	c.mtx.Unlock()
+
+	err := c.sink.Flush()
+	c.mtx.Lock()
+	if err != nil {
+		c.rewind(err)
+		c.mtx.Unlock()
+		return err
+	}
+	// A write failed since the offsets were taken, the events it dropped are not flushed
+	if c.rewinds != rewinds {
+		c.mtx.Unlock()
+		return c.failed
+	}
+	c.failed = nil
+	for nodeID, lsn := range offsets {
+		c.flushed[nodeID] = lsn
+	}
+	c.mtx.Unlock()
+
+	if c.offsetsFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(offsets)
+	if err != nil {
+		return err
+	}
+	tmp := c.offsetsFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.offsetsFile)
+}
+
+// rewind restarts every stream from its saved offset after a sink error, the events written since
+// the last flush may have been dropped. Caller must hold mtx.
+func (c *cdcCollector) rewind(err error) {
+	c.failed = err
+	c.rewinds++
+	for nodeID := range c.offsets {
+		c.offsets[nodeID] = c.flushed[nodeID]
+	}
+	for _, cancel := range c.restarts {
+		cancel()
+	}
+}
+
+// start streams from every node and saves the offsets periodically
+func (c *cdcCollector) start(coordinator *Coordinator) {
+	c.sync(coordinator)
+
+	c.waitGroup.Add(1)
+	go func() {
+		defer c.waitGroup.Done()
+		ticker := time.NewTicker(cdcOffsetFlushSeconds * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.saveOffsets(); err != nil {
+					log.Printf("Error saving CDC offsets : %v", err)
+				}
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// sync starts streams for new nodes and stops the ones of removed nodes, called after membership changes
+func (c *cdcCollector) sync(coordinator *Coordinator) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	nodes := make(map[string]bool)
+	for _, nodeID := range coordinator.nodeIDs() {
+		nodes[nodeID] = true
+	}
+
+	for nodeID, cancel := range c.streams {
+		if !nodes[nodeID] {
+			cancel()
+			delete(c.streams, nodeID)
+		}
+	}
+	for nodeID := range nodes {
+		if _, ok := c.streams[nodeID]; ok {
+			continue
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		c.streams[nodeID] = cancel
+		c.waitGroup.Add(1)
+		go c.run(ctx, coordinator, nodeID)
+	}
+}
+
+// stop closes the streams, saves the offsets and closes the sink
+func (c *cdcCollector) stop() {
+	c.mtx.Lock()
+	for nodeID, cancel := range c.streams {
+		cancel()
+		delete(c.streams, nodeID)
+	}
+	c.mtx.Unlock()
+
+	close(c.done)
+	c.waitGroup.Wait()
+
+	if err := c.saveOffsets(); err != nil {
+		log.Printf("Error saving CDC offsets : %v", err)
+	}
+	c.sink.Close()
+}
+
+// run streams the changes of a node from its saved offset, reconnecting until ctx is cancelled
+func (c *cdcCollector) run(ctx context.Context, coordinator *Coordinator, nodeID string) {
+	defer c.waitGroup.Done()
+
+	for {
+		node, ok := coordinator.node(nodeID)
+		if !ok {
+			return
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		c.mtx.Lock()
+		c.restarts[nodeID] = cancel
+		request := &pb.StorageStreamChangesRequest{}
+		if lsn, ok := c.offsets[nodeID]; ok {
+			request.StartLSN = lsn + 1
+		}
+		c.mtx.Unlock()
+
+		err := c.consume(streamCtx, node, nodeID, request)
+		cancel()
+		c.mtx.Lock()
+		delete(c.restarts, nodeID)
+		c.mtx.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if status.Code(err) == codes.OutOfRange {
+			log.Printf("CDC Node[%v] : %v, changes were lost, continuing from the latest LSN", nodeID, err)
+			c.mtx.Lock()
+			delete(c.offsets, nodeID)
+			c.mtx.Unlock()
+		} else {
+			log.Printf("CDC Node[%v] stream closed : %v, reconnecting", nodeID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cdcRetrySeconds * time.Second):
+		}
+	}
+}
+
+func (c *cdcCollector) consume(ctx context.Context, node *NodeConnection, nodeID string, request *pb.StorageStreamChangesRequest) error {
+	stream, err := node.client.StreamChanges(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(toChangeEvent(nodeID, event))
+		if err != nil {
+			return err
+		}
+
+		// A sink error cancels ctx under the lock after rewinding the offsets, which must not move
+		// forward again. Writing under the lock keeps a flush from saving the events a failed
+		// write dropped before they are rewound.
+		c.mtx.Lock()
+		if ctx.Err() != nil {
+			c.mtx.Unlock()
+			return ctx.Err()
+		}
+		if err := c.sink.Write(nodeID, append(data, '\n')); err != nil {
+			c.rewind(err)
+			c.mtx.Unlock()
+			return err
+		}
+		c.offsets[nodeID] = event.LSN
+		c.mtx.Unlock()
+	}
+}
+
+// status returns the written and saved offsets of every node
+func (c *cdcCollector) status() (map[string]uint64, map[string]uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	offsets := make(map[string]uint64, len(c.offsets))
+	flushed := make(map[string]uint64, len(c.flushed))
+	for nodeID, lsn := range c.offsets {
+		offsets[nodeID] = lsn
+	}
+	for nodeID, lsn := range c.flushed {
+		flushed[nodeID] = lsn
+	}
+	return offsets, flushed, c.failed
+}
@@ -4,58 +4,168 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	pb "github.com/b1acktothefuture/dht-system/gen"
 )
 
-func put(coordinator *Coordinator, key, value string) {
+func put(coordinator *Coordinator, key, value string, ttlSeconds *int64) {
+	var ttl int64
+	if nil != ttlSeconds {
+		ttl = *ttlSeconds
+	}
+	result, err := coordinator.Put(context.Background(), key, []byte(value), ttl)
+	printWrite("Put", result, err)
+}
 
-	nodeID, _ := coordinator.ConsistentHash.GetNode(key)
-	req := &pb.StoragePutRequest{
-		Key:   key,
-		Value: []byte(value),
+// printWrite prints the outcome of a replicated write on each replica
+func printWrite(operation string, result WriteResult, err error) {
+	for _, nodeID := range result.Acked {
+		fmt.Printf("Node[%v] %v done\n", nodeID, operation)
+	}
+	for _, nodeID := range result.Hinted {
+		fmt.Printf("Node[%v] down, hinted\n", nodeID)
+	}
+	for nodeID, failed := range result.Failed {
+		fmt.Printf("Node[%v] %v failed : %v\n", nodeID, operation, failed)
+	}
+	if err != nil {
+		fmt.Printf("%v failed : %v\n", operation, err)
 	}
-	res, _ := coordinator.Nodes[nodeID].client.Put(context.Background(), req)
-	// Error handling
-	fmt.Printf("Node[%v] Is Updated : %v\n", nodeID, res.GetIsUpdated())
 }
 
 func get(coordinator *Coordinator, key string) {
-
-	nodeID, _ := coordinator.ConsistentHash.GetNode(key)
-	req := &pb.StorageGetRequest{
-		Key: key,
+	nodeID, res, err := coordinator.Get(context.Background(), key)
+	if err != nil {
+		fmt.Printf("Node[%v] Get failed : %v\n", nodeID, err)
+		return
 	}
 
-	res, _ := coordinator.Nodes[nodeID].client.Get(context.Background(), req)
-
 	fmt.Printf("Node[%v] Value : %v\n", nodeID, string(res.GetValue()))
 }
 
 func update(coordinator *Coordinator, key, value string) {
-	nodeID, _ := coordinator.ConsistentHash.GetNode(key)
 	req := &pb.StorageUpdateRequest{
 		Key:   key,
 		Value: []byte(value),
 	}
 
-	res, _ := coordinator.Nodes[nodeID].client.Update(context.Background(), req)
+	var res *pb.StorageUpdateResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.Update(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Update Status : %v\n", result.Replicas[0], res.IsKeyPresent)
+	}
+	printWrite("Update", result, err)
+}
+
+func deleteKey(coordinator *Coordinator, key string) {
+	result, err := coordinator.Delete(context.Background(), key)
+	printWrite("Delete", result, err)
+}
+
+func increment(coordinator *Coordinator, key string, delta int64) {
+	req := &pb.StorageIncrementRequest{
+		Key:   key,
+		Delta: delta,
+	}
+
+	var res *pb.StorageIncrementResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.Increment(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Value : %v\n", result.Replicas[0], res.Value)
+	}
+	printWrite("Increment", result, err)
+}
 
-	fmt.Printf("Node[%v] Update Status : %v\n", nodeID, res.IsKeyPresent)
+func decrement(coordinator *Coordinator, key string, delta int64) {
+	req := &pb.StorageDecrementRequest{
+		Key:   key,
+		Delta: delta,
+	}
 
+	var res *pb.StorageDecrementResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.Decrement(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Value : %v\n", result.Replicas[0], res.Value)
+	}
+	printWrite("Decrement", result, err)
 }
 
-func delete(coordinator *Coordinator, key string) {
-	nodeID, _ := coordinator.ConsistentHash.GetNode(key)
-	req := &pb.StorageDeleteRequest{
-		Key: key,
+func stats(coordinator *Coordinator) {
+	nodes := coordinator.connections()
+	nodeIDs := make([]string, 0, len(nodes))
+	for nodeID := range nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
+		res, err := nodes[nodeID].client.Stats(context.Background(), &pb.StorageStatsRequest{})
+		if err != nil {
+			fmt.Printf("Node[%v] Stats failed : %v\n", nodeID, err)
+			continue
+		}
+		fmt.Printf("Node[%v] Keys : %v Memory : %v/%v Policy : %v Evictions : %v Expirations : %v\n",
+			nodeID, res.NumKeys, res.UsedMemoryBytes, res.MaxMemoryBytes, res.EvictionPolicy, res.Evictions, res.Expirations)
 	}
+	reads := coordinator.ReadRepairs()
+	fmt.Printf("Read repairs : %v Mismatches : %v Repaired : %v Failed : %v\n", reads.Reads, reads.Mismatches, reads.Repaired, reads.Failed)
+}
 
-	res, _ := coordinator.Nodes[nodeID].client.Delete(context.Background(), req)
+func scan(coordinator *Coordinator, start, end string, limit uint32, reverse bool) {
+	entries, err := rangeQuery(coordinator, &pb.StorageRangeRequest{
+		Start:   start,
+		End:     end,
+		Limit:   limit,
+		Reverse: reverse,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
-	fmt.Printf("Node[%v] Delete Status : %v\n", nodeID, res.IsKeyPresent)
+	for _, entry := range entries {
+		fmt.Printf("%v : %v\n", entry.Key, string(entry.Value))
+	}
+	fmt.Printf("(%d keys)\n", len(entries))
+}
+
+func query(coordinator *Coordinator, indexName, value string, limit uint32) {
+	entries, err := queryIndex(coordinator, &pb.StorageQueryIndexRequest{
+		IndexName: indexName,
+		Value:     value,
+		Limit:     limit,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("%v : %v\n", entry.Key, string(entry.Value))
+	}
+	fmt.Printf("(%d keys)\n", len(entries))
+}
+
+func watch(coordinator *Coordinator, key string, prefix bool) {
+	var id int
+	id = startWatch(coordinator, key, prefix, 0, func(nodeID string, event *pb.StorageWatchEvent) {
+		fmt.Printf("Watch[%d] Node[%v] LSN[%d] %v %v : %v\n", id, nodeID, event.LSN, event.Operation, event.Key, string(event.GetValue()))
+	})
+	fmt.Printf("Watch[%d] started\n", id)
 }
 
 func readInput() string {
@@ -64,6 +174,27 @@ func readInput() string {
 	return strings.TrimSpace(input)
 }
 
+func cdcStatus(coordinator *Coordinator) {
+	if nil == coordinator.cdc {
+		fmt.Println("CDC is disabled")
+		return
+	}
+	offsets, flushed, err := coordinator.cdc.status()
+
+	nodeIDs := make([]string, 0, len(offsets))
+	for nodeID := range offsets {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
+		fmt.Printf("Node[%v] Written LSN : %v Saved LSN : %v\n", nodeID, offsets[nodeID], flushed[nodeID])
+	}
+	if err != nil {
+		fmt.Printf("Sink failed : %v\n", err)
+	}
+}
+
 func cli(coordinator *Coordinator) {
 	for {
 		fmt.Print(">> ")
@@ -84,21 +215,42 @@ func cli(coordinator *Coordinator) {
 				continue
 			}
 			key := parts[1]
+			if coordinator.VectorClocks(key) {
+				getVersions(coordinator, key)
+				continue
+			}
 			get(coordinator, key)
 		case "PUT":
-			if len(parts) != 3 {
-				fmt.Println("Invalid PUT command. Usage: PUT Key Value")
+			if len(parts) != 3 && len(parts) != 4 {
+				fmt.Println("Invalid PUT command. Usage: PUT Key Value [TTLSeconds], PUT Key Value [Context] in a vclock keyspace")
 				continue
 			}
 			key, value := parts[1], parts[2]
-			put(coordinator, key, value)
+			if coordinator.VectorClocks(key) {
+				token := ""
+				if len(parts) == 4 {
+					token = parts[3]
+				}
+				putVersion(coordinator, key, value, token)
+				continue
+			}
+			var ttlSeconds *int64
+			if len(parts) == 4 {
+				ttl, err := strconv.ParseInt(parts[3], 10, 64)
+				if err != nil || ttl <= 0 {
+					fmt.Println("Invalid PUT command. TTLSeconds must be a positive integer")
+					continue
+				}
+				ttlSeconds = &ttl
+			}
+			put(coordinator, key, value, ttlSeconds)
 		case "DELETE":
 			if len(parts) != 2 {
 				fmt.Println("Invalid DELETE command. Usage: DELETE Key")
 				continue
 			}
 			key := parts[1]
-			delete(coordinator, key)
+			deleteKey(coordinator, key)
 		case "UPDATE":
 			if len(parts) != 3 {
 				fmt.Println("Invalid UPDATE command. Usage: UPDATE Key")
@@ -106,6 +258,243 @@ func cli(coordinator *Coordinator) {
 			}
 			key, value := parts[1], parts[2]
 			update(coordinator, key, value)
+		case "INCR", "DECR":
+			if len(parts) != 2 {
+				fmt.Printf("Invalid %s command. Usage: %s Key\n", command, command)
+				continue
+			}
+			if command == "INCR" {
+				increment(coordinator, parts[1], 1)
+			} else {
+				decrement(coordinator, parts[1], 1)
+			}
+		case "INCRBY", "DECRBY":
+			if len(parts) != 3 {
+				fmt.Printf("Invalid %s command. Usage: %s Key Delta\n", command, command)
+				continue
+			}
+			delta, err := strconv.ParseInt(parts[2], 10, 64)
+			if err != nil {
+				fmt.Printf("Invalid %s command. Delta must be an integer\n", command)
+				continue
+			}
+			if command == "INCRBY" {
+				increment(coordinator, parts[1], delta)
+			} else {
+				decrement(coordinator, parts[1], delta)
+			}
+		case "LPUSH", "RPUSH":
+			if len(parts) < 3 {
+				fmt.Printf("Invalid %s command. Usage: %s Key Value [Value ...]\n", command, command)
+				continue
+			}
+			listPush(coordinator, parts[1], parts[2:], command == "LPUSH")
+		case "LPOP", "RPOP":
+			if len(parts) != 2 && len(parts) != 3 {
+				fmt.Printf("Invalid %s command. Usage: %s Key [Count]\n", command, command)
+				continue
+			}
+			count := uint64(1)
+			if len(parts) == 3 {
+				parsed, err := strconv.ParseUint(parts[2], 10, 32)
+				if err != nil || parsed == 0 {
+					fmt.Printf("Invalid %s command. Count must be a positive integer\n", command)
+					continue
+				}
+				count = parsed
+			}
+			listPop(coordinator, parts[1], uint32(count), command == "LPOP")
+		case "LRANGE":
+			if len(parts) != 4 {
+				fmt.Println("Invalid LRANGE command. Usage: LRANGE Key Start Stop")
+				continue
+			}
+			start, startErr := strconv.ParseInt(parts[2], 10, 64)
+			stop, stopErr := strconv.ParseInt(parts[3], 10, 64)
+			if startErr != nil || stopErr != nil {
+				fmt.Println("Invalid LRANGE command. Start and Stop must be integers")
+				continue
+			}
+			listRange(coordinator, parts[1], start, stop)
+		case "SADD":
+			if len(parts) < 3 {
+				fmt.Println("Invalid SADD command. Usage: SADD Key Member [Member ...]")
+				continue
+			}
+			setAdd(coordinator, parts[1], parts[2:])
+		case "SREM":
+			if len(parts) < 3 {
+				fmt.Println("Invalid SREM command. Usage: SREM Key Member [Member ...]")
+				continue
+			}
+			setRemove(coordinator, parts[1], parts[2:])
+		case "SMEMBERS":
+			if len(parts) != 2 {
+				fmt.Println("Invalid SMEMBERS command. Usage: SMEMBERS Key")
+				continue
+			}
+			setMembers(coordinator, parts[1])
+		case "SISMEMBER":
+			if len(parts) != 3 {
+				fmt.Println("Invalid SISMEMBER command. Usage: SISMEMBER Key Member")
+				continue
+			}
+			setIsMember(coordinator, parts[1], parts[2])
+		case "HSET":
+			if len(parts) != 4 {
+				fmt.Println("Invalid HSET command. Usage: HSET Key Field Value")
+				continue
+			}
+			hashSet(coordinator, parts[1], parts[2], parts[3])
+		case "HGET":
+			if len(parts) != 3 {
+				fmt.Println("Invalid HGET command. Usage: HGET Key Field")
+				continue
+			}
+			hashGet(coordinator, parts[1], parts[2])
+		case "HDEL":
+			if len(parts) < 3 {
+				fmt.Println("Invalid HDEL command. Usage: HDEL Key Field [Field ...]")
+				continue
+			}
+			hashDelete(coordinator, parts[1], parts[2:])
+		case "RANGE":
+			if len(parts) < 3 || len(parts) > 5 {
+				fmt.Println("Invalid RANGE command. Usage: RANGE Start End [Limit] [REVERSE], End '*' has no upper bound")
+				continue
+			}
+			start, end := parts[1], parts[2]
+			if end == "*" {
+				end = ""
+			}
+			var limit uint64
+			reverse := false
+			isValid := true
+			for _, option := range parts[3:] {
+				if strings.ToUpper(option) == "REVERSE" {
+					reverse = true
+					continue
+				}
+				parsed, err := strconv.ParseUint(option, 10, 32)
+				if err != nil {
+					isValid = false
+					break
+				}
+				limit = parsed
+			}
+			if !isValid {
+				fmt.Println("Invalid RANGE command. Limit must be a non negative integer")
+				continue
+			}
+			scan(coordinator, start, end, uint32(limit), reverse)
+		case "QUERY":
+			if len(parts) != 3 && len(parts) != 4 {
+				fmt.Println("Invalid QUERY command. Usage: QUERY IndexName Value [Limit]")
+				continue
+			}
+			var limit uint64
+			if len(parts) == 4 {
+				parsed, err := strconv.ParseUint(parts[3], 10, 32)
+				if err != nil {
+					fmt.Println("Invalid QUERY command. Limit must be a non negative integer")
+					continue
+				}
+				limit = parsed
+			}
+			query(coordinator, parts[1], parts[2], uint32(limit))
+		case "WATCH":
+			if len(parts) != 2 && !(len(parts) == 3 && strings.ToUpper(parts[2]) == "PREFIX") {
+				fmt.Println("Invalid WATCH command. Usage: WATCH Key [PREFIX]")
+				continue
+			}
+			watch(coordinator, parts[1], len(parts) == 3)
+		case "UNWATCH":
+			if len(parts) != 2 {
+				fmt.Println("Invalid UNWATCH command. Usage: UNWATCH WatchID")
+				continue
+			}
+			id, err := strconv.Atoi(parts[1])
+			if err != nil || !stopWatch(coordinator, id) {
+				fmt.Println("Invalid UNWATCH command. Unknown watch")
+				continue
+			}
+			fmt.Printf("Watch[%d] stopped\n", id)
+		case "TXN":
+			if len(parts) < 3 {
+				fmt.Println("Invalid TXN command. Usage: TXN [LOCAL] [IF Key EXISTS =|!= | Key VERSION|VALUE =|!=|<|> Operand ...] THEN Op ... [ELSE Op ...], Op is PUT Key Value, DELETE Key or GET Key")
+				continue
+			}
+			txn(coordinator, parts[1:])
+		case "STATS":
+			stats(coordinator)
+		case "CDC":
+			cdcStatus(coordinator)
+		case "ADDNODE":
+			if len(parts) != 3 && len(parts) != 4 {
+				fmt.Println("Invalid ADDNODE command. Usage: ADDNODE NodeID Host:Port [Weight]")
+				continue
+			}
+			host, portText, err := net.SplitHostPort(parts[2])
+			port, portErr := strconv.ParseUint(portText, 10, 16)
+			if err != nil || portErr != nil || port == 0 {
+				fmt.Println("Invalid ADDNODE command. Address must be Host:Port")
+				continue
+			}
+			weight := 1
+			if len(parts) == 4 {
+				if weight, err = strconv.Atoi(parts[3]); err != nil || weight < 1 {
+					fmt.Println("Invalid ADDNODE command. Weight must be a positive integer")
+					continue
+				}
+			}
+			addNode(coordinator, parts[1], Node{Host: host, Port: port, Weight: weight})
+		case "REMOVENODE":
+			if len(parts) != 2 {
+				fmt.Println("Invalid REMOVENODE command. Usage: REMOVENODE NodeID")
+				continue
+			}
+			removeNode(coordinator, parts[1])
+		case "WEIGHT":
+			if len(parts) != 3 {
+				fmt.Println("Invalid WEIGHT command. Usage: WEIGHT NodeID Weight")
+				continue
+			}
+			weight, err := strconv.Atoi(parts[2])
+			if err != nil || weight < 1 {
+				fmt.Println("Invalid WEIGHT command. Weight must be a positive integer")
+				continue
+			}
+			setNodeWeight(coordinator, parts[1], weight)
+		case "DRAIN":
+			if len(parts) != 2 {
+				fmt.Println("Invalid DRAIN command. Usage: DRAIN NodeID")
+				continue
+			}
+			drain(coordinator, parts[1])
+		case "NODES":
+			listNodes(coordinator)
+		case "MIGRATIONS":
+			migrations(coordinator)
+		case "RING":
+			listRing(coordinator)
+		case "OWNER":
+			if len(parts) != 2 {
+				fmt.Println("Invalid OWNER command. Usage: OWNER Key")
+				continue
+			}
+			keyOwner(coordinator, parts[1])
+		case "BALANCE":
+			nodeBalance(coordinator)
+		case "REPAIR":
+			repair(coordinator)
+		case "HINTS":
+			hintStats(coordinator)
+		case "GOSSIP":
+			if len(parts) != 2 {
+				fmt.Println("Invalid GOSSIP command. Usage: GOSSIP host:port")
+				continue
+			}
+			gossipMembers(parts[1])
 		case "EXIT":
 			fmt.Println("Exiting...")
 			return
@@ -4,21 +4,110 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/b1acktothefuture/dht-system/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
 type Node struct {
 	Host string `yaml:"Host"`
 	Port uint64 `yaml:"Port"`
+
+	// Multiplies the virtual nodes of the node, a node of weight 4 gets four times the keys of a
+	// node of weight 1. 0 counts as 1.
+	Weight int `yaml:"Weight" json:",omitempty"`
+
+	// Set in the saved membership for a node being drained, it is out of the ring
+	Leaving bool `yaml:"-" json:",omitempty"`
 	// Parameters for secure connections [certificate_path]
 }
 
+// Keyspace is the keys starting with Prefix
+type Keyspace struct {
+	Prefix string `yaml:"Prefix"`
+
+	// lww keeps the last write of a key, vclock keeps concurrent writes as siblings under vector clocks
+	Versioning string `yaml:"Versioning"`
+}
+
 type Config struct {
 	Nodes                map[string]Node `yaml:"Nodes"`
 	NumberOfVirtualNodes int             `yaml:"NumberOfVirtualNodes"`
-	Log                  struct {
+
+	// How keys are assigned to nodes: ring, bounded, rendezvous or jump with the crc32, fnv1a,
+	// xxhash or murmur3 hash function. A consistent hashing ring with crc32 when empty.
+	Placement utils.PlacementConfig `yaml:"Placement"`
+
+	Log struct {
 		File string `yaml:"File"`
 	} `yaml:"Log"`
+
+	// Nodes added and removed at runtime, replaces Nodes once it exists
+	MembershipFile string `yaml:"MembershipFile"`
+
+	// Port of the Coordinator admin service, 0 disables it
+	AdminPort uint64 `yaml:"AdminPort"`
+
+	// Copies kept of each key, part of the cluster metadata
+	ReplicationFactor int `yaml:"ReplicationFactor"`
+
+	// Replicas acknowledging a Put or Delete before it succeeds, a majority of the replication
+	// factor when 0
+	WriteQuorum int `yaml:"WriteQuorum"`
+
+	// Versioning of the keyspaces, the longest prefix of a key applies. Keys outside of them are lww.
+	Keyspaces []Keyspace `yaml:"Keyspaces"`
+
+	// Replicas answering a Get, the owner only when 0. The read goes to every replica and returns
+	// once the first ReadQuorum answered. A read repair waits for every replica on Chance percent of
+	// the reads and writes the newest version back to the stale ones, before the read returns when
+	// Mode is blocking, in the background when async (the default).
+	ReadQuorum int `yaml:"ReadQuorum"`
+	ReadRepair struct {
+		Chance int    `yaml:"Chance"`
+		Mode   string `yaml:"Mode"`
+	} `yaml:"ReadRepair"`
+
+	// Milliseconds between the heartbeats the coordinator sends to the nodes, 1000 when 0
+	HeartbeatIntervalMillis int `yaml:"HeartbeatIntervalMillis"`
+
+	// Writes a replica missed while down, replayed once the heartbeats see it back. Hints older
+	// than TTLSeconds (3 hours when 0) are dropped and repairs bring the replica up to date instead,
+	// past MaxHints (10000 when 0) the oldest are dropped. Kept in File across restarts, in memory
+	// only when empty.
+	HintedHandoff struct {
+		File       string `yaml:"File"`
+		TTLSeconds int    `yaml:"TTLSeconds"`
+		MaxHints   int    `yaml:"MaxHints"`
+	} `yaml:"HintedHandoff"`
+
+	// Seconds between the anti-entropy repairs of the replicas, run by the leader. 0 only repairs
+	// on demand.
+	RepairIntervalSeconds int `yaml:"RepairIntervalSeconds"`
+
+	// Coordinator replicas agreeing on the cluster metadata through Raft, disabled when ID is empty.
+	// Peers maps every replica ID, this one included, to its admin host:port. The membership file
+	// is not used, the state file holds the term and the vote, the Raft log and its snapshot are
+	// saved next to it. The log is compacted every SnapshotEntries applied entries while no keys move.
+	Raft struct {
+		ID                    string            `yaml:"ID"`
+		Peers                 map[string]string `yaml:"Peers"`
+		StateFile             string            `yaml:"StateFile"`
+		ElectionTimeoutMillis int               `yaml:"ElectionTimeoutMillis"`
+		HeartbeatMillis       int               `yaml:"HeartbeatMillis"`
+		SnapshotEntries       int               `yaml:"SnapshotEntries"`
+	} `yaml:"Raft"`
+
+	// Decisions of multi-key transactions, replayed on start to finish in doubt ones
+	TxnLogFile string `yaml:"TxnLogFile"`
+
+	// Change data capture of every node mutation
+	CDC struct {
+		Enabled     bool   `yaml:"Enabled"`
+		Sink        string `yaml:"Sink"`        // "file" or "socket"
+		Directory   string `yaml:"Directory"`   // File sink, one <NodeID>.jsonl per node
+		SocketPath  string `yaml:"SocketPath"`  // Socket sink, unix socket the events are written to
+		OffsetsFile string `yaml:"OffsetsFile"` // Last LSN written per node
+	} `yaml:"CDC"`
 }
 
 // takes in a config file descriptor
@@ -0,0 +1,210 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+func toBytes(values []string) [][]byte {
+	converted := make([][]byte, 0, len(values))
+	for _, value := range values {
+		converted = append(converted, []byte(value))
+	}
+	return converted
+}
+
+func printValues(nodeID string, values [][]byte) {
+	for i, value := range values {
+		fmt.Printf("Node[%v] %d) %v\n", nodeID, i+1, string(value))
+	}
+	if len(values) == 0 {
+		fmt.Printf("Node[%v] (empty)\n", nodeID)
+	}
+}
+
+func listPush(coordinator *Coordinator, key string, values []string, left bool) {
+	req := &pb.StorageListPushRequest{
+		Key:    key,
+		Values: toBytes(values),
+		Left:   left,
+	}
+
+	var res *pb.StorageListPushResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.ListPush(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Length : %v\n", result.Replicas[0], res.Length)
+	}
+	printWrite("Push", result, err)
+}
+
+func listPop(coordinator *Coordinator, key string, count uint32, left bool) {
+	req := &pb.StorageListPopRequest{
+		Key:   key,
+		Count: count,
+		Left:  left,
+	}
+
+	var res *pb.StorageListPopResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.ListPop(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		printValues(result.Replicas[0], res.Values)
+	}
+	printWrite("Pop", result, err)
+}
+
+func listRange(coordinator *Coordinator, key string, start, stop int64) {
+	nodeID, node, err := coordinator.ownerFor(context.Background(), key)
+	if err != nil {
+		fmt.Printf("Owner lookup failed : %v\n", err)
+		return
+	}
+	req := &pb.StorageListRangeRequest{
+		Key:   key,
+		Start: start,
+		Stop:  stop,
+	}
+
+	res, err := node.client.ListRange(context.Background(), req)
+	if err != nil {
+		fmt.Printf("Node[%v] ListRange failed : %v\n", nodeID, err)
+		return
+	}
+
+	printValues(nodeID, res.Values)
+}
+
+func setAdd(coordinator *Coordinator, key string, members []string) {
+	req := &pb.StorageSetAddRequest{
+		Key:     key,
+		Members: toBytes(members),
+	}
+
+	var res *pb.StorageSetAddResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.SetAdd(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Added : %v\n", result.Replicas[0], res.Added)
+	}
+	printWrite("SetAdd", result, err)
+}
+
+func setRemove(coordinator *Coordinator, key string, members []string) {
+	req := &pb.StorageSetRemoveRequest{
+		Key:     key,
+		Members: toBytes(members),
+	}
+
+	var res *pb.StorageSetRemoveResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.SetRemove(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Removed : %v\n", result.Replicas[0], res.Removed)
+	}
+	printWrite("SetRemove", result, err)
+}
+
+func setMembers(coordinator *Coordinator, key string) {
+	nodeID, node, err := coordinator.ownerFor(context.Background(), key)
+	if err != nil {
+		fmt.Printf("Owner lookup failed : %v\n", err)
+		return
+	}
+	req := &pb.StorageSetMembersRequest{
+		Key: key,
+	}
+
+	res, err := node.client.SetMembers(context.Background(), req)
+	if err != nil {
+		fmt.Printf("Node[%v] SetMembers failed : %v\n", nodeID, err)
+		return
+	}
+
+	printValues(nodeID, res.Members)
+}
+
+func setIsMember(coordinator *Coordinator, key, member string) {
+	nodeID, node, err := coordinator.ownerFor(context.Background(), key)
+	if err != nil {
+		fmt.Printf("Owner lookup failed : %v\n", err)
+		return
+	}
+	req := &pb.StorageSetIsMemberRequest{
+		Key:    key,
+		Member: []byte(member),
+	}
+
+	res, err := node.client.SetIsMember(context.Background(), req)
+	if err != nil {
+		fmt.Printf("Node[%v] SetIsMember failed : %v\n", nodeID, err)
+		return
+	}
+
+	fmt.Printf("Node[%v] Is Member : %v\n", nodeID, res.IsMember)
+}
+
+func hashSet(coordinator *Coordinator, key, field, value string) {
+	req := &pb.StorageHashSetRequest{
+		Key:   key,
+		Field: field,
+		Value: []byte(value),
+	}
+
+	var res *pb.StorageHashSetResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.HashSet(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Is New : %v\n", result.Replicas[0], res.IsNew)
+	}
+	printWrite("HashSet", result, err)
+}
+
+func hashGet(coordinator *Coordinator, key, field string) {
+	nodeID, node, err := coordinator.ownerFor(context.Background(), key)
+	if err != nil {
+		fmt.Printf("Owner lookup failed : %v\n", err)
+		return
+	}
+	req := &pb.StorageHashGetRequest{
+		Key:   key,
+		Field: field,
+	}
+
+	res, err := node.client.HashGet(context.Background(), req)
+	if err != nil {
+		fmt.Printf("Node[%v] HashGet failed : %v\n", nodeID, err)
+		return
+	}
+
+	fmt.Printf("Node[%v] Value : %v\n", nodeID, string(res.GetValue()))
+}
+
+func hashDelete(coordinator *Coordinator, key string, fields []string) {
+	req := &pb.StorageHashDeleteRequest{
+		Key:    key,
+		Fields: fields,
+	}
+
+	var res *pb.StorageHashDeleteResponse
+	result, err := coordinator.Mutate(context.Background(), key, func(client pb.StorageClient) (err error) {
+		res, err = client.HashDelete(context.Background(), req)
+		return err
+	})
+	if nil != res {
+		fmt.Printf("Node[%v] Deleted : %v\n", result.Replicas[0], res.Deleted)
+	}
+	printWrite("HashDelete", result, err)
+}
@@ -0,0 +1,118 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+const drainPollMillis = 100
+
+var ErrDrainIncomplete = errors.New("node still holds keys after the drain")
+
+// DrainNode retires a node. It leaves the ring so it gets no new writes, its keys are moved to
+// the remaining owners and once the node holds no key it is told to shut down and forgotten.
+// A drain that failed can be run again, it moves whatever the node still holds. A replica that
+// does not lead forwards the drain to the leader.
+func (coordinator *Coordinator) DrainNode(ctx context.Context, nodeID string) error {
+	forwarded, err := coordinator.forward(ctx, func(leader pb.CoordinatorClient) error {
+		_, err := leader.DrainNode(ctx, &pb.CoordinatorDrainNodeRequest{NodeID: nodeID})
+		return err
+	})
+	if forwarded {
+		return err
+	}
+
+	node, ok := coordinator.drainingNode(nodeID)
+	if ok {
+		if err := coordinator.resumeDrain(nodeID); err != nil {
+			return err
+		}
+	} else {
+		if err := coordinator.leave(nodeID, true); err != nil {
+			return err
+		}
+		node, _ = coordinator.drainingNode(nodeID)
+	}
+	log.Printf("Draining Node[%v]", nodeID)
+
+	if err := coordinator.waitMigrations(ctx); err != nil {
+		return err
+	}
+	for _, status := range coordinator.Migrations() {
+		if status.From == nodeID && status.Transferred != status.Imported+status.Skipped {
+			return fmt.Errorf("%w: %d of %d keys moved to Node[%v]", ErrDrainIncomplete, status.Imported+status.Skipped, status.Transferred, status.To)
+		}
+	}
+	stats, err := node.client.Stats(ctx, &pb.StorageStatsRequest{})
+	if err != nil {
+		return fmt.Errorf("Node[%v] stats failed : %w", nodeID, err)
+	}
+	if stats.NumKeys != 0 {
+		return fmt.Errorf("%w: %d keys left on Node[%v]", ErrDrainIncomplete, stats.NumKeys, nodeID)
+	}
+
+	// Forget the node first, a node that missed the shutdown only holds an empty table
+	coordinator.changeMtx.Lock()
+	err = coordinator.changeMetadata(metadataChange{Op: metadataForgetNode, NodeID: nodeID})
+	coordinator.changeMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	defer node.conn.Close()
+
+	if _, err := node.client.Drain(ctx, &pb.StorageDrainRequest{}); err != nil {
+		return fmt.Errorf("Node[%v] is drained but did not shut down : %w", nodeID, err)
+	}
+	log.Printf("Node[%v] drained", nodeID)
+	return nil
+}
+
+// drainingNode returns the connection to a node that left the ring and still holds keys
+func (coordinator *Coordinator) drainingNode(nodeID string) (*NodeConnection, bool) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	node, ok := coordinator.draining[nodeID]
+	return node, ok
+}
+
+// resumeDrain moves every key a draining node still holds to its owner in the current ring
+func (coordinator *Coordinator) resumeDrain(nodeID string) error {
+	coordinator.changeMtx.Lock()
+	defer coordinator.changeMtx.Unlock()
+	if coordinator.isMigrating() {
+		return ErrMigrating
+	}
+	return coordinator.changeMetadata(metadataChange{Op: metadataResumeDrain, NodeID: nodeID})
+}
+
+// waitMigrations waits for the keys moved by the last membership change
+func (coordinator *Coordinator) waitMigrations(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollMillis * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if !coordinator.isMigrating() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func drain(coordinator *Coordinator, nodeID string) {
+	if err := coordinator.DrainNode(context.Background(), nodeID); err != nil {
+		fmt.Printf("Drain failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Node[%v] drained\n", nodeID)
+}
@@ -0,0 +1,51 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// GossipMembers reads the membership the nodes gossip among themselves from any of them, seed is
+// its host:port. Returns every known member, dead ones included, and the version of the live ones.
+func GossipMembers(ctx context.Context, seed string) ([]utils.Member, uint64, error) {
+	conn, err := grpc.Dial(seed, grpc.WithInsecure())
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error establishing connection with %s : %w", seed, err)
+	}
+	defer conn.Close()
+
+	res, err := pb.NewStorageClient(conn).GossipSync(ctx, &pb.StorageGossipSyncRequest{})
+	if err != nil {
+		return nil, 0, err
+	}
+	members := make([]utils.Member, 0, len(res.Members))
+	for _, member := range res.Members {
+		members = append(members, utils.Member{
+			ID:          member.NodeID,
+			Address:     member.Address,
+			State:       member.State,
+			Incarnation: member.Incarnation,
+		})
+	}
+	return members, res.Version, nil
+}
+
+func gossipMembers(seed string) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+	defer cancel()
+
+	members, version, err := GossipMembers(ctx, seed)
+	if err != nil {
+		fmt.Printf("Gossip membership failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Version : %v\n", version)
+	for _, member := range members {
+		fmt.Printf("Node[%v] %s %s Incarnation : %v\n", member.ID, member.Address, member.State, member.Incarnation)
+	}
+}
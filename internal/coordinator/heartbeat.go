@@ -1,7 +1,89 @@
 package coordinator
 
-// TODO
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
 
-func monitor() error {
-	return nil
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+// Milliseconds between two heartbeats when not configured
+const heartbeatIntervalMillis = 1000
+
+// heartbeat pings every node on an interval. A node that answers gets the hints it missed
+// replayed, the first time it answers after missing heartbeats included.
+type heartbeat struct {
+	mtx  sync.Mutex
+	down map[string]bool
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// monitor starts the heartbeats of the nodes, until the returned heartbeat is closed
+func (coordinator *Coordinator) monitor(interval time.Duration) *heartbeat {
+	hb := &heartbeat{down: make(map[string]bool), stop: make(chan struct{})}
+
+	hb.wg.Add(1)
+	go func() {
+		defer hb.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-hb.stop:
+				return
+			}
+
+			var wg sync.WaitGroup
+			for nodeID, node := range coordinator.connections() {
+				wg.Add(1)
+				go func(nodeID string, node *NodeConnection) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(context.Background(), interval)
+					_, err := node.client.Stats(ctx, &pb.StorageStatsRequest{})
+					cancel()
+					if hb.seen(nodeID, err == nil) {
+						coordinator.replayHints(nodeID, node)
+					}
+				}(nodeID, node)
+			}
+			wg.Wait()
+		}
+	}()
+	return hb
+}
+
+// seen records the outcome of a heartbeat, returns whether the node is up
+func (hb *heartbeat) seen(nodeID string, up bool) bool {
+	hb.mtx.Lock()
+	defer hb.mtx.Unlock()
+
+	switch {
+	case up && hb.down[nodeID]:
+		log.Printf("Node[%v] is back up", nodeID)
+		delete(hb.down, nodeID)
+	case !up && !hb.down[nodeID]:
+		log.Printf("Node[%v] missed a heartbeat, down", nodeID)
+		hb.down[nodeID] = true
+	}
+	return up
+}
+
+func (hb *heartbeat) close() {
+	close(hb.stop)
+	hb.wg.Wait()
+}
+
+// NodeDown reports whether the last heartbeat of a node went unanswered
+func (coordinator *Coordinator) NodeDown(nodeID string) bool {
+	if nil == coordinator.heartbeat {
+		return false
+	}
+	coordinator.heartbeat.mtx.Lock()
+	defer coordinator.heartbeat.mtx.Unlock()
+
+	return coordinator.heartbeat.down[nodeID]
 }
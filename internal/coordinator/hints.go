@@ -0,0 +1,309 @@
+package coordinator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// Limits of the hinted handoff when not configured
+const (
+	hintTTLSeconds = 3 * 60 * 60
+	maxHints       = 10000
+)
+
+// hint is a write a replica missed while down
+type hint struct {
+	Target string `json:"target"`
+	Stored int64  `json:"stored"` // Unix nano
+	mutation
+}
+
+// HintStats counts the hints of the hinted handoff since the coordinator started
+type HintStats struct {
+	Pending  map[string]int // By target node
+	Stored   uint64
+	Replayed uint64
+	Expired  uint64 // Older than the TTL, a repair brings the replica up to date instead
+	Dropped  uint64 // Over the size limit or rejected by their target
+}
+
+// hintStore keeps the hints in memory, appended to a file so they survive a restart. The file is
+// rewritten with the pending hints after every replay.
+type hintStore struct {
+	mtx       sync.Mutex
+	path      string
+	file      *os.File
+	ttl       time.Duration
+	maxHints  int
+	hints     []hint          // Oldest first
+	replaying map[string]bool // Targets whose hints are being sent
+	stats     HintStats
+}
+
+// openHints loads the pending hints of the file, an empty path keeps them in memory only
+func openHints(path string, ttlSeconds, limit int) (*hintStore, error) {
+	if ttlSeconds <= 0 {
+		ttlSeconds = hintTTLSeconds
+	}
+	if limit <= 0 {
+		limit = maxHints
+	}
+	h := &hintStore{path: path, ttl: time.Duration(ttlSeconds) * time.Second, maxHints: limit, replaying: make(map[string]bool)}
+	if path == "" {
+		return h, nil
+	}
+
+	if file, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			var stored hint
+			if err := json.Unmarshal(scanner.Bytes(), &stored); err != nil {
+				file.Close()
+				return nil, fmt.Errorf("Error decoding hints : %w", err)
+			}
+			h.hints = append(h.hints, stored)
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("Error reading hints : %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("Error opening hints : %w", err)
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.expire()
+	if over := len(h.hints) - h.maxHints; over > 0 {
+		h.hints = h.hints[over:]
+	}
+	if err := h.rewrite(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// expire drops the hints older than the TTL. Caller must hold mtx.
+func (h *hintStore) expire() {
+	deadline := time.Now().Add(-h.ttl).UnixNano()
+	kept := h.hints[:0]
+	for _, stored := range h.hints {
+		if stored.Stored < deadline {
+			h.stats.Expired++
+			continue
+		}
+		kept = append(kept, stored)
+	}
+	h.hints = kept
+}
+
+// rewrite replaces the file with the pending hints. Caller must hold mtx.
+func (h *hintStore) rewrite() error {
+	if h.path == "" {
+		return nil
+	}
+	if nil != h.file {
+		h.file.Close()
+		h.file = nil
+	}
+
+	tmp := h.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("Error creating hints : %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	for _, stored := range h.hints {
+		data, err := json.Marshal(stored)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(data, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error writing hints : %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error syncing hints : %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		file.Close()
+		return fmt.Errorf("Error replacing hints : %w", err)
+	}
+	if err := utils.SyncDir(h.path); err != nil {
+		file.Close()
+		return err
+	}
+	h.file = file
+	return nil
+}
+
+// add keeps a write for a replica that is down, the oldest hint is dropped past the limit
+func (h *hintStore) add(target string, m mutation) {
+	stored := hint{Target: target, Stored: time.Now().UnixNano(), mutation: m}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if len(h.hints) >= h.maxHints {
+		h.hints = h.hints[1:]
+		h.stats.Dropped++
+	}
+	h.hints = append(h.hints, stored)
+	h.stats.Stored++
+
+	// Dropped hints stay in the file until the next rewrite, they are trimmed on load. The hint is
+	// synced before the write is acknowledged, so it survives a crash of the coordinator.
+	if nil != h.file {
+		if data, err := json.Marshal(stored); err == nil {
+			if _, err := h.file.Write(append(data, '\n')); err == nil {
+				h.file.Sync()
+			}
+		}
+	}
+}
+
+// pending returns the number of hints of a target
+func (h *hintStore) pending(target string) int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	count := 0
+	for _, stored := range h.hints {
+		if stored.Target == target {
+			count++
+		}
+	}
+	return count
+}
+
+// take removes the live hints of a target for a replay, none while another replay of the target
+// runs. finish has to be called once they are sent.
+func (h *hintStore) take(target string) []hint {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.replaying[target] {
+		return nil
+	}
+	h.expire()
+	taken := []hint{}
+	kept := h.hints[:0]
+	for _, stored := range h.hints {
+		if stored.Target == target {
+			taken = append(taken, stored)
+			continue
+		}
+		kept = append(kept, stored)
+	}
+	h.hints = kept
+	if len(taken) != 0 {
+		h.replaying[target] = true
+	}
+	return taken
+}
+
+// finish ends the replay of a target, unsent hints are kept for the next one
+func (h *hintStore) finish(target string, replayed, dropped int, unsent []hint) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	delete(h.replaying, target)
+	h.stats.Replayed += uint64(replayed)
+	h.stats.Dropped += uint64(dropped)
+	h.hints = append(unsent, h.hints...)
+	if over := len(h.hints) - h.maxHints; over > 0 {
+		h.hints = h.hints[over:]
+		h.stats.Dropped += uint64(over)
+	}
+	if err := h.rewrite(); err != nil {
+		log.Printf("Error saving hints : %v", err)
+	}
+}
+
+func (h *hintStore) Stats() HintStats {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	stats := h.stats
+	stats.Pending = make(map[string]int)
+	for _, stored := range h.hints {
+		stats.Pending[stored.Target]++
+	}
+	return stats
+}
+
+func (h *hintStore) close() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if nil != h.file {
+		h.file.Close()
+		h.file = nil
+	}
+}
+
+// Hints returns the counters of the hinted handoff
+func (coordinator *Coordinator) Hints() HintStats {
+	return coordinator.hints.Stats()
+}
+
+// replayHints sends the hints of a node that is back in the order they were stored. A write older
+// than the one the node holds is ignored by it. A hint the node rejects is dropped, the rest are
+// kept when the node goes down again.
+func (coordinator *Coordinator) replayHints(nodeID string, node *NodeConnection) {
+	hints := coordinator.hints.take(nodeID)
+	if len(hints) == 0 {
+		return
+	}
+
+	replayed, dropped := 0, 0
+	for i, stored := range hints {
+		ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+		_, err := stored.apply(ctx, node)
+		cancel()
+		switch {
+		case err == nil:
+			replayed++
+		case unreachable(err):
+			log.Printf("Node[%v] down again, %d hints left : %v", nodeID, len(hints)-i, err)
+			coordinator.hints.finish(nodeID, replayed, dropped, hints[i:])
+			return
+		default:
+			log.Printf("Node[%v] rejected the hint of %s : %v", nodeID, stored.Key, err)
+			dropped++
+		}
+	}
+	log.Printf("Node[%v] replayed %d hints, dropped %d", nodeID, replayed, dropped)
+	coordinator.hints.finish(nodeID, replayed, dropped, nil)
+}
+
+func hintStats(coordinator *Coordinator) {
+	stats := coordinator.Hints()
+	fmt.Printf("Stored : %v Replayed : %v Expired : %v Dropped : %v\n", stats.Stored, stats.Replayed, stats.Expired, stats.Dropped)
+	nodeIDs := make([]string, 0, len(stats.Pending))
+	for nodeID := range stats.Pending {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+	for _, nodeID := range nodeIDs {
+		state := "up"
+		if coordinator.NodeDown(nodeID) {
+			state = "down"
+		}
+		fmt.Printf("Node[%v] Pending : %v (%v)\n", nodeID, stats.Pending[nodeID], state)
+	}
+}
@@ -0,0 +1,247 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc"
+)
+
+var ErrNodeExists = errors.New("node is already in the cluster")
+var ErrUnknownNode = errors.New("node is not in the cluster")
+var ErrLastNode = errors.New("the last node of the cluster cannot be removed")
+
+// dialNode connects to a storage node, blocking until it is reachable or the timeout expires
+func dialNode(nodeID string, info Node) (*NodeConnection, error) {
+	address := fmt.Sprintf("%s:%d", info.Host, info.Port)
+	conn, err := grpc.Dial(address, grpc.WithBlock(), grpc.WithTimeout(connectionTimeout*time.Second), grpc.WithInsecure())
+	if nil != err {
+		return nil, fmt.Errorf("Error establishing connection with %s : %w", address, err)
+	}
+	return &NodeConnection{
+		conn:   conn,
+		client: pb.NewStorageClient(conn),
+		info:   info,
+	}, nil
+}
+
+// loadMembership returns the nodes saved by the last membership change, nil when nothing was saved
+func loadMembership(path string) (map[string]Node, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading membership file : %w", err)
+	}
+
+	var nodes map[string]Node
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("Error parsing membership file : %w", err)
+	}
+	return nodes, nil
+}
+
+// saveMembership replaces the membership file with nodes
+func saveMembership(path string, nodes map[string]Node) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("Error creating membership file : %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("Error writing membership file : %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error syncing membership file : %w", err)
+	}
+	file.Close()
+	return os.Rename(tmp, path)
+}
+
+// membership returns the address of every node, draining nodes are marked as leaving. Caller must hold nodesMtx.
+func (coordinator *Coordinator) membership() map[string]Node {
+	nodes := make(map[string]Node, len(coordinator.Nodes)+len(coordinator.draining))
+	for nodeID, node := range coordinator.Nodes {
+		nodes[nodeID] = node.info
+	}
+	for nodeID, node := range coordinator.draining {
+		info := node.info
+		info.Leaving = true
+		nodes[nodeID] = info
+	}
+	return nodes
+}
+
+// Members returns the address of every node in the cluster
+func (coordinator *Coordinator) Members() map[string]Node {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	return coordinator.membership()
+}
+
+// AddNode dials a storage node and adds it to the ring, the keys it now owns are moved to it in
+// the background. The new membership is saved before it is used. A replica that does not lead
+// forwards the change to the leader.
+func (coordinator *Coordinator) AddNode(nodeID string, info Node) error {
+	forwarded, err := coordinator.forward(context.Background(), func(leader pb.CoordinatorClient) error {
+		_, err := leader.AddNode(context.Background(), &pb.CoordinatorAddNodeRequest{Node: &pb.CoordinatorNode{NodeID: nodeID, Host: info.Host, Port: info.Port, Weight: int64(info.Weight)}})
+		return err
+	})
+	if forwarded {
+		return err
+	}
+
+	if _, ok := coordinator.node(nodeID); ok {
+		return fmt.Errorf("%w: %s", ErrNodeExists, nodeID)
+	}
+	node, err := dialNode(nodeID, info)
+	if err != nil {
+		return err
+	}
+	node.conn.Close()
+
+	// Membership changes wait for the keys of the previous one to settle
+	coordinator.changeMtx.Lock()
+	defer coordinator.changeMtx.Unlock()
+	if coordinator.isMigrating() {
+		return ErrMigrating
+	}
+	return coordinator.changeMetadata(metadataChange{Op: metadataAddNode, NodeID: nodeID, Node: info})
+}
+
+// RemoveNode takes a node out of the ring, its keys are moved to the new owners in the background
+// and its connection is closed once they moved. The new membership is saved before it is used.
+// A replica that does not lead forwards the change to the leader.
+func (coordinator *Coordinator) RemoveNode(nodeID string) error {
+	forwarded, err := coordinator.forward(context.Background(), func(leader pb.CoordinatorClient) error {
+		_, err := leader.RemoveNode(context.Background(), &pb.CoordinatorRemoveNodeRequest{NodeID: nodeID})
+		return err
+	})
+	if forwarded {
+		return err
+	}
+	return coordinator.leave(nodeID, false)
+}
+
+// SetNodeWeight changes the weight of a node, the keys of the ranges it gains or loses are moved
+// in the background. A replica that does not lead forwards the change to the leader.
+func (coordinator *Coordinator) SetNodeWeight(nodeID string, weight int) error {
+	forwarded, err := coordinator.forward(context.Background(), func(leader pb.CoordinatorClient) error {
+		_, err := leader.SetNodeWeight(context.Background(), &pb.CoordinatorSetNodeWeightRequest{NodeID: nodeID, Weight: int64(weight)})
+		return err
+	})
+	if forwarded {
+		return err
+	}
+
+	coordinator.changeMtx.Lock()
+	defer coordinator.changeMtx.Unlock()
+	if coordinator.isMigrating() {
+		return ErrMigrating
+	}
+	return coordinator.changeMetadata(metadataChange{Op: metadataSetWeight, NodeID: nodeID, Node: Node{Weight: weight}})
+}
+
+// leave takes a node out of the ring and moves its keys to the new owners. A draining node is
+// kept as leaving with its connection open until the drain completes.
+func (coordinator *Coordinator) leave(nodeID string, drain bool) error {
+	coordinator.changeMtx.Lock()
+	defer coordinator.changeMtx.Unlock()
+	if coordinator.isMigrating() {
+		return ErrMigrating
+	}
+
+	if drain {
+		return coordinator.changeMetadata(metadataChange{Op: metadataDrainNode, NodeID: nodeID})
+	}
+	return coordinator.changeMetadata(metadataChange{Op: metadataRemoveNode, NodeID: nodeID})
+}
+
+// membershipChanged moves the streams of the watches and the change data capture to the new nodes
+func (coordinator *Coordinator) membershipChanged() {
+	refreshWatches(coordinator)
+	if nil != coordinator.cdc {
+		coordinator.cdc.sync(coordinator)
+	}
+}
+
+// closeNodes closes the connection to every node
+func (coordinator *Coordinator) closeNodes() {
+	coordinator.nodesMtx.Lock()
+	defer coordinator.nodesMtx.Unlock()
+
+	for _, node := range coordinator.Nodes {
+		node.conn.Close()
+	}
+	for _, node := range coordinator.draining {
+		node.conn.Close()
+	}
+}
+
+func addNode(coordinator *Coordinator, nodeID string, info Node) {
+	if err := coordinator.AddNode(nodeID, info); err != nil {
+		fmt.Printf("Add node failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Node[%v] added\n", nodeID)
+}
+
+func removeNode(coordinator *Coordinator, nodeID string) {
+	if err := coordinator.RemoveNode(nodeID); err != nil {
+		fmt.Printf("Remove node failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Node[%v] removed\n", nodeID)
+}
+
+func setNodeWeight(coordinator *Coordinator, nodeID string, weight int) {
+	if err := coordinator.SetNodeWeight(nodeID, weight); err != nil {
+		fmt.Printf("Set weight failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Node[%v] weight set to %d\n", nodeID, weight)
+}
+
+func listNodes(coordinator *Coordinator) {
+	metadata := coordinator.Metadata()
+	members := metadata.Nodes
+	nodeIDs := make([]string, 0, len(members))
+	for nodeID := range members {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
+		if members[nodeID].Leaving {
+			fmt.Printf("Node[%v] %s:%d Leaving\n", nodeID, members[nodeID].Host, members[nodeID].Port)
+			continue
+		}
+		fmt.Printf("Node[%v] %s:%d Weight : %d\n", nodeID, members[nodeID].Host, members[nodeID].Port, max(members[nodeID].Weight, 1))
+	}
+	fmt.Printf("Ring version : %v Virtual nodes : %v Replication factor : %v\n", metadata.RingVersion, metadata.VirtualNodes, metadata.ReplicationFactor)
+	fmt.Printf("Placement : %v Hash : %v\n", metadata.Placement.Strategy, metadata.Placement.Hash)
+	if metadata.Leader != "" {
+		fmt.Printf("Leader : %v\n", metadata.Leader)
+	}
+}
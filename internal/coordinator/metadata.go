@@ -0,0 +1,435 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+const raftProposeSeconds = 10
+
+// Changes of the cluster metadata
+const (
+	metadataBootstrap   = "BOOTSTRAP"
+	metadataAddNode     = "ADDNODE"
+	metadataRemoveNode  = "REMOVENODE"
+	metadataDrainNode   = "DRAINNODE"
+	metadataForgetNode  = "FORGETNODE"
+	metadataSetWeight   = "SETWEIGHT"
+	metadataResumeDrain = "RESUMEDRAIN"
+	metadataMigrated    = "MIGRATED"
+)
+
+// metadataChange is a change of the cluster metadata, an entry of the Raft log when the
+// coordinator is replicated. BOOTSTRAP sets the first membership of the cluster, or the metadata
+// of a snapshot with its ring version and the migrations planned so far in Plan. RESUMEDRAIN
+// moves what a draining node still holds, MIGRATED marks the keys of a migration moved.
+type metadataChange struct {
+	Op                string
+	NodeID            string                 `json:",omitempty"`
+	Node              Node                   `json:",omitempty"`
+	Nodes             map[string]Node        `json:",omitempty"`
+	VirtualNodes      int                    `json:",omitempty"`
+	Placement         *utils.PlacementConfig `json:",omitempty"`
+	ReplicationFactor int                    `json:",omitempty"`
+	To                string                 `json:",omitempty"` // MIGRATED from NodeID to To
+	Plan              uint64                 `json:",omitempty"` // MIGRATED, migrations planned when it was
+	RingVersion       uint64                 `json:",omitempty"` // BOOTSTRAP of a snapshot
+}
+
+// Metadata is the cluster metadata applied by a coordinator replica
+type Metadata struct {
+	Nodes             map[string]Node // Draining nodes are marked as leaving
+	RingVersion       uint64          // Incremented by every change of the ring
+	VirtualNodes      int
+	Placement         utils.PlacementConfig
+	ReplicationFactor int
+	Leader            string // Raft leader, empty when the coordinator is not replicated or none is known
+}
+
+// Metadata returns the cluster metadata known to this replica
+func (coordinator *Coordinator) Metadata() Metadata {
+	coordinator.nodesMtx.RLock()
+	placement := coordinator.Placement.Config()
+	metadata := Metadata{
+		Nodes:             coordinator.membership(),
+		RingVersion:       coordinator.ringVersion,
+		VirtualNodes:      placement.VirtualNodes,
+		Placement:         placement,
+		ReplicationFactor: coordinator.replicationFactor,
+	}
+	coordinator.nodesMtx.RUnlock()
+
+	if nil != coordinator.raft {
+		metadata.Leader, _ = coordinator.raft.Leader()
+	}
+	return metadata
+}
+
+// newPlacement creates the placement of the config, the virtual nodes apply to rings
+func newPlacement(config utils.PlacementConfig, virtualNodes int) (utils.Placement, error) {
+	config.VirtualNodes = virtualNodes
+	placement, err := utils.NewPlacement(config)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid placement : %w", err)
+	}
+	return placement, nil
+}
+
+// connectNode connects to a storage node without waiting for it, replicas apply the metadata
+// even while a node is down
+func connectNode(info Node) *NodeConnection {
+	// Dialing without blocking only fails on a malformed address, the RPCs report it
+	conn, _ := grpc.Dial(fmt.Sprintf("%s:%d", info.Host, info.Port), grpc.WithInsecure())
+	return &NodeConnection{
+		conn:   conn,
+		client: pb.NewStorageClient(conn),
+		info:   info,
+	}
+}
+
+// changeMetadata applies a change on this coordinator, through the Raft log when it is replicated
+func (coordinator *Coordinator) changeMetadata(change metadataChange) error {
+	if nil == coordinator.raft {
+		return coordinator.applyChange(change, true)
+	}
+
+	command, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), raftProposeSeconds*time.Second)
+	defer cancel()
+	response, err := coordinator.raft.Propose(ctx, command)
+	if err != nil {
+		return err
+	}
+	if err, ok := response.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// applyCommand applies a change committed in the Raft log
+func (coordinator *Coordinator) applyCommand(command []byte, leading bool) interface{} {
+	var change metadataChange
+	if err := json.Unmarshal(command, &change); err != nil {
+		log.Printf("Error parsing metadata change : %v", err)
+		return err
+	}
+	if err := coordinator.applyChange(change, leading); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyChange changes the nodes and the ring. Every replica plans the migrations of the ranges
+// that changed owner, the leading one moves their keys, pushes the ring to the nodes and keeps
+// the connection of a node it drains. Without Raft the membership is saved before it is used.
+func (coordinator *Coordinator) applyChange(change metadataChange, leading bool) error {
+	coordinator.migrationMtx.Lock()
+	coordinator.nodesMtx.Lock()
+
+	err := coordinator.applyLocked(change, leading)
+	coordinator.nodesMtx.Unlock()
+	if err != nil {
+		coordinator.migrationMtx.Unlock()
+		return err
+	}
+	if leading {
+		coordinator.runMigrations()
+	}
+	moving := coordinator.migrating()
+	coordinator.migrationMtx.Unlock()
+
+	switch {
+	case !leading || change.Op == metadataForgetNode || change.Op == metadataResumeDrain:
+	case change.Op == metadataMigrated:
+		// The old owners stop accepting the keys that moved away
+		if !moving {
+			go coordinator.pushRing(false)
+		}
+	default:
+		go coordinator.pushRing(moving)
+	}
+	if change.Op != metadataMigrated {
+		coordinator.membershipChanged()
+	}
+	return nil
+}
+
+// applyLocked applies a change and plans the migrations it needs. Caller must hold migrationMtx
+// and nodesMtx.
+func (coordinator *Coordinator) applyLocked(change metadataChange, leading bool) error {
+	switch change.Op {
+	case metadataBootstrap:
+		if coordinator.bootstrapped {
+			return nil
+		}
+		config := utils.PlacementConfig{}
+		if nil != change.Placement {
+			config = *change.Placement
+		}
+		placement, err := newPlacement(config, change.VirtualNodes)
+		if err != nil {
+			return err
+		}
+		coordinator.Placement = placement
+		coordinator.replicationFactor = change.ReplicationFactor
+		for nodeID, info := range change.Nodes {
+			if info.Leaving {
+				coordinator.draining[nodeID] = coordinator.routed(nodeID, connectNode(info))
+				continue
+			}
+			coordinator.Nodes[nodeID] = coordinator.routed(nodeID, connectNode(info))
+			coordinator.Placement.AddWeightedNode(nodeID, info.Weight)
+		}
+		coordinator.ringVersion = 1
+		coordinator.bootstrapped = true
+		log.Printf("Cluster bootstrapped with %d nodes", len(change.Nodes))
+		return nil
+
+	case metadataAddNode:
+		if _, ok := coordinator.Nodes[change.NodeID]; ok {
+			return fmt.Errorf("%w: %s", ErrNodeExists, change.NodeID)
+		}
+		members := coordinator.membership()
+		members[change.NodeID] = change.Node
+		if err := coordinator.saveMembership(members); err != nil {
+			return err
+		}
+
+		before := coordinator.Placement.Copy()
+		coordinator.Nodes[change.NodeID] = coordinator.routed(change.NodeID, connectNode(change.Node))
+		coordinator.Placement.AddWeightedNode(change.NodeID, change.Node.Weight)
+		coordinator.ringVersion++
+		log.Printf("Node[%v] added at %s:%d", change.NodeID, change.Node.Host, change.Node.Port)
+		coordinator.plan(coordinator.planMigrations(before, coordinator.Placement, "", nil, false))
+		return nil
+
+	case metadataRemoveNode, metadataDrainNode:
+		drain := change.Op == metadataDrainNode
+		node, ok := coordinator.Nodes[change.NodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownNode, change.NodeID)
+		}
+		if len(coordinator.Nodes) == 1 {
+			return ErrLastNode
+		}
+		members := coordinator.membership()
+		delete(members, change.NodeID)
+		if drain {
+			info := node.info
+			info.Leaving = true
+			members[change.NodeID] = info
+		}
+		if err := coordinator.saveMembership(members); err != nil {
+			return err
+		}
+
+		if drain {
+			coordinator.draining[change.NodeID] = node
+		}
+		before := coordinator.Placement.Copy()
+		delete(coordinator.Nodes, change.NodeID)
+		coordinator.Placement.RemoveNode(change.NodeID)
+		coordinator.ringVersion++
+		log.Printf("Node[%v] removed", change.NodeID)
+
+		migrations := coordinator.planMigrations(before, coordinator.Placement, change.NodeID, node, !drain)
+		if len(migrations) == 0 && !drain {
+			node.conn.Close()
+		}
+		coordinator.plan(migrations)
+		return nil
+
+	case metadataSetWeight:
+		node, ok := coordinator.Nodes[change.NodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownNode, change.NodeID)
+		}
+		info := node.info
+		info.Weight = change.Node.Weight
+		members := coordinator.membership()
+		members[change.NodeID] = info
+		if err := coordinator.saveMembership(members); err != nil {
+			return err
+		}
+
+		before := coordinator.Placement.Copy()
+		node.info = info
+		coordinator.Placement.SetWeight(change.NodeID, info.Weight)
+		coordinator.ringVersion++
+		log.Printf("Node[%v] weight set to %d", change.NodeID, info.Weight)
+		coordinator.plan(coordinator.planMigrations(before, coordinator.Placement, "", nil, false))
+		return nil
+
+	case metadataForgetNode:
+		node, ok := coordinator.draining[change.NodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownNode, change.NodeID)
+		}
+		delete(coordinator.draining, change.NodeID)
+		if err := coordinator.saveMembership(coordinator.membership()); err != nil {
+			coordinator.draining[change.NodeID] = node
+			return err
+		}
+		// The leader shuts the node down before closing the connection
+		if !leading {
+			node.conn.Close()
+		}
+		return nil
+
+	case metadataResumeDrain:
+		node, ok := coordinator.draining[change.NodeID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownNode, change.NodeID)
+		}
+		alone, err := utils.NewPlacement(coordinator.Placement.Config())
+		if err != nil {
+			return err
+		}
+		alone.AddNode(change.NodeID)
+		coordinator.plan(coordinator.planMigrations(alone, coordinator.Placement, change.NodeID, node, false))
+		return nil
+
+	case metadataMigrated:
+		coordinator.migrated(change.NodeID, change.To, change.Plan)
+		return nil
+	}
+	return fmt.Errorf("Unknown metadata change %s", change.Op)
+}
+
+// snapshotMetadata returns the metadata as the change restoring it. The migrations are not part
+// of it, no snapshot is taken while keys move.
+func (coordinator *Coordinator) snapshotMetadata() ([]byte, bool) {
+	coordinator.migrationMtx.Lock()
+	defer coordinator.migrationMtx.Unlock()
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	if !coordinator.bootstrapped || coordinator.migrating() {
+		return nil, false
+	}
+	placement := coordinator.Placement.Config()
+	data, err := json.Marshal(metadataChange{
+		Op:                metadataBootstrap,
+		Nodes:             coordinator.membership(),
+		VirtualNodes:      placement.VirtualNodes,
+		Placement:         &placement,
+		ReplicationFactor: coordinator.replicationFactor,
+		RingVersion:       coordinator.ringVersion,
+		Plan:              coordinator.plans,
+	})
+	if err != nil {
+		log.Printf("Error saving metadata snapshot : %v", err)
+		return nil, false
+	}
+	return data, true
+}
+
+// restoreMetadata replaces the metadata with a snapshot, the connections are dialed again
+func (coordinator *Coordinator) restoreMetadata(data []byte) error {
+	var change metadataChange
+	if err := json.Unmarshal(data, &change); err != nil {
+		return fmt.Errorf("Error parsing metadata snapshot : %w", err)
+	}
+
+	coordinator.migrationMtx.Lock()
+	coordinator.nodesMtx.Lock()
+	for _, group := range []map[string]*NodeConnection{coordinator.Nodes, coordinator.draining} {
+		for _, node := range group {
+			node.conn.Close()
+		}
+	}
+	coordinator.Nodes = make(map[string]*NodeConnection)
+	coordinator.draining = make(map[string]*NodeConnection)
+	coordinator.migrations = nil
+	coordinator.bootstrapped = false
+	err := coordinator.applyLocked(change, false)
+	if nil == err {
+		coordinator.ringVersion = change.RingVersion
+		coordinator.plans = change.Plan
+	}
+	coordinator.nodesMtx.Unlock()
+	coordinator.migrationMtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Metadata restored at ring version %d", change.RingVersion)
+	coordinator.membershipChanged()
+	return nil
+}
+
+// saveMembership saves the membership file of a coordinator that is not replicated, the Raft log
+// holds it otherwise. Caller must hold nodesMtx.
+func (coordinator *Coordinator) saveMembership(members map[string]Node) error {
+	if nil != coordinator.raft {
+		return nil
+	}
+	return saveMembership(coordinator.membershipFile, members)
+}
+
+// bootstrap writes the first membership in the Raft log, called when a replica starts leading.
+// A bootstrapped cluster resumes the migrations the last leader left unfinished.
+func (coordinator *Coordinator) bootstrap() {
+	coordinator.nodesMtx.RLock()
+	bootstrapped := coordinator.bootstrapped
+	coordinator.nodesMtx.RUnlock()
+	if bootstrapped {
+		// The last leader may have died before pushing its last change or moving the keys
+		coordinator.migrationMtx.Lock()
+		coordinator.runMigrations()
+		moving := coordinator.migrating()
+		coordinator.migrationMtx.Unlock()
+		go coordinator.pushRing(moving)
+		return
+	}
+
+	if err := coordinator.changeMetadata(coordinator.bootstrapChange); err != nil {
+		log.Printf("Bootstrapping the cluster failed : %v", err)
+	}
+}
+
+// waitBootstrap waits until the replica applied the first membership or the timeout expires
+func (coordinator *Coordinator) waitBootstrap(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		coordinator.nodesMtx.RLock()
+		bootstrapped := coordinator.bootstrapped
+		coordinator.nodesMtx.RUnlock()
+		if bootstrapped {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(drainPollMillis * time.Millisecond)
+	}
+}
+
+// forward runs an admin call on the leader when this replica does not lead. Returns false when
+// the call has to run here.
+func (coordinator *Coordinator) forward(ctx context.Context, call func(pb.CoordinatorClient) error) (bool, error) {
+	if nil == coordinator.raft || coordinator.raft.IsLeader() {
+		return false, nil
+	}
+	leaderID, address := coordinator.raft.Leader()
+	if leaderID == "" || address == "" {
+		return true, fmt.Errorf("%w: no leader elected", ErrNotLeader)
+	}
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return true, fmt.Errorf("Error establishing connection with leader %s : %w", address, err)
+	}
+	defer conn.Close()
+	return true, call(pb.NewCoordinatorClient(conn))
+}
@@ -0,0 +1,560 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+const migrationRetrySeconds = 1
+
+var ErrMigrating = errors.New("keys are still moving after the last membership change")
+
+// Migration states
+const (
+	migrationTransferring = "TRANSFERRING"
+	migrationDropping     = "DROPPING"
+	migrationDone         = "DONE"
+)
+
+// MigrationStatus is the progress of the keys moving from one node to another, To is empty when
+// From only drops the keys it no longer replicates
+type MigrationStatus struct {
+	From        string
+	To          string
+	Ranges      int    // Copied to To
+	Transferred uint64 // Keys sent by From
+	Imported    uint64 // Keys written on To
+	Skipped     uint64 // Keys already written on To after the ring changed
+	Dropped     uint64 // Old copies deleted from From
+	State       string
+	Err         error // Last failure, the migration is retried
+}
+
+// migration copies the keys of ranges from a node to a node that newly replicates them, then
+// drops from the node the keys it no longer replicates once every migration of the plan copied
+// its keys. Reads of keys in the ranges that are missing on the new owner are served by the old
+// one until the old copies are dropped. Between placements that are not rings the keys are those
+// the new placement replicates on To, and on From for the drop. Every replica keeps the
+// migrations, the counters are only kept by the leader moving the keys.
+type migration struct {
+	status   MigrationStatus
+	plan     uint64          // Number of the membership change that planned it
+	running  bool            // Moved by this replica
+	source   *NodeConnection // Stays open when From left the cluster
+	ranges   []utils.HashRange
+	drops    []utils.HashRange // Ranges From no longer replicates, between rings
+	dropping bool              // From drops the keys it no longer replicates
+	position utils.HashFunc    // Position of the keys on the ring of the ranges
+	hash     string            // Name of the hash function of the ring
+	before   utils.Placement   // Placements moving keys by replicas, nil between rings
+	after    utils.Placement
+	replicas int  // Replicas of a key
+	closed   bool // From left the cluster, its connection is closed once the keys moved
+}
+
+// appendRange appends a range to ranges, merged with the last one when they are adjacent
+func appendRange(ranges []utils.HashRange, r utils.HashRange) []utils.HashRange {
+	if last := len(ranges) - 1; last >= 0 && ranges[last].End == r.Start {
+		ranges[last].End = r.End
+		return ranges
+	}
+	return append(ranges, r)
+}
+
+// copySource picks the replica the keys are copied from, a removed node only when it was the only one
+func copySource(replicas []string, removedID string) string {
+	for _, nodeID := range replicas {
+		if nodeID != removedID {
+			return nodeID
+		}
+	}
+	return replicas[0]
+}
+
+// planMigrations compares the replicas of the keys before and after a change. Keys are copied to
+// every node that newly replicates them, from one of their old replicas, and dropped from the
+// nodes that no longer do. Caller must hold nodesMtx with the connection of a removed node in
+// removed, closed once its keys moved when closeRemoved is set.
+func (coordinator *Coordinator) planMigrations(before, after utils.Placement, removedID string, removed *NodeConnection, closeRemoved bool) []*migration {
+	replicas := max(coordinator.replicationFactor, 1)
+	migrations := []*migration{}
+	byNodes := make(map[[2]string]*migration)
+	get := func(from, to string) *migration {
+		pair := [2]string{from, to}
+		if m, ok := byNodes[pair]; ok {
+			return m
+		}
+		m := &migration{status: MigrationStatus{From: from, To: to, State: migrationTransferring}, replicas: replicas}
+		if from == removedID {
+			m.source, m.closed = removed, closeRemoved
+		} else {
+			m.source = coordinator.Nodes[from]
+		}
+		byNodes[pair] = m
+		migrations = append(migrations, m)
+		return m
+	}
+	// The drops of a node go with one of the migrations from it
+	dropper := func(from string) *migration {
+		for _, m := range migrations {
+			if m.status.From == from {
+				return m
+			}
+		}
+		return get(from, "")
+	}
+
+	beforeRing, isRing := before.(*utils.ConsistentHash)
+	afterRing, bothRings := after.(*utils.ConsistentHash)
+	if isRing && bothRings {
+		drops := make(map[string][]utils.HashRange)
+		dropping := []string{}
+		for _, move := range utils.MovedReplicas(beforeRing, afterRing, replicas) {
+			from := copySource(move.Before, removedID)
+			for _, to := range move.After {
+				if slices.Contains(move.Before, to) {
+					continue
+				}
+				m := get(from, to)
+				m.ranges = appendRange(m.ranges, move.Range)
+			}
+			for _, old := range move.Before {
+				if slices.Contains(move.After, old) {
+					continue
+				}
+				if _, ok := drops[old]; !ok {
+					dropping = append(dropping, old)
+				}
+				drops[old] = appendRange(drops[old], move.Range)
+			}
+		}
+		for _, old := range dropping {
+			m := dropper(old)
+			m.drops, m.dropping = drops[old], true
+		}
+		for _, m := range migrations {
+			m.position, m.hash = afterRing.Position, afterRing.Config().Hash
+			m.status.Ranges = len(m.ranges)
+		}
+	} else {
+		// Keys of any old replica may move to any new one
+		after = after.Copy()
+		for _, from := range before.ListNodes() {
+			for _, to := range after.ListNodes() {
+				if from != to {
+					get(from, to)
+				}
+			}
+			dropper(from).dropping = true
+		}
+		for _, m := range migrations {
+			m.before, m.after = before, after
+		}
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		if migrations[i].status.From != migrations[j].status.From {
+			return migrations[i].status.From < migrations[j].status.From
+		}
+		return migrations[i].status.To < migrations[j].status.To
+	})
+	return migrations
+}
+
+// contains reports whether a key is copied by the migration
+func (m *migration) contains(key string) bool {
+	if m.status.To == "" {
+		return false
+	}
+	if nil == m.after {
+		position := m.position(key)
+		for _, r := range m.ranges {
+			if r.Contains(position) {
+				return true
+			}
+		}
+		return false
+	}
+	before, err := m.before.GetNodes(key, m.replicas)
+	if err != nil || !slices.Contains(before, m.status.From) || slices.Contains(before, m.status.To) {
+		return false
+	}
+	after, err := m.after.GetNodes(key, m.replicas)
+	return err == nil && slices.Contains(after, m.status.To)
+}
+
+// storageRanges converts the ranges of a migration between rings
+func storageRanges(ranges []utils.HashRange) []*pb.StorageHashRange {
+	converted := make([]*pb.StorageHashRange, 0, len(ranges))
+	for _, r := range ranges {
+		converted = append(converted, &pb.StorageHashRange{Start: r.Start, End: r.End})
+	}
+	return converted
+}
+
+// storagePlacement returns the placement the source selects the keys of To with, nil between rings
+func (m *migration) storagePlacement() *pb.StoragePlacement {
+	if nil == m.after {
+		return nil
+	}
+	return utils.PlacementToProto(m.after)
+}
+
+// migrating reports whether keys are still moving. Caller must hold migrationMtx.
+func (coordinator *Coordinator) migrating() bool {
+	for _, m := range coordinator.migrations {
+		if m.status.State != migrationDone {
+			return true
+		}
+	}
+	return false
+}
+
+// isMigrating reports whether keys are still moving
+func (coordinator *Coordinator) isMigrating() bool {
+	coordinator.migrationMtx.Lock()
+	defer coordinator.migrationMtx.Unlock()
+
+	return coordinator.migrating()
+}
+
+// plan replaces the finished migrations with the ones of a membership change. Caller must hold migrationMtx.
+func (coordinator *Coordinator) plan(migrations []*migration) {
+	coordinator.plans++
+	for _, m := range migrations {
+		m.plan = coordinator.plans
+	}
+	coordinator.migrations = migrations
+}
+
+// runMigrations moves the keys of the unfinished migrations this replica does not move yet, run
+// by the leader. Caller must hold migrationMtx.
+func (coordinator *Coordinator) runMigrations() {
+	for _, m := range coordinator.migrations {
+		if m.status.State == migrationDone || m.running {
+			continue
+		}
+		m.running = true
+		switch {
+		case m.status.To == "":
+			log.Printf("Dropping the keys Node[%v] no longer replicates", m.status.From)
+		case nil == m.after:
+			log.Printf("Moving %d ranges from Node[%v] to Node[%v]", len(m.ranges), m.status.From, m.status.To)
+		default:
+			log.Printf("Moving keys placed by %s from Node[%v] to Node[%v]", m.after.Config().Strategy, m.status.From, m.status.To)
+		}
+		go coordinator.migrate(m)
+	}
+}
+
+// migrate moves the keys and drops the old copies, retrying until both succeed, then marks the
+// migration done through the Raft log. Stops when the replica no longer leads, the next leader
+// moves the keys again.
+func (coordinator *Coordinator) migrate(m *migration) {
+	defer func() {
+		coordinator.migrationMtx.Lock()
+		m.running = false
+		coordinator.migrationMtx.Unlock()
+	}()
+
+	for {
+		err := coordinator.transfer(m)
+		if err == nil {
+			err = coordinator.waitCopies(m)
+		}
+		if err == nil {
+			err = coordinator.drop(m)
+		}
+		if err == nil {
+			err = coordinator.changeMetadata(metadataChange{Op: metadataMigrated, NodeID: m.status.From, To: m.status.To, Plan: m.plan})
+		}
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrNotLeader) {
+			log.Printf("Stopped moving keys from Node[%v] to Node[%v] : %v", m.status.From, m.status.To, err)
+			return
+		}
+
+		log.Printf("Moving keys from Node[%v] to Node[%v] failed : %v", m.status.From, m.status.To, err)
+		coordinator.migrationMtx.Lock()
+		m.status.Err = err
+		coordinator.migrationMtx.Unlock()
+		time.Sleep(migrationRetrySeconds * time.Second)
+	}
+	log.Printf("Moved keys from Node[%v] to Node[%v]", m.status.From, m.status.To)
+}
+
+// migrated marks a migration of the current plan done. The connection of a node that left is
+// closed once no migration moves its keys. Caller must hold migrationMtx.
+func (coordinator *Coordinator) migrated(from, to string, plan uint64) {
+	var done *migration
+	for _, m := range coordinator.migrations {
+		if m.plan == plan && m.status.From == from && m.status.To == to && m.status.State != migrationDone {
+			done = m
+		}
+	}
+	if nil == done {
+		return
+	}
+	done.status.State = migrationDone
+	done.status.Err = nil
+
+	// The connection is shared by the migrations from the same node
+	closing := done.closed
+	for _, other := range coordinator.migrations {
+		closing = closing && (other.source != done.source || other.status.State == migrationDone)
+	}
+	if closing {
+		done.source.conn.Close()
+	}
+}
+
+// transfer streams the keys of the ranges from the old replica to the new one
+func (coordinator *Coordinator) transfer(m *migration) error {
+	coordinator.migrationMtx.Lock()
+	state := m.status.State
+	if m.status.To == "" {
+		m.status.State = migrationDropping
+	}
+	coordinator.migrationMtx.Unlock()
+	if state != migrationTransferring || m.status.To == "" {
+		return nil
+	}
+
+	target, ok := coordinator.node(m.status.To)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownNode, m.status.To)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := m.source.client.TransferKeys(ctx, &pb.StorageTransferKeysRequest{
+		Ranges:            storageRanges(m.ranges),
+		Hash:              m.hash,
+		Placement:         m.storagePlacement(),
+		Owner:             m.status.To,
+		ReplicationFactor: uint32(m.replicas),
+	})
+	if err != nil {
+		return err
+	}
+	sink, err := target.client.ImportKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var transferred uint64
+	for {
+		entry, err := source.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := sink.Send(entry); err != nil {
+			// The import failed, its status comes with CloseAndRecv
+			break
+		}
+		transferred++
+	}
+	res, err := sink.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	coordinator.migrationMtx.Lock()
+	m.status.Transferred = transferred
+	m.status.Imported, m.status.Skipped = res.Imported, res.Skipped
+	m.status.State = migrationDropping
+	coordinator.migrationMtx.Unlock()
+	return nil
+}
+
+// waitCopies waits until every migration of the plan copied its keys, the node a migration drops
+// keys from may be the source of another one. Fails with ErrNotLeader once the replica no longer leads.
+func (coordinator *Coordinator) waitCopies(m *migration) error {
+	for {
+		copying := false
+		coordinator.migrationMtx.Lock()
+		for _, other := range coordinator.migrations {
+			copying = copying || (other.plan == m.plan && other.status.State == migrationTransferring)
+		}
+		coordinator.migrationMtx.Unlock()
+		if !copying {
+			return nil
+		}
+		if nil != coordinator.raft && !coordinator.raft.IsLeader() {
+			return ErrNotLeader
+		}
+		time.Sleep(drainPollMillis * time.Millisecond)
+	}
+}
+
+// drop deletes the copies of the keys From no longer replicates
+func (coordinator *Coordinator) drop(m *migration) error {
+	if !m.dropping {
+		return nil
+	}
+	res, err := m.source.client.DropKeys(context.Background(), &pb.StorageDropKeysRequest{
+		Ranges:            storageRanges(m.drops),
+		Hash:              m.hash,
+		Placement:         m.storagePlacement(),
+		Owner:             m.status.From,
+		ReplicationFactor: uint32(m.replicas),
+	})
+	if err != nil {
+		return err
+	}
+
+	coordinator.migrationMtx.Lock()
+	m.status.Dropped = res.KeysDeleted
+	coordinator.migrationMtx.Unlock()
+	return nil
+}
+
+// moving returns the migration of a key to nodeID that is still in flight, only one still
+// transferring keys when transferring is set
+func (coordinator *Coordinator) moving(key, nodeID string, transferring bool) (*migration, bool) {
+	coordinator.migrationMtx.Lock()
+	defer coordinator.migrationMtx.Unlock()
+
+	for _, m := range coordinator.migrations {
+		if m.status.State == migrationDone || m.status.To != nodeID {
+			continue
+		}
+		if transferring && m.status.State != migrationTransferring {
+			continue
+		}
+		if m.contains(key) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// previousOwner returns the node a key is moving from to nodeID while its migration is in flight
+func (coordinator *Coordinator) previousOwner(key, nodeID string) (string, *NodeConnection, bool) {
+	m, ok := coordinator.moving(key, nodeID, false)
+	if !ok {
+		return "", nil, false
+	}
+	return m.status.From, m.source, true
+}
+
+// pull copies a key still moving to nodeID from the previous owner before an operation on it, so
+// the operation sees the moved value and the transfer can't bring back what it replaced. The
+// import keeps whichever write of the two nodes is later.
+func (coordinator *Coordinator) pull(ctx context.Context, key, nodeID string, node *NodeConnection) error {
+	m, ok := coordinator.moving(key, nodeID, true)
+	if !ok || nil == node {
+		return nil
+	}
+
+	source, err := m.source.client.TransferKeys(ctx, &pb.StorageTransferKeysRequest{Keys: []string{key}})
+	if err != nil {
+		return fmt.Errorf("Error pulling moving key %s from Node[%v] : %w", key, m.status.From, err)
+	}
+	entry, err := source.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error pulling moving key %s from Node[%v] : %w", key, m.status.From, err)
+	}
+	sink, err := node.client.ImportKeys(ctx)
+	if err == nil {
+		if err = sink.Send(entry); err == nil || err == io.EOF {
+			_, err = sink.CloseAndRecv()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Error importing moving key %s on Node[%v] : %w", key, nodeID, err)
+	}
+	return nil
+}
+
+// ownerFor is owner for an operation on a key, a key still moving to the owner is pulled first
+func (coordinator *Coordinator) ownerFor(ctx context.Context, key string) (string, *NodeConnection, error) {
+	nodeID, node, err := coordinator.owner(key)
+	if err != nil {
+		return "", nil, err
+	}
+	return nodeID, node, coordinator.pull(ctx, key, nodeID, node)
+}
+
+// Get reads a key from its replicas, see read. A key still moving to the owner is read from the
+// previous one. Returns the node the value was read from. Keys versioned with vector clocks are
+// read with GetVersions.
+func (coordinator *Coordinator) Get(ctx context.Context, key string) (string, *pb.StorageGetResponse, error) {
+	if coordinator.VectorClocks(key) {
+		return "", nil, fmt.Errorf("%w: %s, read it with GetVersions", ErrVersioned, key)
+	}
+	replicas, nodes, _, err := coordinator.replicas(key)
+	if err != nil {
+		return "", nil, err
+	}
+	nodeID, res, err := coordinator.read(ctx, key, replicas, nodes)
+	if err != nil || res.Found || res.Deleted {
+		return nodeID, res, err
+	}
+
+	if fromID, from, ok := coordinator.previousOwner(key, replicas[0]); ok {
+		previous, err := from.client.Get(ctx, &pb.StorageGetRequest{Key: key})
+		if err == nil && previous.Found {
+			return fromID, previous, nil
+		}
+	}
+	return nodeID, res, nil
+}
+
+// Migrations returns the progress of the keys moved by the last membership change
+func (coordinator *Coordinator) Migrations() []MigrationStatus {
+	coordinator.migrationMtx.Lock()
+	defer coordinator.migrationMtx.Unlock()
+
+	statuses := make([]MigrationStatus, 0, len(coordinator.migrations))
+	for _, m := range coordinator.migrations {
+		statuses = append(statuses, m.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].From != statuses[j].From {
+			return statuses[i].From < statuses[j].From
+		}
+		return statuses[i].To < statuses[j].To
+	})
+	return statuses
+}
+
+func migrations(coordinator *Coordinator) {
+	statuses := coordinator.Migrations()
+	if len(statuses) == 0 {
+		fmt.Println("No keys moved")
+		return
+	}
+	for _, status := range statuses {
+		if status.To == "" {
+			fmt.Printf("Node[%v] State : %v Dropped : %v\n", status.From, status.State, status.Dropped)
+			if nil != status.Err {
+				fmt.Printf("  Retrying after : %v\n", status.Err)
+			}
+			continue
+		}
+		fmt.Printf("Node[%v] -> Node[%v] Ranges : %v State : %v Transferred : %v Imported : %v Skipped : %v Dropped : %v\n",
+			status.From, status.To, status.Ranges, status.State, status.Transferred, status.Imported, status.Skipped, status.Dropped)
+		if nil != status.Err {
+			fmt.Printf("  Retrying after : %v\n", status.Err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package coordinator
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"github.com/b1acktothefuture/dht-system/pkg/client"
+	"google.golang.org/grpc"
+)
+
+// setRingRequest returns the placement pushed to a node. Caller must hold nodesMtx.
+func (coordinator *Coordinator) setRingRequest(nodeID string, moving bool) *pb.StorageSetRingRequest {
+	request := &pb.StorageSetRingRequest{
+		NodeID:            nodeID,
+		RingVersion:       coordinator.ringVersion,
+		Moving:            moving,
+		ReplicationFactor: uint32(coordinator.replicationFactor),
+	}
+	if ring, ok := coordinator.Placement.(*utils.ConsistentHash); ok {
+		request.Ring = ring.Snapshot().ToProto()
+		request.Ring.Version = coordinator.ringVersion
+	} else {
+		request.Placement = utils.PlacementToProto(coordinator.Placement)
+	}
+	return request
+}
+
+// pushRing sends the placement to every node, draining ones included, so they reject the keys
+// they do not own. moving keeps the keys of the previous placement accepted while they move.
+// Returns the highest ring version the nodes hold.
+func (coordinator *Coordinator) pushRing(moving bool) uint64 {
+	coordinator.nodesMtx.RLock()
+	nodes := make(map[string]*NodeConnection, len(coordinator.Nodes)+len(coordinator.draining))
+	requests := make(map[string]*pb.StorageSetRingRequest, len(nodes))
+	for _, group := range []map[string]*NodeConnection{coordinator.Nodes, coordinator.draining} {
+		for nodeID, node := range group {
+			nodes[nodeID] = node
+			requests[nodeID] = coordinator.setRingRequest(nodeID, moving)
+		}
+	}
+	coordinator.nodesMtx.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+	defer cancel()
+
+	var mtx sync.Mutex
+	var highest uint64
+	var wg sync.WaitGroup
+	for nodeID, node := range nodes {
+		wg.Add(1)
+		go func(nodeID string, node *NodeConnection) {
+			defer wg.Done()
+			res, err := node.client.SetRing(ctx, requests[nodeID])
+			if err != nil {
+				log.Printf("Node[%v] ring push failed : %v", nodeID, err)
+				return
+			}
+			mtx.Lock()
+			highest = max(highest, res.RingVersion)
+			mtx.Unlock()
+		}(nodeID, node)
+	}
+	wg.Wait()
+	return highest
+}
+
+// syncRingVersion continues the ring version from the highest one the nodes hold. A coordinator
+// that is not replicated starts over at version 1 and the nodes ignore older rings.
+func (coordinator *Coordinator) syncRingVersion() {
+	highest := coordinator.pushRing(false)
+
+	coordinator.nodesMtx.Lock()
+	behind := highest > coordinator.ringVersion
+	if behind {
+		coordinator.ringVersion = highest + 1
+		log.Printf("Ring version continues at %d", coordinator.ringVersion)
+	}
+	coordinator.nodesMtx.Unlock()
+
+	if behind {
+		coordinator.pushRing(false)
+	}
+}
+
+// ownerConn is the connection to a node that fixes the routing of a request the node rejects as
+// not its own. A node with an older ring missed a push, it gets the current one and the request
+// is sent again. A node with a newer ring knows of a change this replica did not apply yet, the
+// request is sent to the owner it names.
+type ownerConn struct {
+	*grpc.ClientConn
+	coordinator *Coordinator
+	nodeID      string
+}
+
+// routed sends the requests to a node through an ownerConn
+func (coordinator *Coordinator) routed(nodeID string, node *NodeConnection) *NodeConnection {
+	node.client = pb.NewStorageClient(&ownerConn{ClientConn: node.conn, coordinator: coordinator, nodeID: nodeID})
+	return node
+}
+
+func (c *ownerConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	err := c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	detail, ok := client.WrongOwner(err)
+	if !ok {
+		return err
+	}
+
+	moving := c.coordinator.isMigrating()
+	c.coordinator.nodesMtx.RLock()
+	version := c.coordinator.ringVersion
+	request := c.coordinator.setRingRequest(c.nodeID, moving)
+	owner, known := c.coordinator.Nodes[detail.Owner]
+	c.coordinator.nodesMtx.RUnlock()
+
+	switch {
+	case detail.RingVersion < version:
+		log.Printf("Node[%v] holds ring version %d, pushing %d", c.nodeID, detail.RingVersion, version)
+		if _, pushErr := pb.NewStorageClient(c.ClientConn).SetRing(ctx, request); pushErr != nil {
+			return err
+		}
+		return c.ClientConn.Invoke(ctx, method, args, reply, opts...)
+	case detail.RingVersion > version && known:
+		log.Printf("Node[%v] holds ring version %d ahead of %d, sending to Node[%v]", c.nodeID, detail.RingVersion, version, detail.Owner)
+		return owner.conn.Invoke(ctx, method, args, reply, opts...)
+	}
+	return err
+}
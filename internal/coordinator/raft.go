@@ -0,0 +1,937 @@
+package coordinator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// Raft defaults, used when the config leaves them at 0
+const (
+	raftElectionTimeoutMillis = 1000
+	raftHeartbeatMillis       = 100
+	raftSnapshotEntries       = 1000
+)
+
+var ErrNotLeader = errors.New("coordinator replica is not the leader")
+
+// Raft roles
+const (
+	raftFollower  = "FOLLOWER"
+	raftCandidate = "CANDIDATE"
+	raftLeader    = "LEADER"
+)
+
+type raftEntry struct {
+	Term    uint64
+	Command []byte
+}
+
+// raftRecord is a line of the log file
+type raftRecord struct {
+	Index uint64
+	raftEntry
+}
+
+// raftState is saved in the state file whenever the term or the vote changes
+type raftState struct {
+	Term     uint64
+	VotedFor string
+	Log      []raftEntry `json:",omitempty"` // Only read, the state file held the whole log before the log file
+}
+
+// raftSnapshot replaces the entries up to Index, saved in the snapshot file
+type raftSnapshot struct {
+	Index uint64
+	Term  uint64
+	Data  []byte
+}
+
+// raftResult is what applying the entry at an index returned, for the replica waiting for it
+type raftResult struct {
+	term     uint64
+	response interface{}
+}
+
+// Raft replicates a log of commands among the coordinator replicas. Commands are proposed to the
+// leader and applied in the same order on every replica once a majority saved them. The applied
+// entries are compacted into a snapshot of the state they built.
+type Raft struct {
+	pb.UnimplementedRaftServer
+
+	mtx       sync.Mutex
+	id        string
+	peers     map[string]string // Replica ID to admin address, without this replica
+	stateFile string            // The log and the snapshot are saved next to it
+	logFile   *os.File
+
+	term     uint64
+	votedFor string
+	entries  []raftEntry // Entry i has index snapshot.Index+i+1
+	snapshot raftSnapshot
+
+	role             string
+	leader           string
+	commitIndex      uint64
+	lastApplied      uint64
+	nextIndex        map[string]uint64
+	matchIndex       map[string]uint64
+	replicating      map[string]bool
+	electionDeadline time.Time
+	electionTimeout  time.Duration
+	heartbeat        time.Duration
+	snapshotEntries  uint64
+
+	apply        func(command []byte, leading bool) interface{}
+	onLeader     func()
+	takeSnapshot func() ([]byte, bool)
+	restore      func(data []byte) error
+	waiters      map[uint64]chan raftResult
+	applyC       chan struct{}
+
+	connMtx sync.Mutex
+	conns   map[string]*grpc.ClientConn
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newRaft loads the saved state of the replica. apply is called for every committed command in
+// log order, leading is set on the leader of the term the command was proposed in. onLeader is
+// called once the replica leads and applied the entries of the previous leaders. takeSnapshot
+// returns the state built by the applied commands, false while it can not be taken, restore
+// replaces the state with a snapshot.
+func newRaft(config *Config, apply func(command []byte, leading bool) interface{}, onLeader func(), takeSnapshot func() ([]byte, bool), restore func(data []byte) error) (*Raft, error) {
+	electionTimeout, heartbeat := config.Raft.ElectionTimeoutMillis, config.Raft.HeartbeatMillis
+	if electionTimeout == 0 {
+		electionTimeout = raftElectionTimeoutMillis
+	}
+	if heartbeat == 0 {
+		heartbeat = raftHeartbeatMillis
+	}
+	snapshotEntries := config.Raft.SnapshotEntries
+	if snapshotEntries == 0 {
+		snapshotEntries = raftSnapshotEntries
+	}
+
+	raft := &Raft{
+		id:              config.Raft.ID,
+		peers:           make(map[string]string),
+		stateFile:       config.Raft.StateFile,
+		role:            raftFollower,
+		electionTimeout: time.Duration(electionTimeout) * time.Millisecond,
+		heartbeat:       time.Duration(heartbeat) * time.Millisecond,
+		snapshotEntries: uint64(snapshotEntries),
+		apply:           apply,
+		onLeader:        onLeader,
+		takeSnapshot:    takeSnapshot,
+		restore:         restore,
+		waiters:         make(map[uint64]chan raftResult),
+		applyC:          make(chan struct{}, 1),
+		conns:           make(map[string]*grpc.ClientConn),
+		done:            make(chan struct{}),
+	}
+	for peerID, address := range config.Raft.Peers {
+		if peerID != raft.id {
+			raft.peers[peerID] = address
+		}
+	}
+
+	if err := raft.load(); err != nil {
+		return nil, err
+	}
+	// The snapshot only holds committed entries
+	raft.commitIndex = raft.snapshot.Index
+	raft.resetElection()
+	if len(raft.peers) == 0 {
+		// Alone in the cluster, nobody else could lead
+		raft.electionDeadline = time.Now()
+	}
+	return raft, nil
+}
+
+func (raft *Raft) start() {
+	raft.wg.Add(2)
+	go raft.run()
+	go raft.applyCommitted()
+	// The snapshot is restored before the entries after it are applied
+	raft.signalApply()
+}
+
+func (raft *Raft) stop() {
+	close(raft.done)
+	raft.wg.Wait()
+
+	raft.connMtx.Lock()
+	for peerID, conn := range raft.conns {
+		conn.Close()
+		delete(raft.conns, peerID)
+	}
+	raft.connMtx.Unlock()
+
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+	if nil != raft.logFile {
+		raft.logFile.Close()
+		raft.logFile = nil
+	}
+}
+
+func (raft *Raft) logPath() string {
+	return raft.stateFile + ".log"
+}
+
+func (raft *Raft) snapshotPath() string {
+	return raft.stateFile + ".snapshot"
+}
+
+// load reads the state, the snapshot and the entries after it, a torn last line of the log file
+// is dropped
+func (raft *Raft) load() error {
+	if raft.stateFile == "" {
+		return nil
+	}
+
+	var state raftState
+	data, err := os.ReadFile(raft.stateFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Error reading raft state : %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("Error parsing raft state : %w", err)
+		}
+	}
+	raft.term, raft.votedFor = state.Term, state.VotedFor
+
+	data, err = os.ReadFile(raft.snapshotPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Error reading raft snapshot : %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &raft.snapshot); err != nil {
+			return fmt.Errorf("Error parsing raft snapshot : %w", err)
+		}
+	}
+
+	rewrite := false
+	file, err := os.Open(raft.logPath())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Error opening raft log : %w", err)
+	}
+	if err == nil {
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			var record raftRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				log.Printf("Raft[%v] dropping the log after index %d : %v", raft.id, raft.lastIndex(), err)
+				rewrite = true
+				break
+			}
+			// Entries the snapshot replaced are left when compacting stopped before the rewrite
+			if record.Index <= raft.snapshot.Index {
+				rewrite = true
+				continue
+			}
+			if record.Index != raft.lastIndex()+1 {
+				file.Close()
+				return fmt.Errorf("Error parsing raft log : entry %d follows %d", record.Index, raft.lastIndex())
+			}
+			raft.entries = append(raft.entries, record.raftEntry)
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("Error reading raft log : %w", err)
+		}
+	}
+
+	if len(state.Log) != 0 && len(raft.entries) == 0 && raft.snapshot.Index == 0 {
+		raft.entries = state.Log
+		if err := raft.saveState(); err != nil {
+			return err
+		}
+		rewrite = true
+	}
+	if rewrite {
+		return raft.rewriteLog()
+	}
+	raft.logFile, err = os.OpenFile(raft.logPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("Error opening raft log : %w", err)
+	}
+	return nil
+}
+
+// saveState saves the term and the vote. Caller must hold mtx.
+func (raft *Raft) saveState() error {
+	if raft.stateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(raftState{Term: raft.term, VotedFor: raft.votedFor})
+	if err != nil {
+		return err
+	}
+	return utils.WriteSynced(raft.stateFile, "raft state", data)
+}
+
+// appendLog appends the entries from index to the log file. Caller must hold mtx.
+func (raft *Raft) appendLog(index uint64) error {
+	if nil == raft.logFile {
+		return nil
+	}
+	data := []byte{}
+	for ; index <= raft.lastIndex(); index++ {
+		line, err := json.Marshal(raftRecord{Index: index, raftEntry: raft.entry(index)})
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if _, err := raft.logFile.Write(data); err != nil {
+		return fmt.Errorf("Error writing raft log : %w", err)
+	}
+	if err := raft.logFile.Sync(); err != nil {
+		return fmt.Errorf("Error syncing raft log : %w", err)
+	}
+	return nil
+}
+
+// rewriteLog replaces the log file with the entries after the snapshot, done when a conflicting
+// entry truncates the log or the log is compacted. Caller must hold mtx.
+func (raft *Raft) rewriteLog() error {
+	if raft.stateFile == "" {
+		return nil
+	}
+	if nil != raft.logFile {
+		raft.logFile.Close()
+		raft.logFile = nil
+	}
+
+	tmp := raft.logPath() + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("Error creating raft log : %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	for index := raft.snapshot.Index + 1; index <= raft.lastIndex(); index++ {
+		line, err := json.Marshal(raftRecord{Index: index, raftEntry: raft.entry(index)})
+		if err != nil {
+			file.Close()
+			return err
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error writing raft log : %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error syncing raft log : %w", err)
+	}
+	if err := os.Rename(tmp, raft.logPath()); err != nil {
+		file.Close()
+		return fmt.Errorf("Error replacing raft log : %w", err)
+	}
+	raft.logFile = file
+	return utils.SyncDir(raft.logPath())
+}
+
+// saveSnapshot saves the snapshot and drops the entries it replaced from the log file. Caller must hold mtx.
+func (raft *Raft) saveSnapshot() error {
+	if raft.stateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(raft.snapshot)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteSynced(raft.snapshotPath(), "raft snapshot", data); err != nil {
+		return err
+	}
+	return raft.rewriteLog()
+}
+
+// compact replaces the entries up to index with the state they built. Caller must hold mtx.
+func (raft *Raft) compact(index uint64, data []byte) {
+	if index <= raft.snapshot.Index || index > raft.lastIndex() {
+		return
+	}
+	term := raft.termAt(index)
+	raft.entries = append([]raftEntry(nil), raft.entries[index-raft.snapshot.Index:]...)
+	raft.snapshot = raftSnapshot{Index: index, Term: term, Data: data}
+	if err := raft.saveSnapshot(); err != nil {
+		log.Printf("Raft[%v] failed to save its snapshot : %v", raft.id, err)
+		return
+	}
+	log.Printf("Raft[%v] compacted its log up to index %d", raft.id, index)
+}
+
+// lastIndex returns the index of the last entry. Caller must hold mtx.
+func (raft *Raft) lastIndex() uint64 {
+	return raft.snapshot.Index + uint64(len(raft.entries))
+}
+
+// lastLog returns the index and term of the last entry. Caller must hold mtx.
+func (raft *Raft) lastLog() (uint64, uint64) {
+	if len(raft.entries) == 0 {
+		return raft.snapshot.Index, raft.snapshot.Term
+	}
+	return raft.lastIndex(), raft.entries[len(raft.entries)-1].Term
+}
+
+// entry returns the entry at an index after the snapshot. Caller must hold mtx.
+func (raft *Raft) entry(index uint64) raftEntry {
+	return raft.entries[index-raft.snapshot.Index-1]
+}
+
+// termAt returns the term of the entry at index, 0 before the snapshot or after the last entry.
+// Caller must hold mtx.
+func (raft *Raft) termAt(index uint64) uint64 {
+	if index == raft.snapshot.Index {
+		return raft.snapshot.Term
+	}
+	if index < raft.snapshot.Index || index > raft.lastIndex() {
+		return 0
+	}
+	return raft.entry(index).Term
+}
+
+// resetElection picks a random deadline between one and two election timeouts. Caller must hold mtx.
+func (raft *Raft) resetElection() {
+	timeout := raft.electionTimeout + time.Duration(rand.Int63n(int64(raft.electionTimeout)))
+	raft.electionDeadline = time.Now().Add(timeout)
+}
+
+// stepDown follows the leader of a newer term. Caller must hold mtx.
+func (raft *Raft) stepDown(term uint64) {
+	if term > raft.term {
+		raft.term = term
+		raft.votedFor = ""
+	}
+	if raft.role != raftFollower {
+		log.Printf("Raft[%v] stepping down in term %d", raft.id, raft.term)
+	}
+	raft.role = raftFollower
+	raft.resetElection()
+}
+
+// quorum reports whether count replicas out of the cluster are a majority
+func (raft *Raft) quorum(count int) bool {
+	return count > (len(raft.peers)+1)/2
+}
+
+func (raft *Raft) run() {
+	defer raft.wg.Done()
+	ticker := time.NewTicker(raft.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-raft.done:
+			return
+		case <-ticker.C:
+		}
+
+		raft.mtx.Lock()
+		switch {
+		case raft.role == raftLeader:
+			raft.replicate()
+		case time.Now().After(raft.electionDeadline):
+			raft.startElection()
+		}
+		raft.mtx.Unlock()
+	}
+}
+
+// startElection asks every peer for its vote in a new term. Caller must hold mtx.
+func (raft *Raft) startElection() {
+	raft.term++
+	raft.votedFor = raft.id
+	raft.role = raftCandidate
+	raft.leader = ""
+	raft.resetElection()
+	if err := raft.saveState(); err != nil {
+		log.Printf("Raft[%v] election failed : %v", raft.id, err)
+		return
+	}
+	log.Printf("Raft[%v] starting election for term %d", raft.id, raft.term)
+
+	votes := 1
+	if raft.quorum(votes) {
+		raft.becomeLeader()
+		return
+	}
+
+	lastIndex, lastTerm := raft.lastLog()
+	request := &pb.CoordinatorRequestVoteRequest{Term: raft.term, CandidateID: raft.id, LastLogIndex: lastIndex, LastLogTerm: lastTerm}
+	for peerID := range raft.peers {
+		raft.wg.Add(1)
+		go func(peerID string) {
+			defer raft.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), raft.electionTimeout)
+			defer cancel()
+			res, err := raft.client(peerID).RequestVote(ctx, request)
+			if err != nil {
+				return
+			}
+
+			raft.mtx.Lock()
+			defer raft.mtx.Unlock()
+			if res.Term > raft.term {
+				raft.stepDown(res.Term)
+				raft.saveState()
+				return
+			}
+			if raft.role != raftCandidate || raft.term != request.Term || !res.Granted {
+				return
+			}
+			votes++
+			if raft.quorum(votes) {
+				raft.becomeLeader()
+			}
+		}(peerID)
+	}
+}
+
+// becomeLeader starts the term with an empty entry, it commits the entries of the previous
+// terms along with it. Caller must hold mtx.
+func (raft *Raft) becomeLeader() {
+	log.Printf("Raft[%v] leading term %d", raft.id, raft.term)
+	raft.role = raftLeader
+	raft.leader = raft.id
+	raft.entries = append(raft.entries, raftEntry{Term: raft.term})
+	lastIndex, _ := raft.lastLog()
+	if err := raft.appendLog(lastIndex); err != nil {
+		log.Printf("Raft[%v] failed to save its log : %v", raft.id, err)
+	}
+
+	raft.nextIndex = make(map[string]uint64, len(raft.peers))
+	raft.matchIndex = make(map[string]uint64, len(raft.peers))
+	raft.replicating = make(map[string]bool, len(raft.peers))
+	for peerID := range raft.peers {
+		raft.nextIndex[peerID] = lastIndex
+		raft.matchIndex[peerID] = 0
+	}
+	raft.advanceCommit()
+	raft.replicate()
+}
+
+// replicate sends the missing entries, or a heartbeat, to every peer. A peer missing compacted
+// entries gets the snapshot. Caller must hold mtx.
+func (raft *Raft) replicate() {
+	for peerID := range raft.peers {
+		if raft.replicating[peerID] {
+			continue
+		}
+		raft.replicating[peerID] = true
+
+		if raft.nextIndex[peerID] <= raft.snapshot.Index {
+			request := &pb.CoordinatorInstallSnapshotRequest{
+				Term:              raft.term,
+				LeaderID:          raft.id,
+				LastIncludedIndex: raft.snapshot.Index,
+				LastIncludedTerm:  raft.snapshot.Term,
+				Data:              raft.snapshot.Data,
+			}
+			raft.wg.Add(1)
+			go raft.installSnapshot(peerID, request)
+			continue
+		}
+
+		prevIndex := raft.nextIndex[peerID] - 1
+		entries := make([]*pb.CoordinatorLogEntry, 0, raft.lastIndex()-prevIndex)
+		for _, entry := range raft.entries[prevIndex-raft.snapshot.Index:] {
+			entries = append(entries, &pb.CoordinatorLogEntry{Term: entry.Term, Command: entry.Command})
+		}
+		request := &pb.CoordinatorAppendEntriesRequest{
+			Term:         raft.term,
+			LeaderID:     raft.id,
+			PrevLogIndex: prevIndex,
+			PrevLogTerm:  raft.termAt(prevIndex),
+			Entries:      entries,
+			LeaderCommit: raft.commitIndex,
+		}
+		raft.wg.Add(1)
+		go raft.appendEntries(peerID, request)
+	}
+}
+
+func (raft *Raft) appendEntries(peerID string, request *pb.CoordinatorAppendEntriesRequest) {
+	defer raft.wg.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), raft.electionTimeout)
+	defer cancel()
+	res, err := raft.client(peerID).AppendEntries(ctx, request)
+
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+	raft.replicating[peerID] = false
+	if err != nil {
+		return
+	}
+	if res.Term > raft.term {
+		raft.stepDown(res.Term)
+		raft.saveState()
+		return
+	}
+	if raft.role != raftLeader || raft.term != request.Term {
+		return
+	}
+
+	if !res.Success {
+		next := raft.nextIndex[peerID] - 1
+		if res.LastLogIndex+1 < next {
+			next = res.LastLogIndex + 1
+		}
+		if next < 1 {
+			next = 1
+		}
+		raft.nextIndex[peerID] = next
+		return
+	}
+	raft.matched(peerID, request.PrevLogIndex+uint64(len(request.Entries)))
+}
+
+func (raft *Raft) installSnapshot(peerID string, request *pb.CoordinatorInstallSnapshotRequest) {
+	defer raft.wg.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), raft.electionTimeout)
+	defer cancel()
+	res, err := raft.client(peerID).InstallSnapshot(ctx, request)
+
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+	raft.replicating[peerID] = false
+	if err != nil {
+		return
+	}
+	if res.Term > raft.term {
+		raft.stepDown(res.Term)
+		raft.saveState()
+		return
+	}
+	if raft.role != raftLeader || raft.term != request.Term {
+		return
+	}
+	raft.matched(peerID, request.LastIncludedIndex)
+}
+
+// matched records that a peer saved the entries up to match. Caller must hold mtx.
+func (raft *Raft) matched(peerID string, match uint64) {
+	if match > raft.matchIndex[peerID] {
+		raft.matchIndex[peerID] = match
+		raft.nextIndex[peerID] = match + 1
+		raft.advanceCommit()
+	}
+}
+
+// advanceCommit commits the last entry of the term saved by a majority. Caller must hold mtx.
+func (raft *Raft) advanceCommit() {
+	for index := raft.lastIndex(); index > raft.commitIndex; index-- {
+		if raft.termAt(index) != raft.term {
+			break
+		}
+		count := 1
+		for _, match := range raft.matchIndex {
+			if match >= index {
+				count++
+			}
+		}
+		if raft.quorum(count) {
+			raft.commitIndex = index
+			raft.signalApply()
+			return
+		}
+	}
+}
+
+func (raft *Raft) signalApply() {
+	select {
+	case raft.applyC <- struct{}{}:
+	default:
+	}
+}
+
+// applyCommitted applies the committed entries in order and hands the results to their proposers.
+// A snapshot newer than the applied entries replaces them. The log is compacted once enough
+// entries were applied since the last snapshot.
+func (raft *Raft) applyCommitted() {
+	defer raft.wg.Done()
+	for {
+		select {
+		case <-raft.done:
+			return
+		case <-raft.applyC:
+		}
+
+		raft.mtx.Lock()
+		for raft.lastApplied < raft.commitIndex {
+			if raft.lastApplied < raft.snapshot.Index {
+				snapshot := raft.snapshot
+				for index, waiter := range raft.waiters {
+					// The proposal was replaced by the entries of another leader
+					if index <= snapshot.Index {
+						waiter <- raftResult{}
+						delete(raft.waiters, index)
+					}
+				}
+				raft.mtx.Unlock()
+
+				if err := raft.restore(snapshot.Data); err != nil {
+					log.Printf("Raft[%v] failed to restore the snapshot at index %d : %v", raft.id, snapshot.Index, err)
+				}
+
+				raft.mtx.Lock()
+				raft.lastApplied = snapshot.Index
+				continue
+			}
+
+			index := raft.lastApplied + 1
+			entry := raft.entry(index)
+			waiter, waiting := raft.waiters[index]
+			delete(raft.waiters, index)
+			leading := raft.role == raftLeader && entry.Term == raft.term
+			raft.mtx.Unlock()
+
+			var response interface{}
+			if len(entry.Command) != 0 {
+				response = raft.apply(entry.Command, leading)
+			} else if leading && nil != raft.onLeader {
+				go raft.onLeader()
+			}
+			if waiting {
+				waiter <- raftResult{term: entry.Term, response: response}
+			}
+
+			raft.mtx.Lock()
+			raft.lastApplied = index
+		}
+		applied := raft.lastApplied
+		compact := applied-raft.snapshot.Index >= raft.snapshotEntries
+		raft.mtx.Unlock()
+
+		// Only this goroutine changes the state, it is the one of the applied entries
+		if compact {
+			if data, ok := raft.takeSnapshot(); ok {
+				raft.mtx.Lock()
+				raft.compact(applied, data)
+				raft.mtx.Unlock()
+			}
+		}
+	}
+}
+
+// Propose appends a command to the log of the leader and returns what applying it returned once
+// it is committed. Fails with ErrNotLeader on the other replicas.
+func (raft *Raft) Propose(ctx context.Context, command []byte) (interface{}, error) {
+	raft.mtx.Lock()
+	if raft.role != raftLeader {
+		raft.mtx.Unlock()
+		return nil, ErrNotLeader
+	}
+	raft.entries = append(raft.entries, raftEntry{Term: raft.term, Command: command})
+	index, term := raft.lastLog()
+	if err := raft.appendLog(index); err != nil {
+		// The file may hold part of the entry
+		raft.entries = raft.entries[:len(raft.entries)-1]
+		raft.rewriteLog()
+		raft.mtx.Unlock()
+		return nil, err
+	}
+	waiter := make(chan raftResult, 1)
+	raft.waiters[index] = waiter
+	raft.advanceCommit()
+	raft.replicate()
+	raft.mtx.Unlock()
+
+	select {
+	case result := <-waiter:
+		if result.term != term {
+			// A new leader replaced the entry
+			return nil, ErrNotLeader
+		}
+		return result.response, nil
+	case <-ctx.Done():
+		raft.mtx.Lock()
+		delete(raft.waiters, index)
+		raft.mtx.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Leader returns the ID and admin address of the leader known to the replica, empty while there is none
+func (raft *Raft) Leader() (string, string) {
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+
+	if raft.leader == raft.id {
+		return raft.id, ""
+	}
+	return raft.leader, raft.peers[raft.leader]
+}
+
+// IsLeader reports whether the replica leads the current term
+func (raft *Raft) IsLeader() bool {
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+
+	return raft.role == raftLeader
+}
+
+// client returns the Raft client of a peer, dialed once and reused
+func (raft *Raft) client(peerID string) pb.RaftClient {
+	raft.connMtx.Lock()
+	defer raft.connMtx.Unlock()
+
+	conn, ok := raft.conns[peerID]
+	if !ok {
+		// Dialing without blocking only fails on a malformed address, the RPCs report it
+		conn, _ = grpc.Dial(raft.peers[peerID], grpc.WithInsecure())
+		raft.conns[peerID] = conn
+	}
+	return pb.NewRaftClient(conn)
+}
+
+func (raft *Raft) RequestVote(ctx context.Context, request *pb.CoordinatorRequestVoteRequest) (*pb.CoordinatorRequestVoteResponse, error) {
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+
+	term, votedFor := raft.term, raft.votedFor
+	if request.Term > raft.term {
+		raft.stepDown(request.Term)
+	}
+	lastIndex, lastTerm := raft.lastLog()
+	upToDate := request.LastLogTerm > lastTerm || (request.LastLogTerm == lastTerm && request.LastLogIndex >= lastIndex)
+	granted := request.Term == raft.term && upToDate && (raft.votedFor == "" || raft.votedFor == request.CandidateID)
+	if granted {
+		raft.votedFor = request.CandidateID
+		raft.resetElection()
+	}
+	if raft.term != term || raft.votedFor != votedFor {
+		if err := raft.saveState(); err != nil {
+			return nil, err
+		}
+	}
+	return &pb.CoordinatorRequestVoteResponse{Term: raft.term, Granted: granted}, nil
+}
+
+// follow steps down to follow the leader of a request, the term is saved when it changed.
+// Returns false when the request comes from the leader of an older term. Caller must hold mtx.
+func (raft *Raft) follow(term uint64, leaderID string) (bool, error) {
+	if term < raft.term {
+		return false, nil
+	}
+	if term > raft.term {
+		raft.stepDown(term)
+		if err := raft.saveState(); err != nil {
+			return false, err
+		}
+	} else if raft.role != raftFollower {
+		raft.stepDown(term)
+	}
+	raft.leader = leaderID
+	raft.resetElection()
+	return true, nil
+}
+
+func (raft *Raft) AppendEntries(ctx context.Context, request *pb.CoordinatorAppendEntriesRequest) (*pb.CoordinatorAppendEntriesResponse, error) {
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+
+	lastIndex, _ := raft.lastLog()
+	if current, err := raft.follow(request.Term, request.LeaderID); err != nil || !current {
+		return &pb.CoordinatorAppendEntriesResponse{Term: raft.term, LastLogIndex: lastIndex}, err
+	}
+
+	// Entries up to the snapshot are committed, they match the ones of the leader
+	if request.PrevLogIndex > lastIndex || (request.PrevLogIndex >= raft.snapshot.Index && raft.termAt(request.PrevLogIndex) != request.PrevLogTerm) {
+		hint := lastIndex
+		if request.PrevLogIndex <= lastIndex {
+			hint = request.PrevLogIndex - 1
+		}
+		return &pb.CoordinatorAppendEntriesResponse{Term: raft.term, LastLogIndex: hint}, nil
+	}
+
+	appended, truncated := uint64(0), false
+	for i, entry := range request.Entries {
+		index := request.PrevLogIndex + uint64(i) + 1
+		if index <= raft.snapshot.Index {
+			continue
+		}
+		if index <= raft.lastIndex() {
+			if raft.termAt(index) == entry.Term {
+				continue
+			}
+			// A conflicting entry and everything after it came from a replaced leader
+			raft.entries = raft.entries[:index-raft.snapshot.Index-1]
+			truncated = true
+		}
+		raft.entries = append(raft.entries, raftEntry{Term: entry.Term, Command: entry.Command})
+		if appended == 0 {
+			appended = index
+		}
+	}
+	switch {
+	case truncated:
+		if err := raft.rewriteLog(); err != nil {
+			return nil, err
+		}
+	case appended != 0:
+		if err := raft.appendLog(appended); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the entries of this request are known to match the leader
+	commit := request.LeaderCommit
+	if newest := request.PrevLogIndex + uint64(len(request.Entries)); newest < commit {
+		commit = newest
+	}
+	if commit > raft.commitIndex {
+		raft.commitIndex = commit
+		raft.signalApply()
+	}
+	lastIndex, _ = raft.lastLog()
+	return &pb.CoordinatorAppendEntriesResponse{Term: raft.term, Success: true, LastLogIndex: lastIndex}, nil
+}
+
+// InstallSnapshot replaces the log of a follower that misses compacted entries. The entries after
+// the snapshot are kept when the follower has the last one it replaces.
+func (raft *Raft) InstallSnapshot(ctx context.Context, request *pb.CoordinatorInstallSnapshotRequest) (*pb.CoordinatorInstallSnapshotResponse, error) {
+	raft.mtx.Lock()
+	defer raft.mtx.Unlock()
+
+	if current, err := raft.follow(request.Term, request.LeaderID); err != nil || !current {
+		return &pb.CoordinatorInstallSnapshotResponse{Term: raft.term}, err
+	}
+	if request.LastIncludedIndex <= raft.commitIndex {
+		// Already applied or about to be
+		return &pb.CoordinatorInstallSnapshotResponse{Term: raft.term}, nil
+	}
+
+	if raft.termAt(request.LastIncludedIndex) == request.LastIncludedTerm {
+		raft.entries = append([]raftEntry(nil), raft.entries[request.LastIncludedIndex-raft.snapshot.Index:]...)
+	} else {
+		raft.entries = nil
+	}
+	raft.snapshot = raftSnapshot{Index: request.LastIncludedIndex, Term: request.LastIncludedTerm, Data: request.Data}
+	if err := raft.saveSnapshot(); err != nil {
+		return nil, err
+	}
+	log.Printf("Raft[%v] installed the snapshot at index %d", raft.id, request.LastIncludedIndex)
+
+	raft.commitIndex = request.LastIncludedIndex
+	raft.signalApply()
+	return &pb.CoordinatorInstallSnapshotResponse{Term: raft.term}, nil
+}
@@ -0,0 +1,104 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+// scatter runs call on every node in parallel and collects the per node results.
+// Keys are hashed across the whole ring so ordered and index lookups have to ask every node.
+func scatter(coordinator *Coordinator, call func(client pb.StorageClient) ([]*pb.KeyValue, error)) ([][]*pb.KeyValue, error) {
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var firstErr error
+	nodes := coordinator.connections()
+	results := make([][]*pb.KeyValue, 0, len(nodes))
+
+	for nodeID, node := range nodes {
+		wg.Add(1)
+		go func(nodeID string, node *NodeConnection) {
+			defer wg.Done()
+			entries, err := call(node.client)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				if nil == firstErr {
+					firstErr = fmt.Errorf("Node[%v] request failed : %w", nodeID, err)
+				}
+				return
+			}
+			results = append(results, entries)
+		}(nodeID, node)
+	}
+	wg.Wait()
+
+	return results, firstErr
+}
+
+// rangeQuery gathers the range from every node and merges the per node results in key order
+func rangeQuery(coordinator *Coordinator, request *pb.StorageRangeRequest) ([]*pb.KeyValue, error) {
+	results, err := scatter(coordinator, func(client pb.StorageClient) ([]*pb.KeyValue, error) {
+		res, err := client.Range(context.Background(), request)
+		return res.GetEntries(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return MergeRanges(results, int(request.Limit), request.Reverse), nil
+}
+
+// queryIndex looks the value up in the secondary index of every node, results are merged in key order
+func queryIndex(coordinator *Coordinator, request *pb.StorageQueryIndexRequest) ([]*pb.KeyValue, error) {
+	results, err := scatter(coordinator, func(client pb.StorageClient) ([]*pb.KeyValue, error) {
+		res, err := client.QueryIndex(context.Background(), request)
+		return res.GetEntries(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return MergeRanges(results, int(request.Limit), false), nil
+}
+
+// MergeRanges k-way merges sorted per node results, stopping at limit when it is non zero. A key
+// returned by several replicas is kept once, with the value of its latest write.
+func MergeRanges(results [][]*pb.KeyValue, limit int, reverse bool) []*pb.KeyValue {
+	merged := []*pb.KeyValue{}
+	positions := make([]int, len(results))
+
+	for {
+		next := -1
+		for i, entries := range results {
+			if positions[i] >= len(entries) {
+				continue
+			}
+			if next == -1 {
+				next = i
+				continue
+			}
+			candidate, current := entries[positions[i]].Key, results[next][positions[next]].Key
+			if (!reverse && candidate < current) || (reverse && candidate > current) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		entry := results[next][positions[next]]
+		positions[next]++
+		if last := len(merged) - 1; last >= 0 && merged[last].Key == entry.Key {
+			if entry.Timestamp > merged[last].Timestamp {
+				merged[last] = entry
+			}
+			continue
+		}
+		if limit > 0 && len(merged) == limit {
+			break
+		}
+		merged = append(merged, entry)
+	}
+	return merged
+}
@@ -0,0 +1,198 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+// Read repair modes
+const (
+	ReadRepairAsync    = "async"
+	ReadRepairBlocking = "blocking"
+)
+
+// ReadRepairStats counts the reads of the coordinator since it started
+type ReadRepairStats struct {
+	Reads      uint64 // Sent to every replica for a read repair
+	Mismatches uint64 // Replicas answered different versions of the key, any read
+	Repaired   uint64 // Stale replicas the newest version was written back to
+	Failed     uint64 // Write backs that failed
+}
+
+// readRepair compares the replicas of a key on reads and writes the newest version back to the
+// stale ones
+type readRepair struct {
+	quorum   int            // Replicas answering a read out of all of them, the owner only when 0
+	chance   int            // Percent of the reads sent to every replica and repaired
+	blocking bool           // Stale replicas are written back before the read returns
+	wg       sync.WaitGroup // Write backs in the background
+	stats    ReadRepairStats
+}
+
+func newReadRepair(config *Config) (*readRepair, error) {
+	rr := &readRepair{quorum: max(config.ReadQuorum, 0), chance: config.ReadRepair.Chance}
+	switch config.ReadRepair.Mode {
+	case "", ReadRepairAsync:
+	case ReadRepairBlocking:
+		rr.blocking = true
+	default:
+		return nil, fmt.Errorf("Unknown read repair mode: %s", config.ReadRepair.Mode)
+	}
+	if rr.chance < 0 || rr.chance > 100 {
+		return nil, fmt.Errorf("Read repair chance %d is not a percentage", rr.chance)
+	}
+	return rr, nil
+}
+
+// replicaRead is the answer of a replica to a read
+type replicaRead struct {
+	nodeID string
+	node   *NodeConnection
+	res    *pb.StorageGetResponse
+	err    error
+}
+
+// newer reports whether a holds a later write than b, a delete wins over a write at the same time
+func newer(a, b *pb.StorageGetResponse) bool {
+	return a.Timestamp > b.Timestamp || a.Timestamp == b.Timestamp && a.Deleted && !b.Deleted
+}
+
+// read sends a read of a key to every replica and returns once the read quorum answered, or
+// reads the owner only when the quorum is 0. A read repair waits for every replica. Returns the
+// newest version the replicas answered and the node it was read from. Fails with ErrQuorum when
+// fewer replicas than the quorum answered, with the error of the owner when the quorum is 1.
+func (coordinator *Coordinator) read(ctx context.Context, key string, replicas []string, nodes map[string]*NodeConnection) (string, *pb.StorageGetResponse, error) {
+	rr := coordinator.readRepair
+	quorum := min(max(rr.quorum, 1), len(replicas))
+	repair := rr.chance > 0 && len(replicas) > 1 && rand.Intn(100) < rr.chance
+	if !repair && rr.quorum == 0 {
+		replicas = replicas[:1]
+	}
+
+	answers := make(chan replicaRead, len(replicas))
+	for _, nodeID := range replicas {
+		go func(read replicaRead) {
+			if nil == read.node {
+				read.err = fmt.Errorf("%w: %s", ErrUnknownNode, read.nodeID)
+			} else {
+				read.res, read.err = read.node.client.Get(ctx, &pb.StorageGetRequest{Key: key})
+			}
+			answers <- read
+		}(replicaRead{nodeID: nodeID, node: nodes[nodeID]})
+	}
+
+	// Slow replicas past the quorum are left behind, their answers go to the buffered channel
+	reads := []replicaRead{}
+	var failed error
+	for range replicas {
+		read := <-answers
+		if read.err != nil {
+			if nil == failed || read.nodeID == replicas[0] {
+				failed = read.err
+			}
+			continue
+		}
+		reads = append(reads, read)
+		if !repair && len(reads) >= quorum {
+			break
+		}
+	}
+	switch {
+	case len(reads) >= quorum:
+	case quorum == 1:
+		return replicas[0], nil, failed
+	default:
+		return "", nil, fmt.Errorf("%w: %d of %d : %v", ErrQuorum, len(reads), quorum, failed)
+	}
+
+	newest := &reads[0]
+	for i := range reads {
+		if newer(reads[i].res, newest.res) {
+			newest = &reads[i]
+		}
+	}
+	stale := []replicaRead{}
+	for _, read := range reads {
+		if newer(newest.res, read.res) {
+			stale = append(stale, read)
+		}
+	}
+	if repair {
+		atomic.AddUint64(&rr.stats.Reads, 1)
+	}
+	if len(stale) == 0 {
+		return newest.nodeID, newest.res, nil
+	}
+	atomic.AddUint64(&rr.stats.Mismatches, 1)
+	if !repair {
+		return newest.nodeID, newest.res, nil
+	}
+
+	entry := &pb.StorageEntry{
+		Key:       key,
+		Value:     newest.res.Value,
+		ExpiresAt: newest.res.ExpiresAt,
+		Timestamp: newest.res.Timestamp,
+		Deleted:   newest.res.Deleted,
+	}
+	if rr.blocking {
+		rr.writeBack(ctx, entry, stale)
+		return newest.nodeID, newest.res, nil
+	}
+	rr.wg.Add(1)
+	go func() {
+		defer rr.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+		defer cancel()
+		rr.writeBack(ctx, entry, stale)
+	}()
+	return newest.nodeID, newest.res, nil
+}
+
+// writeBack merges the newest version of a key into the stale replicas, a replica that got a
+// later write meanwhile keeps it
+func (rr *readRepair) writeBack(ctx context.Context, entry *pb.StorageEntry, stale []replicaRead) {
+	var wg sync.WaitGroup
+	for _, read := range stale {
+		wg.Add(1)
+		go func(read replicaRead) {
+			defer wg.Done()
+			err := func() error {
+				stream, err := read.node.client.RepairImport(ctx)
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(entry); err != nil {
+					return err
+				}
+				_, err = stream.CloseAndRecv()
+				return err
+			}()
+			if err != nil {
+				log.Printf("Node[%v] read repair of %s failed : %v", read.nodeID, entry.Key, err)
+				atomic.AddUint64(&rr.stats.Failed, 1)
+				return
+			}
+			atomic.AddUint64(&rr.stats.Repaired, 1)
+		}(read)
+	}
+	wg.Wait()
+}
+
+// ReadRepairs returns the counters of the read repairs
+func (coordinator *Coordinator) ReadRepairs() ReadRepairStats {
+	rr := coordinator.readRepair
+	return ReadRepairStats{
+		Reads:      atomic.LoadUint64(&rr.stats.Reads),
+		Mismatches: atomic.LoadUint64(&rr.stats.Mismatches),
+		Repaired:   atomic.LoadUint64(&rr.stats.Repaired),
+		Failed:     atomic.LoadUint64(&rr.stats.Failed),
+	}
+}
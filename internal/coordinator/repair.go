@@ -0,0 +1,180 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// Longest repair of all the replicas started from the CLI or the schedule
+const repairTimeoutSeconds = 300
+
+// RepairResult is the outcome of the repair of the ranges two replicas share, Node compared its
+// Merkle tree with the one of Peer
+type RepairResult struct {
+	Node     string
+	Peer     string
+	Ranges   int    // Ranges of the ring compared, 0 when the placement is not a ring
+	Leaves   uint32 // Leaves of the trees that differed
+	Received uint64 // Keys Node merged from Peer
+	Sent     uint64 // Keys Peer merged from Node
+	Err      error
+}
+
+// Repair compares the Merkle trees of every pair of nodes replicating the same keys and exchanges
+// the keys that differ, the newer write of a key wins. On a ring the pairs compare the ranges they
+// replicate, with another placement every pair of nodes compares the keys the placement
+// replicates on both. Fails with ErrMigrating while keys move.
+func (coordinator *Coordinator) Repair(ctx context.Context) ([]RepairResult, error) {
+	if coordinator.isMigrating() {
+		return nil, ErrMigrating
+	}
+
+	coordinator.nodesMtx.RLock()
+	pairs := coordinator.repairPairs()
+	nodes := make(map[string]*NodeConnection, len(coordinator.Nodes))
+	for nodeID, node := range coordinator.Nodes {
+		nodes[nodeID] = node
+	}
+	coordinator.nodesMtx.RUnlock()
+
+	results := make([]RepairResult, 0, len(pairs))
+	for pair, request := range pairs {
+		results = append(results, RepairResult{Node: pair[0], Peer: pair[1], Ranges: len(request.Ranges)})
+	}
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(result *RepairResult) {
+			defer wg.Done()
+			node, peer := nodes[result.Node], nodes[result.Peer]
+			if nil == node || nil == peer {
+				result.Err = ErrUnknownNode
+				return
+			}
+			request := pairs[[2]string{result.Node, result.Peer}]
+			request.Peer = fmt.Sprintf("%s:%d", peer.info.Host, peer.info.Port)
+			res, err := node.client.Repair(ctx, request)
+			if err != nil {
+				result.Err = err
+				log.Printf("Node[%v] repair with Node[%v] failed : %v", result.Node, result.Peer, err)
+				return
+			}
+			result.Leaves, result.Received, result.Sent = res.Leaves, res.Received, res.Sent
+		}(&results[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Node != results[j].Node {
+			return results[i].Node < results[j].Node
+		}
+		return results[i].Peer < results[j].Peer
+	})
+	return results, nil
+}
+
+// repairPairs returns the repair of each pair of replicas without its peer, the node of the smaller
+// ID compares. Caller must hold nodesMtx.
+func (coordinator *Coordinator) repairPairs() map[[2]string]*pb.StorageRepairRequest {
+	pairs := map[[2]string]*pb.StorageRepairRequest{}
+	hashName := coordinator.Placement.Config().Hash
+	ring, ok := coordinator.Placement.(*utils.ConsistentHash)
+	if !ok {
+		if coordinator.replicationFactor < 2 {
+			return pairs
+		}
+		placement := utils.PlacementToProto(coordinator.Placement)
+		nodes := coordinator.Placement.ListNodes()
+		sort.Strings(nodes)
+		for i := range nodes {
+			for j := i + 1; j < len(nodes); j++ {
+				pairs[[2]string{nodes[i], nodes[j]}] = &pb.StorageRepairRequest{
+					Hash:              hashName,
+					Placement:         placement,
+					ReplicationFactor: uint32(coordinator.replicationFactor),
+					Replicas:          []string{nodes[i], nodes[j]},
+				}
+			}
+		}
+		return pairs
+	}
+
+	for _, replica := range ring.Snapshot().ReplicaRanges(coordinator.replicationFactor) {
+		for i := range replica.Nodes {
+			for j := i + 1; j < len(replica.Nodes); j++ {
+				pair := [2]string{min(replica.Nodes[i], replica.Nodes[j]), max(replica.Nodes[i], replica.Nodes[j])}
+				if nil == pairs[pair] {
+					pairs[pair] = &pb.StorageRepairRequest{Hash: hashName}
+				}
+				pairs[pair].Ranges = append(pairs[pair].Ranges, &pb.StorageHashRange{Start: replica.Range.Start, End: replica.Range.End})
+			}
+		}
+	}
+	return pairs
+}
+
+// startRepairs repairs the replicas every interval while this replica leads, until Close
+func (coordinator *Coordinator) startRepairs(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	coordinator.stopRepairs = cancel
+
+	coordinator.repairWG.Add(1)
+	go func() {
+		defer coordinator.repairWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if nil != coordinator.raft && !coordinator.raft.IsLeader() {
+				continue
+			}
+
+			repairCtx, cancelRepair := context.WithTimeout(ctx, repairTimeoutSeconds*time.Second)
+			results, err := coordinator.Repair(repairCtx)
+			cancelRepair()
+			if err != nil {
+				log.Printf("Scheduled repair skipped : %v", err)
+				continue
+			}
+			for _, result := range results {
+				if nil == result.Err && result.Leaves != 0 {
+					log.Printf("Node[%v] repaired %d leaves with Node[%v] : received %d keys, sent %d", result.Node, result.Leaves, result.Peer, result.Received, result.Sent)
+				}
+			}
+		}
+	}()
+}
+
+func repair(coordinator *Coordinator) {
+	ctx, cancel := context.WithTimeout(context.Background(), repairTimeoutSeconds*time.Second)
+	defer cancel()
+
+	results, err := coordinator.Repair(ctx)
+	if err != nil {
+		fmt.Printf("Repair failed : %v\n", err)
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println("No replicas to repair")
+		return
+	}
+	for _, result := range results {
+		if nil != result.Err {
+			fmt.Printf("Node[%v] Node[%v] Ranges : %v Failed : %v\n", result.Node, result.Peer, result.Ranges, result.Err)
+			continue
+		}
+		fmt.Printf("Node[%v] Node[%v] Ranges : %v Leaves differing : %v Received : %v Sent : %v\n",
+			result.Node, result.Peer, result.Ranges, result.Leaves, result.Received, result.Sent)
+	}
+}
@@ -0,0 +1,207 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrQuorum = errors.New("fewer replicas answered than the quorum")
+
+// mutation is a replicated Put or Delete, every replica applies it at the same Timestamp so the
+// later write of a key wins on all of them whatever the order they get writes in. Other writes run
+// on the owner, the key is then replicated as Entry, see Mutate.
+type mutation struct {
+	Key        string `json:"key"`
+	Value      []byte `json:"value,omitempty"`
+	Deleted    bool   `json:"deleted,omitempty"`
+	TTLSeconds int64  `json:"ttl_seconds,omitempty"`
+	Timestamp  int64  `json:"ts"`
+
+	// Sibling of a key versioned with vector clocks, merged with the ones of the replica
+	Sibling *utils.Sibling `json:"sibling,omitempty"`
+
+	// Key as the owner holds it after the write, or its tombstone, imported unless it is older
+	Entry *pb.StorageEntry `json:"entry,omitempty"`
+}
+
+// apply sends the mutation to a node, stale is set when the node holds a later write of the key
+func (m mutation) apply(ctx context.Context, node *NodeConnection) (stale bool, err error) {
+	if nil != m.Sibling {
+		sibling := &pb.StorageSibling{Value: m.Sibling.Value, Context: m.Sibling.Context, DotNode: m.Sibling.Dot.Node, DotCounter: m.Sibling.Dot.Counter}
+		res, err := node.client.MergeSibling(ctx, &pb.StorageMergeSiblingRequest{Key: m.Key, Sibling: sibling, Timestamp: m.Timestamp})
+		if err != nil {
+			return false, err
+		}
+		return res.Stale, nil
+	}
+	if nil != m.Entry {
+		stream, err := node.client.ImportKeys(ctx)
+		if err != nil {
+			return false, err
+		}
+		if err := stream.Send(m.Entry); err != nil && err != io.EOF {
+			return false, err
+		}
+		res, err := stream.CloseAndRecv()
+		if err != nil {
+			return false, err
+		}
+		return res.Imported == 0, nil
+	}
+	if m.Deleted {
+		res, err := node.client.Delete(ctx, &pb.StorageDeleteRequest{Key: m.Key, Timestamp: m.Timestamp})
+		if err != nil {
+			return false, err
+		}
+		return res.Stale, nil
+	}
+
+	request := &pb.StoragePutRequest{Key: m.Key, Value: m.Value, Timestamp: m.Timestamp}
+	if m.TTLSeconds > 0 {
+		request.TTLSeconds = &m.TTLSeconds
+	}
+	res, err := node.client.Put(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	return res.Stale, nil
+}
+
+// unreachable reports whether a write failed because the node is down, it is hinted then
+func unreachable(err error) bool {
+	code := status.Code(err)
+	return code == codes.Unavailable || code == codes.DeadlineExceeded
+}
+
+// WriteResult is the outcome of a replicated write on each replica of the key
+type WriteResult struct {
+	Replicas []string // Owner first
+	Acked    []string
+	Hinted   []string // Down, the write is replayed once they are back
+	Failed   map[string]error
+}
+
+// replicas returns the nodes holding a key, the owner first, and the connections to them
+func (coordinator *Coordinator) replicas(key string) ([]string, map[string]*NodeConnection, int, error) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	replicas, err := coordinator.Placement.GetNodes(key, coordinator.replicationFactor)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	nodes := make(map[string]*NodeConnection, len(replicas))
+	for _, nodeID := range replicas {
+		nodes[nodeID] = coordinator.Nodes[nodeID]
+	}
+	quorum := coordinator.writeQuorum
+	if quorum == 0 {
+		quorum = coordinator.replicationFactor/2 + 1
+	}
+	return replicas, nodes, min(quorum, len(replicas)), nil
+}
+
+// Put writes a key to every replica, a ttl of 0 keeps it until it is deleted. See replicate. A key
+// versioned with vector clocks takes no ttl, the value is kept next to the present ones.
+func (coordinator *Coordinator) Put(ctx context.Context, key string, value []byte, ttlSeconds int64) (WriteResult, error) {
+	if coordinator.VectorClocks(key) {
+		if ttlSeconds > 0 {
+			return WriteResult{}, fmt.Errorf("%w: %s takes no TTL", ErrVersioned, key)
+		}
+		return coordinator.PutVersion(ctx, key, value, "")
+	}
+	return coordinator.replicate(ctx, mutation{Key: key, Value: value, TTLSeconds: ttlSeconds, Timestamp: time.Now().UnixNano()})
+}
+
+// Delete deletes a key on every replica, they keep a tombstone of the delete. See replicate.
+func (coordinator *Coordinator) Delete(ctx context.Context, key string) (WriteResult, error) {
+	return coordinator.replicate(ctx, mutation{Key: key, Deleted: true, Timestamp: time.Now().UnixNano()})
+}
+
+// replicate applies a mutation on every replica of its key at once. A replica that cannot be
+// reached gets a hint replayed once the heartbeats see it back. Fails with ErrQuorum when fewer
+// replicas than the write quorum acknowledged, the write stays on the ones that did and in the hints.
+func (coordinator *Coordinator) replicate(ctx context.Context, m mutation) (WriteResult, error) {
+	replicas, nodes, quorum, err := coordinator.replicas(m.Key)
+	if err != nil {
+		return WriteResult{}, err
+	}
+
+	result := WriteResult{Replicas: replicas, Failed: make(map[string]error)}
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for _, nodeID := range replicas {
+		wg.Add(1)
+		go func(nodeID string, node *NodeConnection) {
+			defer wg.Done()
+			err := fmt.Errorf("%w: %s", ErrUnknownNode, nodeID)
+			if nil != node {
+				err = coordinator.pull(ctx, m.Key, nodeID, node)
+			}
+			if nil != node && err == nil {
+				_, err = m.apply(ctx, node)
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			switch {
+			case err == nil:
+				result.Acked = append(result.Acked, nodeID)
+			case unreachable(err):
+				coordinator.hints.add(nodeID, m)
+				result.Hinted = append(result.Hinted, nodeID)
+			default:
+				result.Failed[nodeID] = err
+			}
+		}(nodeID, nodes[nodeID])
+	}
+	wg.Wait()
+
+	if len(result.Acked) < quorum {
+		return result, fmt.Errorf("%w: %d of %d", ErrQuorum, len(result.Acked), quorum)
+	}
+	return result, nil
+}
+
+// Mutate runs a write other than a Put or Delete, an increment or a list push, on the owner of a
+// key, then replicates the key as the owner left it. The replicas import the value the write made
+// rather than running it again. Fails with the error of call without replicating.
+func (coordinator *Coordinator) Mutate(ctx context.Context, key string, call func(client pb.StorageClient) error) (WriteResult, error) {
+	nodeID, node, err := coordinator.ownerFor(ctx, key)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	if err := call(node.client); err != nil {
+		return WriteResult{Replicas: []string{nodeID}}, err
+	}
+	return coordinator.copyKey(ctx, key, nodeID, node)
+}
+
+// copyKey replicates a key written on its owner, see Mutate
+func (coordinator *Coordinator) copyKey(ctx context.Context, key, nodeID string, node *NodeConnection) (WriteResult, error) {
+	if coordinator.replicationFactor <= 1 {
+		return WriteResult{Replicas: []string{nodeID}, Acked: []string{nodeID}}, nil
+	}
+	stream, err := node.client.TransferKeys(ctx, &pb.StorageTransferKeysRequest{Keys: []string{key}})
+	if err != nil {
+		return WriteResult{Replicas: []string{nodeID}}, fmt.Errorf("Error reading %s from Node[%v] : %w", key, nodeID, err)
+	}
+	entry, err := stream.Recv()
+	if err == io.EOF {
+		// Expired meanwhile, the replicas expire it as well
+		return WriteResult{Replicas: []string{nodeID}, Acked: []string{nodeID}}, nil
+	}
+	if err != nil {
+		return WriteResult{Replicas: []string{nodeID}}, fmt.Errorf("Error reading %s from Node[%v] : %w", key, nodeID, err)
+	}
+	return coordinator.replicate(ctx, mutation{Key: key, Timestamp: entry.Timestamp, Entry: entry})
+}
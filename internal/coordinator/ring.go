@@ -0,0 +1,174 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+var ErrNotRing = errors.New("placement is not a consistent hashing ring")
+
+// Ring returns the snapshot of a ring placement and the ring version of the metadata
+func (coordinator *Coordinator) Ring() (*utils.RingSnapshot, uint64, error) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	ring, ok := coordinator.Placement.(*utils.ConsistentHash)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotRing, coordinator.Placement.Config().Strategy)
+	}
+	return ring.Snapshot(), coordinator.ringVersion, nil
+}
+
+// Routing returns the placement pushed to the nodes, a ring or the weights of the nodes, at the ring
+// version of the metadata
+func (coordinator *Coordinator) Routing() (*pb.StorageRing, *pb.StoragePlacement, uint64) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	request := coordinator.setRingRequest("", false)
+	return request.Ring, request.Placement, request.RingVersion
+}
+
+// KeyOwner is where a key lives. Token is the virtual node of the arc holding the key, only set
+// when the placement is a ring.
+type KeyOwner struct {
+	Position    uint32
+	Token       *utils.RingToken
+	Owner       string
+	Replicas    []string // Owner first, up to the replication factor
+	RingVersion uint64
+}
+
+// KeyOwner returns the position, owning virtual node and replicas of a key
+func (coordinator *Coordinator) KeyOwner(key string) (KeyOwner, error) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	replicas, err := coordinator.Placement.GetNodes(key, coordinator.replicationFactor)
+	if err != nil {
+		return KeyOwner{}, err
+	}
+	owner := KeyOwner{Owner: replicas[0], Replicas: replicas, RingVersion: coordinator.ringVersion}
+	if ring, ok := coordinator.Placement.(*utils.ConsistentHash); ok {
+		snapshot := ring.Snapshot()
+		token, err := snapshot.Token(key)
+		if err != nil {
+			return KeyOwner{}, err
+		}
+		owner.Position, owner.Token = snapshot.Position(key), &token
+	} else {
+		hash, err := utils.ParseHash(coordinator.Placement.Config().Hash)
+		if err != nil {
+			return KeyOwner{}, err
+		}
+		owner.Position = hash(key)
+	}
+	return owner, nil
+}
+
+// NodeBalance is the share of a node of the hash space and of the keys
+type NodeBalance struct {
+	NodeID   string
+	Weight   int
+	Share    float64 // Of the hash space
+	Keys     uint64
+	KeyShare float64
+	Err      error // Stats failed, Keys is unknown
+}
+
+// Balance returns the share of the hash space of every node in the ring and the keys each holds
+// from its Stats. Returns the ring version the shares were computed at.
+func (coordinator *Coordinator) Balance(ctx context.Context) ([]NodeBalance, uint64) {
+	coordinator.nodesMtx.RLock()
+	shares := utils.Shares(coordinator.Placement)
+	version := coordinator.ringVersion
+	balances := make([]NodeBalance, 0, len(coordinator.Nodes))
+	nodes := make([]*NodeConnection, 0, len(coordinator.Nodes))
+	for nodeID, node := range coordinator.Nodes {
+		balances = append(balances, NodeBalance{NodeID: nodeID, Weight: coordinator.Placement.Weight(nodeID), Share: shares[nodeID]})
+		nodes = append(nodes, node)
+	}
+	coordinator.nodesMtx.RUnlock()
+
+	var wg sync.WaitGroup
+	for i := range balances {
+		wg.Add(1)
+		go func(balance *NodeBalance, node *NodeConnection) {
+			defer wg.Done()
+			res, err := node.client.Stats(ctx, &pb.StorageStatsRequest{})
+			if err != nil {
+				balance.Err = err
+				return
+			}
+			balance.Keys = res.NumKeys
+		}(&balances[i], nodes[i])
+	}
+	wg.Wait()
+
+	var total uint64
+	for _, balance := range balances {
+		total += balance.Keys
+	}
+	for i := range balances {
+		if total != 0 {
+			balances[i].KeyShare = float64(balances[i].Keys) / float64(total)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].NodeID < balances[j].NodeID
+	})
+	return balances, version
+}
+
+func listRing(coordinator *Coordinator) {
+	snapshot, version, err := coordinator.Ring()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	tokens := snapshot.Tokens()
+	fmt.Printf("Ring version : %v Tokens : %v\n", version, len(tokens))
+	for _, token := range tokens {
+		if token.Owner != token.Node {
+			fmt.Printf("%10d Node[%v] Owner : %v\n", token.Position, token.Node, token.Owner)
+			continue
+		}
+		fmt.Printf("%10d Node[%v]\n", token.Position, token.Node)
+	}
+}
+
+func keyOwner(coordinator *Coordinator, key string) {
+	owner, err := coordinator.KeyOwner(key)
+	if err != nil {
+		fmt.Printf("Owner lookup failed : %v\n", err)
+		return
+	}
+	fmt.Printf("Key : %v Hash : %v\n", key, owner.Position)
+	if nil != owner.Token {
+		fmt.Printf("Token : %v Node[%v]\n", owner.Token.Position, owner.Token.Node)
+	}
+	fmt.Printf("Owner : Node[%v] Replicas : %v Ring version : %v\n", owner.Owner, owner.Replicas, owner.RingVersion)
+}
+
+func nodeBalance(coordinator *Coordinator) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+	defer cancel()
+
+	balances, version := coordinator.Balance(ctx)
+	fmt.Printf("Ring version : %v\n", version)
+	for _, balance := range balances {
+		if nil != balance.Err {
+			fmt.Printf("Node[%v] Weight : %v Hash space : %.2f%% Stats failed : %v\n", balance.NodeID, balance.Weight, balance.Share*100, balance.Err)
+			continue
+		}
+		fmt.Printf("Node[%v] Weight : %v Hash space : %.2f%% Keys : %v (%.2f%%)\n",
+			balance.NodeID, balance.Weight, balance.Share*100, balance.Keys, balance.KeyShare*100)
+	}
+}
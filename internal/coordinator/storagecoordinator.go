@@ -1,6 +1,7 @@
 package coordinator
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -16,40 +17,280 @@ const connectionTimeout = 5
 type NodeConnection struct {
 	conn   *grpc.ClientConn
 	client pb.StorageClient
+	info   Node
 }
 
 type Coordinator struct {
+	// Nodes and the ring change with the membership, guarded by nodesMtx
+	nodesMtx       sync.RWMutex
 	Nodes          map[string]*NodeConnection
-	ConsistentHash *utils.ConsistentHash
+	Placement      utils.Placement
+	membershipFile string
+	draining       map[string]*NodeConnection // Left the ring, still holding keys
+
+	// Rest of the cluster metadata, guarded by nodesMtx
+	ringVersion       uint64
+	replicationFactor int
+	bootstrapped      bool
+
+	// Serializes the membership changes
+	changeMtx sync.Mutex
+
+	// Replicates the cluster metadata among the coordinators, nil when not replicated
+	raft            *Raft
+	bootstrapChange metadataChange
+	adminServer     *grpc.Server
+
+	// Keys moving after the last membership change, planned by every replica from the Raft log
+	// and moved by the leader
+	migrationMtx sync.Mutex
+	migrations   []*migration
+	plans        uint64 // Migrations planned so far, numbers the current ones
+
+	// Watches proxied to the nodes
+	watchMtx    sync.Mutex
+	watches     map[int]*proxyWatch
+	nextWatchID int
+
+	// Change data capture, nil when disabled
+	cdc *cdcCollector
+
+	// Decisions of the two-phase commits
+	txnLog *txnLog
+
+	// Replicas acknowledging a write, 0 for a majority
+	writeQuorum int
+
+	// Versioning of the keyspaces, longest prefix first
+	keyspaces []Keyspace
+
+	// Quorum reads and the write backs of their read repairs
+	readRepair *readRepair
+
+	// Writes the replicas missed while down and the heartbeats that see them back
+	hints     *hintStore
+	heartbeat *heartbeat
+
+	// Scheduled anti-entropy repairs, stopRepairs is nil when they are disabled
+	stopRepairs context.CancelFunc
+	repairWG    sync.WaitGroup
 }
 
-func StorageCoordinator(config *Config, wg *sync.WaitGroup) {
-	defer wg.Done()
-	if nil == config {
-		log.Println("Nil config received")
-		return
+// node returns the connection to a node
+func (coordinator *Coordinator) node(nodeID string) (*NodeConnection, bool) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	node, ok := coordinator.Nodes[nodeID]
+	return node, ok
+}
+
+// owner returns the node owning key and the connection to it
+func (coordinator *Coordinator) owner(key string) (string, *NodeConnection, error) {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	nodeID, err := coordinator.Placement.GetNode(key)
+	if err != nil {
+		return "", nil, err
+	}
+	return nodeID, coordinator.Nodes[nodeID], nil
+}
+
+// connections returns the connection to every node
+func (coordinator *Coordinator) connections() map[string]*NodeConnection {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	nodes := make(map[string]*NodeConnection, len(coordinator.Nodes))
+	for nodeID, node := range coordinator.Nodes {
+		nodes[nodeID] = node
 	}
+	return nodes
+}
+
+// nodeIDs returns the IDs of the nodes in the ring
+func (coordinator *Coordinator) nodeIDs() []string {
+	coordinator.nodesMtx.RLock()
+	defer coordinator.nodesMtx.RUnlock()
+
+	return coordinator.Placement.ListNodes()
+}
 
+// NewCoordinator connects to the nodes of the saved membership, or of the config on the first
+// start, and finishes the transactions left in doubt by the last run. A replicated coordinator
+// gets the membership from the Raft log instead, the first leader writes the one of the config.
+func NewCoordinator(config *Config) (*Coordinator, error) {
+	placement, err := newPlacement(config.Placement, config.NumberOfVirtualNodes)
+	if err != nil {
+		return nil, err
+	}
 	coordinator := &Coordinator{
-		Nodes:          make(map[string]*NodeConnection),
-		ConsistentHash: utils.NewConsistentHash(config.NumberOfVirtualNodes),
+		Nodes:             make(map[string]*NodeConnection),
+		Placement:         placement,
+		membershipFile:    config.MembershipFile,
+		draining:          make(map[string]*NodeConnection),
+		replicationFactor: config.ReplicationFactor,
+		writeQuorum:       config.WriteQuorum,
+		watches:           make(map[int]*proxyWatch),
+	}
+	if coordinator.replicationFactor == 0 {
+		coordinator.replicationFactor = 1
+	}
+	if coordinator.keyspaces, err = newKeyspaces(config.Keyspaces); err != nil {
+		return nil, err
+	}
+	if coordinator.readRepair, err = newReadRepair(config); err != nil {
+		return nil, err
+	}
+	if coordinator.hints, err = openHints(config.HintedHandoff.File, config.HintedHandoff.TTLSeconds, config.HintedHandoff.MaxHints); err != nil {
+		return nil, err
+	}
+
+	members, err := loadMembership(config.MembershipFile)
+	if err != nil {
+		return nil, err
+	}
+	if nil == members {
+		members = config.Nodes
+	}
+
+	// Collected from the start, the Raft log may change the membership at any time
+	if config.CDC.Enabled {
+		collector, err := newCDCCollector(config)
+		if err != nil {
+			return nil, fmt.Errorf("Error starting CDC : %w", err)
+		}
+		coordinator.cdc = collector
 	}
 
-	// Setup
-	for nodeID, nodeNetInfo := range config.Nodes {
-		conn, err := grpc.Dial(fmt.Sprintf("%s:%d", nodeNetInfo.Host, nodeNetInfo.Port),
-			grpc.WithBlock(), grpc.WithTimeout(connectionTimeout*time.Second), grpc.WithInsecure())
-		if nil != err {
-			log.Printf("Error establishing connection with : %s", fmt.Sprintf("%s:%d", nodeNetInfo.Host, nodeNetInfo.Port))
-			return
+	if config.Raft.ID != "" {
+		if err := coordinator.startRaft(config, members); err != nil {
+			coordinator.Close()
+			return nil, err
 		}
-		defer conn.Close()
-		coordinator.Nodes[nodeID] = &NodeConnection{
-			conn:   conn,
-			client: pb.NewStorageClient(conn),
+	} else {
+		for nodeID, info := range members {
+			node, err := dialNode(nodeID, info)
+			if err != nil {
+				coordinator.Close()
+				return nil, err
+			}
+			coordinator.routed(nodeID, node)
+			if info.Leaving {
+				coordinator.draining[nodeID] = node
+				continue
+			}
+			coordinator.Nodes[nodeID] = node
+			coordinator.Placement.AddWeightedNode(nodeID, info.Weight)
 		}
-		coordinator.ConsistentHash.AddNode(nodeID)
+		coordinator.ringVersion = 1
+		coordinator.bootstrapped = true
+		coordinator.syncRingVersion()
+
+		if config.AdminPort != 0 {
+			if coordinator.adminServer, err = serveAdmin(coordinator, config.AdminPort); err != nil {
+				coordinator.Close()
+				return nil, err
+			}
+		}
+	}
+
+	txns, unfinished, err := openTxnLog(config.TxnLogFile)
+	if err != nil {
+		coordinator.Close()
+		return nil, fmt.Errorf("Error opening transaction log : %w", err)
+	}
+	coordinator.txnLog = txns
+	recoverTxns(coordinator, unfinished)
+
+	if nil != coordinator.cdc {
+		coordinator.cdc.start(coordinator)
+	}
+	interval := config.HeartbeatIntervalMillis
+	if interval <= 0 {
+		interval = heartbeatIntervalMillis
+	}
+	coordinator.heartbeat = coordinator.monitor(time.Duration(interval) * time.Millisecond)
+	if config.RepairIntervalSeconds > 0 {
+		coordinator.startRepairs(time.Duration(config.RepairIntervalSeconds) * time.Second)
+	}
+	return coordinator, nil
+}
+
+// startRaft serves the Raft RPCs on the admin port and waits for the metadata, members are
+// written as the first membership if the cluster has none yet
+func (coordinator *Coordinator) startRaft(config *Config, members map[string]Node) error {
+	if config.AdminPort == 0 {
+		return fmt.Errorf("Replicated coordinators need an admin port")
+	}
+	raft, err := newRaft(config, coordinator.applyCommand, coordinator.bootstrap, coordinator.snapshotMetadata, coordinator.restoreMetadata)
+	if err != nil {
+		return err
+	}
+	coordinator.raft = raft
+	coordinator.bootstrapChange = metadataChange{
+		Op:                metadataBootstrap,
+		Nodes:             members,
+		VirtualNodes:      config.NumberOfVirtualNodes,
+		Placement:         &config.Placement,
+		ReplicationFactor: coordinator.replicationFactor,
+	}
+
+	if coordinator.adminServer, err = serveAdmin(coordinator, config.AdminPort); err != nil {
+		coordinator.raft = nil
+		return err
+	}
+	raft.start()
+
+	// A replica that cannot reach a majority yet keeps waiting in the background
+	if !coordinator.waitBootstrap(connectionTimeout * time.Second) {
+		log.Printf("Cluster metadata not known yet, waiting for a leader")
+	}
+	return nil
+}
+
+// Close stops the heartbeats, the scheduled repairs, the admin server, the Raft replication and the
+// change data capture, waits for the read repairs and closes the transaction log, the hints and the
+// node connections
+func (coordinator *Coordinator) Close() {
+	if nil != coordinator.heartbeat {
+		coordinator.heartbeat.close()
+	}
+	if nil != coordinator.stopRepairs {
+		coordinator.stopRepairs()
+		coordinator.repairWG.Wait()
+	}
+	if nil != coordinator.adminServer {
+		coordinator.adminServer.Stop()
+	}
+	if nil != coordinator.raft {
+		coordinator.raft.stop()
+	}
+	if nil != coordinator.cdc {
+		coordinator.cdc.stop()
+	}
+	if nil != coordinator.txnLog {
+		coordinator.txnLog.close()
+	}
+	coordinator.readRepair.wg.Wait()
+	coordinator.hints.close()
+	coordinator.closeNodes()
+}
+
+func StorageCoordinator(config *Config, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if nil == config {
+		log.Println("Nil config received")
+		return
+	}
+
+	coordinator, err := NewCoordinator(config)
+	if err != nil {
+		log.Printf("Error starting coordinator : %v", err)
+		return
 	}
+	defer coordinator.Close()
 
 	// Call CLI with this config
 	cli(coordinator)
@@ -0,0 +1,596 @@
+package coordinator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Attempts of a transaction whose keys are locked by another one
+const txnConflictRetries = 3
+const txnRetrySeconds = 1
+
+// Time a decision is resent to unreachable participants, after it the next start retries it
+const txnDecisionTimeoutSeconds = 60
+
+var ErrTxnConflict = errors.New("transaction conflicted with another one, retry it")
+var ErrCrossNodeTxn = errors.New("keys of the transaction live on more than one node")
+
+// TxnRequest runs Then when every compare holds and Else otherwise, atomically across nodes
+type TxnRequest struct {
+	Compares []*pb.TxnCompare
+	Then     []*pb.TxnOp
+	Else     []*pb.TxnOp
+}
+
+// TxnResponse holds one result per operation of the branch that ran, in request order
+type TxnResponse struct {
+	Succeeded bool
+	Results   []*pb.TxnOpResult
+}
+
+// Transaction states of the log, a transaction without a decision is aborted on recovery
+const (
+	txnPreparing = "PREPARING"
+	txnCommit    = "COMMIT"
+	txnAbort     = "ABORT"
+	txnDone      = "DONE"
+)
+
+type txnLogRecord struct {
+	ID           string   `json:"id"`
+	State        string   `json:"state"`
+	Participants []string `json:"participants,omitempty"`
+	Succeeded    bool     `json:"succeeded,omitempty"`
+}
+
+// txnLog appends the state changes of transactions, synced before the participants are told
+type txnLog struct {
+	mtx  sync.Mutex
+	path string
+	file *os.File
+}
+
+var txnCounter uint64
+var txnEpoch = time.Now().UnixNano()
+
+func newTxnID() string {
+	return fmt.Sprintf("%x-%d", txnEpoch, atomic.AddUint64(&txnCounter, 1))
+}
+
+// openTxnLog returns the transactions without a DONE record and truncates the log to them.
+// An empty path keeps no log, in doubt transactions are then left locked after a crash.
+func openTxnLog(path string) (*txnLog, []txnLogRecord, error) {
+	txns := &txnLog{path: path}
+	if path == "" {
+		return txns, nil, nil
+	}
+
+	pending := make(map[string]txnLogRecord)
+	order := []string{}
+	if file, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record txnLogRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				file.Close()
+				return nil, nil, fmt.Errorf("Error decoding transaction log : %w", err)
+			}
+			if record.State == txnDone {
+				delete(pending, record.ID)
+				continue
+			}
+			if _, ok := pending[record.ID]; !ok {
+				order = append(order, record.ID)
+			}
+			// Decisions keep the participants of the prepare
+			if previous, ok := pending[record.ID]; ok && len(record.Participants) == 0 {
+				record.Participants = previous.Participants
+			}
+			pending[record.ID] = record
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, nil, fmt.Errorf("Error reading transaction log : %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("Error opening transaction log : %w", err)
+	}
+
+	unfinished := []txnLogRecord{}
+	for _, id := range order {
+		if record, ok := pending[id]; ok {
+			unfinished = append(unfinished, record)
+		}
+	}
+
+	// Rewrite with the unfinished transactions only
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error creating transaction log : %w", err)
+	}
+	txns.file = file
+	for _, record := range unfinished {
+		if err := txns.append(record); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, nil, fmt.Errorf("Error replacing transaction log : %w", err)
+	}
+	return txns, unfinished, nil
+}
+
+func (l *txnLog) append(record txnLogRecord) error {
+	if nil == l.file {
+		return nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *txnLog) close() {
+	if nil != l.file {
+		l.file.Close()
+	}
+}
+
+// txnPart is the share of a transaction sent to one node, indexes map back to the request order
+type txnPart struct {
+	request     *pb.StorageTxnPrepareRequest
+	thenIndexes []int
+	elseIndexes []int
+}
+
+// splitTxn groups compares and operations by the node owning their key
+func splitTxn(ctx context.Context, coordinator *Coordinator, id string, request *TxnRequest) (map[string]*txnPart, error) {
+	parts := make(map[string]*txnPart)
+	part := func(key string) (*txnPart, error) {
+		nodeID, _, err := coordinator.ownerFor(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := parts[nodeID]; !ok {
+			parts[nodeID] = &txnPart{request: &pb.StorageTxnPrepareRequest{TxnID: id}}
+		}
+		return parts[nodeID], nil
+	}
+
+	for _, compare := range request.Compares {
+		p, err := part(compare.Key)
+		if err != nil {
+			return nil, err
+		}
+		p.request.Compares = append(p.request.Compares, compare)
+	}
+	for i, op := range request.Then {
+		p, err := part(op.Key)
+		if err != nil {
+			return nil, err
+		}
+		p.request.Then = append(p.request.Then, op)
+		p.thenIndexes = append(p.thenIndexes, i)
+	}
+	for i, op := range request.Else {
+		p, err := part(op.Key)
+		if err != nil {
+			return nil, err
+		}
+		p.request.Else = append(p.request.Else, op)
+		p.elseIndexes = append(p.elseIndexes, i)
+	}
+	return parts, nil
+}
+
+// Txn executes a transaction atomically across the owners of its keys with a two-phase commit,
+// a transaction on a single node runs there directly. Transactions conflicting on a key are
+// retried and fail with ErrTxnConflict when they keep conflicting. The keys written are then
+// replicated, see copyWritten.
+func (coordinator *Coordinator) Txn(ctx context.Context, request *TxnRequest) (*TxnResponse, error) {
+	response, err := retryConflicts(ctx, func() (*TxnResponse, error) {
+		return coordinator.runTxn(ctx, request)
+	})
+	if err != nil {
+		return response, err
+	}
+	return response, coordinator.copyWritten(ctx, request, response)
+}
+
+// NodeTxn executes a transaction on the node owning every one of its keys without a two-phase
+// commit. Fails with ErrCrossNodeTxn when the keys have more than one owner.
+func (coordinator *Coordinator) NodeTxn(ctx context.Context, request *TxnRequest) (*TxnResponse, error) {
+	parts, err := splitTxn(ctx, coordinator, "", request)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) > 1 {
+		owners := make([]string, 0, len(parts))
+		for nodeID := range parts {
+			owners = append(owners, nodeID)
+		}
+		sort.Strings(owners)
+		return nil, fmt.Errorf("%w: %s", ErrCrossNodeTxn, strings.Join(owners, ", "))
+	}
+
+	response, err := retryConflicts(ctx, func() (*TxnResponse, error) {
+		return coordinator.nodeTxn(ctx, parts)
+	})
+	if err != nil {
+		return response, err
+	}
+	return response, coordinator.copyWritten(ctx, request, response)
+}
+
+// copyWritten replicates the keys the branch of a transaction that ran wrote on their owners, see
+// Mutate. The transaction stays applied when a key fails to reach the write quorum, the error of
+// the first one is returned with the response.
+func (coordinator *Coordinator) copyWritten(ctx context.Context, request *TxnRequest, response *TxnResponse) error {
+	if coordinator.replicationFactor <= 1 {
+		return nil
+	}
+	ops := request.Else
+	if response.Succeeded {
+		ops = request.Then
+	}
+	copied := make(map[string]bool)
+	for _, op := range ops {
+		if op.Type != "PUT" && op.Type != "DELETE" || copied[op.Key] {
+			continue
+		}
+		copied[op.Key] = true
+		nodeID, node, err := coordinator.owner(op.Key)
+		if err == nil {
+			_, err = coordinator.copyKey(ctx, op.Key, nodeID, node)
+		}
+		if err != nil {
+			return fmt.Errorf("Error replicating %s : %w", op.Key, err)
+		}
+	}
+	return nil
+}
+
+// nodeTxn sends a transaction split to at most one node as a single Txn
+func (coordinator *Coordinator) nodeTxn(ctx context.Context, parts map[string]*txnPart) (*TxnResponse, error) {
+	for nodeID, part := range parts {
+		node, ok := coordinator.node(nodeID)
+		if !ok {
+			return nil, fmt.Errorf("Node[%v] not found", nodeID)
+		}
+		res, err := node.client.Txn(ctx, &pb.StorageTxnRequest{
+			Compares: part.request.Compares,
+			Then:     part.request.Then,
+			Else:     part.request.Else,
+		})
+		if err != nil {
+			if status.Code(err) == codes.Aborted {
+				err = fmt.Errorf("%w: %v", ErrTxnConflict, err)
+			}
+			return nil, fmt.Errorf("Node[%v] txn failed : %w", nodeID, err)
+		}
+		return &TxnResponse{Succeeded: res.Succeeded, Results: res.Results}, nil
+	}
+
+	// Nothing to compare or apply
+	return &TxnResponse{Succeeded: true, Results: []*pb.TxnOpResult{}}, nil
+}
+
+// retryConflicts runs a transaction until it does not conflict or runs out of attempts
+func retryConflicts(ctx context.Context, run func() (*TxnResponse, error)) (*TxnResponse, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := run()
+		if !errors.Is(err, ErrTxnConflict) || attempt+1 >= txnConflictRetries {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * 50 * time.Millisecond):
+		}
+	}
+}
+
+func (coordinator *Coordinator) runTxn(ctx context.Context, request *TxnRequest) (*TxnResponse, error) {
+	id := newTxnID()
+	parts, err := splitTxn(ctx, coordinator, id, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) <= 1 {
+		return coordinator.nodeTxn(ctx, parts)
+	}
+	participants := make([]string, 0, len(parts))
+	for nodeID := range parts {
+		participants = append(participants, nodeID)
+	}
+
+	if err := coordinator.txnLog.append(txnLogRecord{ID: id, State: txnPreparing, Participants: participants}); err != nil {
+		return nil, fmt.Errorf("Error logging transaction : %w", err)
+	}
+
+	// Phase 1
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	var prepareErr error
+	succeeded := true
+	for nodeID, part := range parts {
+		wg.Add(1)
+		go func(nodeID string, part *txnPart) {
+			defer wg.Done()
+			node, ok := coordinator.node(nodeID)
+			if !ok {
+				mtx.Lock()
+				prepareErr = fmt.Errorf("Node[%v] prepare failed : %w", nodeID, ErrUnknownNode)
+				mtx.Unlock()
+				return
+			}
+			res, err := node.client.TxnPrepare(ctx, part.request)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				if status.Code(err) == codes.Aborted {
+					err = fmt.Errorf("%w: %v", ErrTxnConflict, err)
+				}
+				if nil == prepareErr || errors.Is(prepareErr, ErrTxnConflict) {
+					prepareErr = fmt.Errorf("Node[%v] prepare failed : %w", nodeID, err)
+				}
+				return
+			}
+			succeeded = succeeded && res.Succeeded
+		}(nodeID, part)
+	}
+	wg.Wait()
+
+	if nil != prepareErr {
+		if err := coordinator.txnLog.append(txnLogRecord{ID: id, State: txnAbort}); err != nil {
+			log.Printf("Error logging transaction abort : %v", err)
+		}
+		coordinator.finishTxn(id, participants, txnAbort, false, txnDeadline(), nil)
+		return nil, prepareErr
+	}
+
+	// The decision is durable once logged, participants are retried until they apply it
+	if err := coordinator.txnLog.append(txnLogRecord{ID: id, State: txnCommit, Succeeded: succeeded}); err != nil {
+		coordinator.finishTxn(id, participants, txnAbort, false, txnDeadline(), nil)
+		return nil, fmt.Errorf("Error logging transaction decision : %w", err)
+	}
+
+	// Phase 2
+	response := &TxnResponse{Succeeded: succeeded}
+	ops := request.Else
+	if succeeded {
+		ops = request.Then
+	}
+	response.Results = make([]*pb.TxnOpResult, len(ops))
+	coordinator.finishTxn(id, participants, txnCommit, succeeded, txnDeadline(), func(nodeID string, results []*pb.TxnOpResult) {
+		indexes := parts[nodeID].elseIndexes
+		if succeeded {
+			indexes = parts[nodeID].thenIndexes
+		}
+		for i, result := range results {
+			if i < len(indexes) {
+				response.Results[indexes[i]] = result
+			}
+		}
+	})
+	return response, nil
+}
+
+// txnDeadline is the time a decision sent now is retried until
+func txnDeadline() time.Time {
+	return time.Now().Add(txnDecisionTimeoutSeconds * time.Second)
+}
+
+// finishTxn sends the decision to every participant, nodes that can't be reached are retried
+// in the background until the deadline. DONE is logged once every participant applied it, a
+// decision still pending at the deadline stays in the log and is resent on the next start.
+func (coordinator *Coordinator) finishTxn(id string, participants []string, decision string, succeeded bool, deadline time.Time, onResults func(nodeID string, results []*pb.TxnOpResult)) {
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	pending := []string{}
+	for _, nodeID := range participants {
+		wg.Add(1)
+		go func(nodeID string) {
+			defer wg.Done()
+			results, err := coordinator.sendDecision(nodeID, id, decision, succeeded)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				log.Printf("Txn[%s] Node[%v] %s failed : %v", id, nodeID, decision, err)
+				pending = append(pending, nodeID)
+				return
+			}
+			if nil != onResults {
+				onResults(nodeID, results)
+			}
+		}(nodeID)
+	}
+	wg.Wait()
+
+	if len(pending) == 0 {
+		if err := coordinator.txnLog.append(txnLogRecord{ID: id, State: txnDone}); err != nil {
+			log.Printf("Error logging transaction completion : %v", err)
+		}
+		return
+	}
+	if time.Now().Add(txnRetrySeconds * time.Second).After(deadline) {
+		log.Printf("Txn[%s] %s not delivered to %v before the deadline, left for recovery", id, decision, pending)
+		return
+	}
+	go func() {
+		time.Sleep(txnRetrySeconds * time.Second)
+		coordinator.finishTxn(id, pending, decision, succeeded, deadline, nil)
+	}()
+}
+
+func (coordinator *Coordinator) sendDecision(nodeID, id, decision string, succeeded bool) ([]*pb.TxnOpResult, error) {
+	node, ok := coordinator.node(nodeID)
+	if !ok {
+		return nil, fmt.Errorf("Unknown node")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout*time.Second)
+	defer cancel()
+	if decision == txnCommit {
+		res, err := node.client.TxnCommit(ctx, &pb.StorageTxnCommitRequest{TxnID: id, Succeeded: succeeded})
+		return res.GetResults(), err
+	}
+	_, err := node.client.TxnAbort(ctx, &pb.StorageTxnAbortRequest{TxnID: id})
+	return nil, err
+}
+
+// recoverTxns completes the transactions left unfinished by a previous run, undecided ones are aborted
+func recoverTxns(coordinator *Coordinator, unfinished []txnLogRecord) {
+	for _, record := range unfinished {
+		decision := record.State
+		if decision == txnPreparing {
+			decision = txnAbort
+			if err := coordinator.txnLog.append(txnLogRecord{ID: record.ID, State: txnAbort}); err != nil {
+				log.Printf("Error logging transaction abort : %v", err)
+			}
+		}
+		log.Printf("Recovering Txn[%s] : %s", record.ID, decision)
+		coordinator.finishTxn(record.ID, record.Participants, decision, record.Succeeded, txnDeadline(), nil)
+	}
+}
+
+// parseTxn reads IF Compare ... THEN Op ... [ELSE Op ...] where a compare is Key EXISTS =|!= or
+// Key VERSION|VALUE Op Operand and an op is PUT Key Value, DELETE Key or GET Key
+func parseTxn(parts []string) (*TxnRequest, error) {
+	request := &TxnRequest{}
+	i := 0
+	if i < len(parts) && strings.ToUpper(parts[i]) == "IF" {
+		i++
+		for i < len(parts) && strings.ToUpper(parts[i]) != "THEN" {
+			if i+3 > len(parts) {
+				return nil, fmt.Errorf("Incomplete compare")
+			}
+			compare := &pb.TxnCompare{Key: parts[i], Target: strings.ToUpper(parts[i+1]), Result: parts[i+2]}
+			if compare.Target == "EXISTS" {
+				if compare.Result != "=" && compare.Result != "!=" {
+					return nil, fmt.Errorf("EXISTS only compares with = or !=")
+				}
+				request.Compares = append(request.Compares, compare)
+				i += 3
+				continue
+			}
+			if i+4 > len(parts) {
+				return nil, fmt.Errorf("Incomplete compare")
+			}
+			switch compare.Target {
+			case "VERSION":
+				version, err := strconv.ParseUint(parts[i+3], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Version must be a non negative integer")
+				}
+				compare.Version = version
+			case "VALUE":
+				compare.Value = []byte(parts[i+3])
+			default:
+				return nil, fmt.Errorf("Compare target must be EXISTS, VERSION or VALUE")
+			}
+			request.Compares = append(request.Compares, compare)
+			i += 4
+		}
+	}
+	if i >= len(parts) || strings.ToUpper(parts[i]) != "THEN" {
+		return nil, fmt.Errorf("Missing THEN")
+	}
+	i++
+
+	branch := &request.Then
+	for i < len(parts) {
+		opType := strings.ToUpper(parts[i])
+		switch opType {
+		case "ELSE":
+			if branch == &request.Else {
+				return nil, fmt.Errorf("Duplicate ELSE")
+			}
+			branch = &request.Else
+			i++
+			continue
+		case "PUT":
+			if i+3 > len(parts) {
+				return nil, fmt.Errorf("PUT needs a key and a value")
+			}
+			*branch = append(*branch, &pb.TxnOp{Type: opType, Key: parts[i+1], Value: []byte(parts[i+2])})
+			i += 3
+		case "DELETE", "GET":
+			if i+2 > len(parts) {
+				return nil, fmt.Errorf("%s needs a key", opType)
+			}
+			*branch = append(*branch, &pb.TxnOp{Type: opType, Key: parts[i+1]})
+			i += 2
+		default:
+			return nil, fmt.Errorf("Unknown operation: %s", parts[i])
+		}
+	}
+	return request, nil
+}
+
+func txn(coordinator *Coordinator, parts []string) {
+	// LOCAL runs the transaction on the single owner of its keys
+	local := len(parts) > 0 && strings.ToUpper(parts[0]) == "LOCAL"
+	if local {
+		parts = parts[1:]
+	}
+	request, err := parseTxn(parts)
+	if err != nil {
+		fmt.Printf("Invalid TXN command. %v\n", err)
+		return
+	}
+
+	run := coordinator.Txn
+	if local {
+		run = coordinator.NodeTxn
+	}
+	response, err := run(context.Background(), request)
+	if err != nil {
+		fmt.Printf("Txn failed : %v\n", err)
+		return
+	}
+
+	fmt.Printf("Succeeded : %v\n", response.Succeeded)
+	ops := request.Else
+	if response.Succeeded {
+		ops = request.Then
+	}
+	for i, result := range response.Results {
+		if nil == result {
+			fmt.Printf("%s %s : pending\n", ops[i].Type, ops[i].Key)
+			continue
+		}
+		switch ops[i].Type {
+		case "GET":
+			fmt.Printf("GET %s : Found : %v Value : %s Version : %v\n", result.Key, result.Found, result.GetValue(), result.Version)
+		case "DELETE":
+			fmt.Printf("DELETE %s : Delete Status : %v\n", result.Key, result.Found)
+		default:
+			fmt.Printf("PUT %s : Version : %v\n", result.Key, result.Version)
+		}
+	}
+}
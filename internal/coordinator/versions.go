@@ -0,0 +1,181 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// Versioning of a keyspace
+const (
+	VersioningLWW         = "lww"
+	VersioningVectorClock = "vclock"
+)
+
+var ErrVersioned = errors.New("key is versioned with vector clocks")
+
+// newKeyspaces checks the versioning of the keyspaces, the longest prefix comes first
+func newKeyspaces(keyspaces []Keyspace) ([]Keyspace, error) {
+	sorted := append([]Keyspace{}, keyspaces...)
+	for _, keyspace := range sorted {
+		switch keyspace.Versioning {
+		case "", VersioningLWW, VersioningVectorClock:
+		default:
+			return nil, fmt.Errorf("Unknown versioning %s of keyspace %q", keyspace.Versioning, keyspace.Prefix)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Prefix) > len(sorted[j].Prefix)
+	})
+	return sorted, nil
+}
+
+// Keyspaces returns the versioning of the keyspaces, longest prefix first
+func (coordinator *Coordinator) Keyspaces() []Keyspace {
+	return append([]Keyspace{}, coordinator.keyspaces...)
+}
+
+// VectorClocks reports whether the keyspace of a key keeps concurrent writes as siblings
+func (coordinator *Coordinator) VectorClocks(key string) bool {
+	for _, keyspace := range coordinator.keyspaces {
+		if strings.HasPrefix(key, keyspace.Prefix) {
+			return keyspace.Versioning == VersioningVectorClock
+		}
+	}
+	return false
+}
+
+// Versions are the concurrent values of a key, a write carrying Context replaces them all
+type Versions struct {
+	Values  [][]byte
+	Context string
+}
+
+// GetVersions sends a read of the siblings of a key versioned with vector clocks to every replica
+// and merges them once the read quorum answered, see read. Fails like read when too few replicas
+// answer.
+func (coordinator *Coordinator) GetVersions(ctx context.Context, key string) (Versions, error) {
+	replicas, nodes, _, err := coordinator.replicas(key)
+	if err != nil {
+		return Versions{}, err
+	}
+	quorum := min(max(coordinator.readRepair.quorum, 1), len(replicas))
+	if coordinator.readRepair.quorum == 0 {
+		replicas = replicas[:1]
+	}
+
+	type siblingsRead struct {
+		nodeID string
+		res    *pb.StorageGetSiblingsResponse
+		err    error
+	}
+	answers := make(chan siblingsRead, len(replicas))
+	for _, nodeID := range replicas {
+		go func(nodeID string, node *NodeConnection) {
+			read := siblingsRead{nodeID: nodeID}
+			if nil == node {
+				read.err = fmt.Errorf("%w: %s", ErrUnknownNode, nodeID)
+			} else {
+				read.res, read.err = node.client.GetSiblings(ctx, &pb.StorageGetSiblingsRequest{Key: key})
+			}
+			answers <- read
+		}(nodeID, nodes[nodeID])
+	}
+
+	siblings := []utils.Sibling{}
+	answered := 0
+	var failed error
+	for range replicas {
+		read := <-answers
+		if read.err != nil {
+			if nil == failed || read.nodeID == replicas[0] {
+				failed = read.err
+			}
+			continue
+		}
+		for _, sibling := range read.res.Siblings {
+			siblings = utils.MergeSiblings(siblings, utils.Sibling{Value: sibling.Value, Context: sibling.Context, Dot: utils.Dot{Node: sibling.DotNode, Counter: sibling.DotCounter}})
+		}
+		if answered++; answered >= quorum {
+			break
+		}
+	}
+	switch {
+	case answered >= quorum:
+	case quorum == 1:
+		return Versions{}, failed
+	default:
+		return Versions{}, fmt.Errorf("%w: %d of %d : %v", ErrQuorum, answered, quorum, failed)
+	}
+
+	versions := Versions{Values: [][]byte{}, Context: utils.EncodeContext(utils.SiblingsClock(siblings))}
+	for _, sibling := range siblings {
+		versions.Values = append(versions.Values, sibling.Value)
+	}
+	return versions, nil
+}
+
+// PutVersion writes a value of a key versioned with vector clocks. It replaces the values the
+// context of GetVersions was read with, an empty context keeps the value next to the present ones.
+// The first replica that answers coordinates the write, the others merge the sibling it created.
+// See replicate.
+func (coordinator *Coordinator) PutVersion(ctx context.Context, key string, value []byte, token string) (WriteResult, error) {
+	clock, err := utils.DecodeContext(token)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	replicas, nodes, _, err := coordinator.replicas(key)
+	if err != nil {
+		return WriteResult{}, err
+	}
+
+	timestamp := time.Now().UnixNano()
+	var created *pb.StorageSibling
+	for _, nodeID := range replicas {
+		node := nodes[nodeID]
+		if nil == node {
+			continue
+		}
+		// A key still moving to the replica is pulled first, its counters go on from the moved ones
+		if err := coordinator.pull(ctx, key, nodeID, node); err != nil {
+			return WriteResult{Replicas: replicas}, err
+		}
+		res, err := node.client.PutSibling(ctx, &pb.StoragePutSiblingRequest{Key: key, Value: value, Context: clock, Timestamp: timestamp})
+		if err == nil {
+			created = res.Sibling
+			break
+		}
+		if !unreachable(err) {
+			return WriteResult{Replicas: replicas}, err
+		}
+	}
+	if nil == created {
+		return WriteResult{Replicas: replicas}, fmt.Errorf("%w: no replica of %s answered", ErrQuorum, key)
+	}
+
+	sibling := &utils.Sibling{Value: created.Value, Context: created.Context, Dot: utils.Dot{Node: created.DotNode, Counter: created.DotCounter}}
+	return coordinator.replicate(ctx, mutation{Key: key, Timestamp: timestamp, Sibling: sibling})
+}
+
+func getVersions(coordinator *Coordinator, key string) {
+	versions, err := coordinator.GetVersions(context.Background(), key)
+	if err != nil {
+		fmt.Printf("GetVersions failed : %v\n", err)
+		return
+	}
+	for _, value := range versions.Values {
+		fmt.Printf("Value : %v\n", string(value))
+	}
+	fmt.Printf("Context : %v\n", versions.Context)
+}
+
+func putVersion(coordinator *Coordinator, key, value, token string) {
+	result, err := coordinator.PutVersion(context.Background(), key, []byte(value), token)
+	printWrite("Put", result, err)
+}
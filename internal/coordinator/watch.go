@@ -0,0 +1,171 @@
+package coordinator
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const watchRetrySeconds = 1
+
+// proxyWatch forwards the Watch streams of the nodes owning a key, or of every node for a prefix
+type proxyWatch struct {
+	id      int
+	key     string
+	prefix  bool
+	onEvent func(nodeID string, event *pb.StorageWatchEvent)
+
+	mtx     sync.Mutex
+	streams map[string]context.CancelFunc // Node ID -> stream
+	lastLSN map[string]uint64             // Node ID -> last LSN received, used to resume
+}
+
+// watchOwners returns the nodes holding the watched keys
+func watchOwners(coordinator *Coordinator, w *proxyWatch) []string {
+	if w.prefix {
+		// Prefixes are spread across the ring
+		return coordinator.nodeIDs()
+	}
+	nodeID, _, err := coordinator.owner(w.key)
+	if err != nil {
+		return []string{}
+	}
+	return []string{nodeID}
+}
+
+// startWatch proxies a watch to the owning nodes, events are passed to onEvent from the stream goroutines.
+// startLSN is applied to every owner, 0 only watches new changes.
+func startWatch(coordinator *Coordinator, key string, prefix bool, startLSN uint64, onEvent func(nodeID string, event *pb.StorageWatchEvent)) int {
+	w := &proxyWatch{
+		key:     key,
+		prefix:  prefix,
+		onEvent: onEvent,
+		streams: make(map[string]context.CancelFunc),
+		lastLSN: make(map[string]uint64),
+	}
+
+	coordinator.watchMtx.Lock()
+	coordinator.nextWatchID++
+	w.id = coordinator.nextWatchID
+	coordinator.watches[w.id] = w
+	coordinator.watchMtx.Unlock()
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for _, nodeID := range watchOwners(coordinator, w) {
+		if startLSN > 0 {
+			w.lastLSN[nodeID] = startLSN - 1
+		}
+		w.connect(coordinator, nodeID)
+	}
+	return w.id
+}
+
+// stopWatch closes every stream of the watch, returns false for an unknown id
+func stopWatch(coordinator *Coordinator, id int) bool {
+	coordinator.watchMtx.Lock()
+	w, ok := coordinator.watches[id]
+	delete(coordinator.watches, id)
+	coordinator.watchMtx.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for nodeID, cancel := range w.streams {
+		cancel()
+		delete(w.streams, nodeID)
+	}
+	return true
+}
+
+// refreshWatches moves the watches to the current owners, called after the ring changes.
+// Streams to a new owner start from its current LSN since LSNs are per node.
+func refreshWatches(coordinator *Coordinator) {
+	coordinator.watchMtx.Lock()
+	defer coordinator.watchMtx.Unlock()
+
+	for _, w := range coordinator.watches {
+		owners := make(map[string]bool)
+		for _, nodeID := range watchOwners(coordinator, w) {
+			owners[nodeID] = true
+		}
+
+		w.mtx.Lock()
+		for nodeID, cancel := range w.streams {
+			if !owners[nodeID] {
+				cancel()
+				delete(w.streams, nodeID)
+				delete(w.lastLSN, nodeID)
+			}
+		}
+		for nodeID := range owners {
+			if _, ok := w.streams[nodeID]; !ok {
+				w.connect(coordinator, nodeID)
+			}
+		}
+		w.mtx.Unlock()
+	}
+}
+
+// connect starts the stream to a node. Caller must hold w.mtx.
+func (w *proxyWatch) connect(coordinator *Coordinator, nodeID string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.streams[nodeID] = cancel
+	go w.run(ctx, coordinator, nodeID)
+}
+
+// run keeps a stream to the node open, reconnecting from the last received LSN until ctx is cancelled
+func (w *proxyWatch) run(ctx context.Context, coordinator *Coordinator, nodeID string) {
+	for {
+		node, ok := coordinator.node(nodeID)
+		if !ok {
+			return
+		}
+
+		w.mtx.Lock()
+		request := &pb.StorageWatchRequest{Key: w.key, Prefix: w.prefix}
+		if lastLSN, ok := w.lastLSN[nodeID]; ok {
+			request.StartLSN = lastLSN + 1
+		}
+		w.mtx.Unlock()
+
+		stream, err := node.client.Watch(ctx, request)
+		for err == nil {
+			var event *pb.StorageWatchEvent
+			if event, err = stream.Recv(); err != nil {
+				break
+			}
+			w.mtx.Lock()
+			w.lastLSN[nodeID] = event.LSN
+			w.mtx.Unlock()
+			w.onEvent(nodeID, event)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.OutOfRange {
+			// History is gone, carry on with new changes only
+			log.Printf("Watch[%d] Node[%v] : %v, resuming from the latest LSN", w.id, nodeID, err)
+			w.mtx.Lock()
+			delete(w.lastLSN, nodeID)
+			w.mtx.Unlock()
+		} else {
+			log.Printf("Watch[%d] Node[%v] stream closed : %v, reconnecting", w.id, nodeID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetrySeconds * time.Second):
+		}
+	}
+}
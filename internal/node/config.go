@@ -18,9 +18,17 @@ type Config struct {
 	} `yaml:"Network"`
 
 	HashTable struct {
-		NumBuckets int `yaml:"NumBuckets"`
+		NumBuckets     int    `yaml:"NumBuckets"`
+		MaxMemoryBytes int64  `yaml:"MaxMemoryBytes"` // 0 for no limit
+		EvictionPolicy string `yaml:"EvictionPolicy"` // noeviction, allkeys-lru, allkeys-lfu, volatile-ttl
 	} `yaml:"HashTable"`
 
+	// Secondary indexes on JSON values, Path is a dotted field path e.g. profile.email
+	Indexes []struct {
+		Name string `yaml:"Name"`
+		Path string `yaml:"Path"`
+	} `yaml:"Indexes"`
+
 	Log struct {
 		File string `yaml:"File"`
 	} `yaml:"Log"`
@@ -31,6 +39,24 @@ type Config struct {
 		WALFile        string `yaml:"WALFile"`
 	} `yaml:"Checkpoint"`
 
+	// SWIM gossip among the nodes. Address is where the other nodes reach this one, Seeds are
+	// addresses of nodes already in the cluster. Timings are in milliseconds, 0 for the defaults.
+	Gossip struct {
+		Enabled                bool     `yaml:"Enabled"`
+		Address                string   `yaml:"Address"`
+		Seeds                  []string `yaml:"Seeds"`
+		IntervalMillis         int      `yaml:"IntervalMillis"`
+		PingTimeoutMillis      int      `yaml:"PingTimeoutMillis"`
+		IndirectChecks         int      `yaml:"IndirectChecks"`
+		SuspicionTimeoutMillis int      `yaml:"SuspicionTimeoutMillis"`
+	} `yaml:"Gossip"`
+
+	// File the ring pushed by the coordinator is saved to, a restarted node keeps rejecting the keys
+	// it does not own. Empty keeps the ring in memory only.
+	Ownership struct {
+		RingFile string `yaml:"RingFile"`
+	} `yaml:"Ownership"`
+
 	Recover struct {
 		CheckpointFile *string `yaml:"CheckpointFile"`
 		WALFile        *string `yaml:"WALFile"`
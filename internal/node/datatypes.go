@@ -0,0 +1,252 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *StorageServer) ListPush(ctx context.Context, request *pb.StorageListPushRequest) (*pb.StorageListPushResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if len(request.Values) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Values cannot be empty")
+	}
+
+	log.Printf("Received ListPush request: Key[%s]/Count[%d]/Left[%v]", request.Key, len(request.Values), request.Left)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	length, err := s.HashTable.ListPush(request.Key, request.Values, request.Left, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageListPushResponse{
+		Length: uint64(length),
+	}, nil
+}
+
+func (s *StorageServer) ListPop(ctx context.Context, request *pb.StorageListPopRequest) (*pb.StorageListPopResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received ListPop request: Key[%s]/Count[%d]/Left[%v]", request.Key, request.Count, request.Left)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	count := int(request.Count)
+	if count == 0 {
+		count = 1
+	}
+
+	values, err := s.HashTable.ListPop(request.Key, count, request.Left, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageListPopResponse{
+		Values: values,
+	}, nil
+}
+
+func (s *StorageServer) ListRange(ctx context.Context, request *pb.StorageListRangeRequest) (*pb.StorageListRangeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received ListRange request: Key[%s]/Start[%d]/Stop[%d]", request.Key, request.Start, request.Stop)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	values, err := s.HashTable.ListRange(request.Key, int(request.Start), int(request.Stop))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageListRangeResponse{
+		Values: values,
+	}, nil
+}
+
+func (s *StorageServer) SetAdd(ctx context.Context, request *pb.StorageSetAddRequest) (*pb.StorageSetAddResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if len(request.Members) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Members cannot be empty")
+	}
+
+	log.Printf("Received SetAdd request: Key[%s]/Count[%d]", request.Key, len(request.Members))
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	added, err := s.HashTable.SetAdd(request.Key, request.Members, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageSetAddResponse{
+		Added: uint64(added),
+	}, nil
+}
+
+func (s *StorageServer) SetRemove(ctx context.Context, request *pb.StorageSetRemoveRequest) (*pb.StorageSetRemoveResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received SetRemove request: Key[%s]/Count[%d]", request.Key, len(request.Members))
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	removed, err := s.HashTable.SetRemove(request.Key, request.Members, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageSetRemoveResponse{
+		Removed: uint64(removed),
+	}, nil
+}
+
+func (s *StorageServer) SetMembers(ctx context.Context, request *pb.StorageSetMembersRequest) (*pb.StorageSetMembersResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received SetMembers request: Key[%s]", request.Key)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	members, err := s.HashTable.SetMembers(request.Key)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageSetMembersResponse{
+		Members: members,
+	}, nil
+}
+
+func (s *StorageServer) SetIsMember(ctx context.Context, request *pb.StorageSetIsMemberRequest) (*pb.StorageSetIsMemberResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received SetIsMember request: Key[%s]", request.Key)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.HashTable.SetIsMember(request.Key, request.Member)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageSetIsMemberResponse{
+		IsMember: isMember,
+	}, nil
+}
+
+func (s *StorageServer) HashSet(ctx context.Context, request *pb.StorageHashSetRequest) (*pb.StorageHashSetResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if nil == request.Value {
+		return nil, status.Errorf(codes.InvalidArgument, "Value cannot be empty")
+	}
+
+	log.Printf("Received HashSet request: Key[%s]/Field[%s]/Value[%v]", request.Key, request.Field, request.Value)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	isNew, err := s.HashTable.HashSet(request.Key, request.Field, request.Value, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageHashSetResponse{
+		IsNew: isNew,
+	}, nil
+}
+
+func (s *StorageServer) HashGet(ctx context.Context, request *pb.StorageHashGetRequest) (*pb.StorageHashGetResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received HashGet request: Key[%s]/Field[%s]", request.Key, request.Field)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	value, isFound, err := s.HashTable.HashGet(request.Key, request.Field)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if false == isFound {
+		return &pb.StorageHashGetResponse{
+			Found: false,
+			Value: nil,
+		}, nil
+	}
+
+	return &pb.StorageHashGetResponse{
+		Found: true,
+		Value: value,
+	}, nil
+}
+
+func (s *StorageServer) HashDelete(ctx context.Context, request *pb.StorageHashDeleteRequest) (*pb.StorageHashDeleteResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received HashDelete request: Key[%s]/Fields[%v]", request.Key, request.Fields)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.HashTable.HashDelete(request.Key, request.Fields, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageHashDeleteResponse{
+		Deleted: uint64(deleted),
+	}, nil
+}
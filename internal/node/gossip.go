@@ -0,0 +1,286 @@
+package node
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gossip defaults, used when the config leaves them at 0
+const (
+	gossipIntervalMillis         = 1000
+	gossipPingTimeoutMillis      = 300
+	gossipIndirectChecks         = 3
+	gossipSuspicionTimeoutMillis = 5000
+	gossipPiggybackUpdates       = 8
+)
+
+// Gossip runs the SWIM protocol of a node. Every interval it pings one member, a member that
+// does not ack is pinged through IndirectChecks other members and suspected when none of them
+// reaches it. Membership updates are piggybacked on the pings and their acks.
+type Gossip struct {
+	Membership     *utils.Membership
+	seeds          []string
+	interval       time.Duration
+	pingTimeout    time.Duration
+	indirectChecks int
+
+	connMtx sync.Mutex
+	conns   map[string]*grpc.ClientConn
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func NewGossip(config *Config) *Gossip {
+	interval, pingTimeout := config.Gossip.IntervalMillis, config.Gossip.PingTimeoutMillis
+	indirectChecks, suspicionTimeout := config.Gossip.IndirectChecks, config.Gossip.SuspicionTimeoutMillis
+	if interval == 0 {
+		interval = gossipIntervalMillis
+	}
+	if pingTimeout == 0 {
+		pingTimeout = gossipPingTimeoutMillis
+	}
+	if indirectChecks == 0 {
+		indirectChecks = gossipIndirectChecks
+	}
+	if suspicionTimeout == 0 {
+		suspicionTimeout = gossipSuspicionTimeoutMillis
+	}
+
+	return &Gossip{
+		Membership:     utils.NewMembership(config.NodeID, config.Gossip.Address, time.Duration(suspicionTimeout)*time.Millisecond),
+		seeds:          config.Gossip.Seeds,
+		interval:       time.Duration(interval) * time.Millisecond,
+		pingTimeout:    time.Duration(pingTimeout) * time.Millisecond,
+		indirectChecks: indirectChecks,
+		conns:          make(map[string]*grpc.ClientConn),
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+// Start joins the cluster through the seeds and probes the members until Stop is called
+func (g *Gossip) Start() {
+	go g.run()
+}
+
+// Stop ends the probes without telling the other members, they detect the node as failed
+func (g *Gossip) Stop() {
+	close(g.done)
+	<-g.stopped
+
+	g.connMtx.Lock()
+	defer g.connMtx.Unlock()
+	for address, conn := range g.conns {
+		conn.Close()
+		delete(g.conns, address)
+	}
+}
+
+// Leave tells a few members the node is leaving, the others learn it through them
+func (g *Gossip) Leave(ctx context.Context) {
+	self := g.Membership.Self()
+	self.State = utils.MemberDead
+	updates := toStorageMembers([]utils.Member{self})
+
+	for _, member := range g.Membership.RandomMembers(g.indirectChecks, "") {
+		pingCtx, cancel := context.WithTimeout(ctx, g.pingTimeout)
+		_, err := g.client(member.Address).GossipPing(pingCtx, &pb.StorageGossipPingRequest{Target: member.ID, Updates: updates})
+		cancel()
+		if err != nil {
+			log.Printf("Leave notice to Node[%v] failed : %v", member.ID, err)
+		}
+	}
+}
+
+func (g *Gossip) run() {
+	defer close(g.stopped)
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	g.join()
+	for {
+		select {
+		case <-g.done:
+			return
+		case <-ticker.C:
+		}
+
+		g.probe()
+		for _, nodeID := range g.Membership.ExpireSuspects(time.Now()) {
+			log.Printf("Node[%v] declared dead", nodeID)
+		}
+		// A node alone in its view keeps asking the seeds, they may have been down when it started
+		if len(g.Membership.Live()) == 1 {
+			g.join()
+		}
+	}
+}
+
+// join exchanges the full membership with every seed
+func (g *Gossip) join() {
+	self := g.Membership.Self()
+	for _, seed := range g.seeds {
+		if seed == self.Address {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), g.interval)
+		res, err := g.client(seed).GossipSync(ctx, &pb.StorageGossipSyncRequest{Members: toStorageMembers(g.Membership.Members())})
+		cancel()
+		if err != nil {
+			log.Printf("Gossip sync with seed[%s] failed : %v", seed, err)
+			continue
+		}
+		g.Membership.Apply(fromStorageMembers(res.Members))
+	}
+}
+
+// probe pings the next member, directly then through other members
+func (g *Gossip) probe() {
+	target, ok := g.Membership.NextProbe()
+	if !ok {
+		return
+	}
+	if err := g.ping(context.Background(), target); err == nil {
+		return
+	}
+
+	helpers := g.Membership.RandomMembers(g.indirectChecks, target.ID)
+	acks := make(chan bool, len(helpers))
+	for _, helper := range helpers {
+		go func(helper utils.Member) {
+			// The helper needs a ping timeout of its own to reach the target
+			ctx, cancel := context.WithTimeout(context.Background(), 2*g.pingTimeout)
+			defer cancel()
+			res, err := g.client(helper.Address).GossipPingReq(ctx, &pb.StorageGossipPingReqRequest{
+				Target:  toStorageMember(target),
+				Updates: toStorageMembers(g.Membership.Piggyback(gossipPiggybackUpdates)),
+			})
+			if err != nil {
+				acks <- false
+				return
+			}
+			g.Membership.Apply(fromStorageMembers(res.Updates))
+			acks <- res.Acked
+		}(helper)
+	}
+	for range helpers {
+		if <-acks {
+			return
+		}
+	}
+
+	log.Printf("Node[%v] did not answer, suspecting it", target.ID)
+	g.Membership.Suspect(target.ID)
+}
+
+// ping sends the piggybacked updates to a member and applies the ones it acks with
+func (g *Gossip) ping(ctx context.Context, member utils.Member) error {
+	ctx, cancel := context.WithTimeout(ctx, g.pingTimeout)
+	defer cancel()
+
+	res, err := g.client(member.Address).GossipPing(ctx, &pb.StorageGossipPingRequest{
+		Target:  member.ID,
+		Updates: toStorageMembers(g.Membership.Piggyback(gossipPiggybackUpdates)),
+	})
+	if err != nil {
+		return err
+	}
+	g.Membership.Apply(fromStorageMembers(res.Updates))
+	return nil
+}
+
+// client returns the connection to a member, dialed once and reused
+func (g *Gossip) client(address string) pb.StorageClient {
+	g.connMtx.Lock()
+	defer g.connMtx.Unlock()
+
+	conn, ok := g.conns[address]
+	if !ok {
+		// Dialing without blocking only fails on a malformed address, the RPCs report it
+		conn, _ = grpc.Dial(address, grpc.WithInsecure())
+		g.conns[address] = conn
+	}
+	return pb.NewStorageClient(conn)
+}
+
+func toStorageMember(member utils.Member) *pb.StorageMember {
+	return &pb.StorageMember{
+		NodeID:      member.ID,
+		Address:     member.Address,
+		State:       member.State,
+		Incarnation: member.Incarnation,
+	}
+}
+
+func toStorageMembers(members []utils.Member) []*pb.StorageMember {
+	storageMembers := make([]*pb.StorageMember, 0, len(members))
+	for _, member := range members {
+		storageMembers = append(storageMembers, toStorageMember(member))
+	}
+	return storageMembers
+}
+
+func fromStorageMembers(storageMembers []*pb.StorageMember) []utils.Member {
+	members := make([]utils.Member, 0, len(storageMembers))
+	for _, member := range storageMembers {
+		members = append(members, utils.Member{
+			ID:          member.NodeID,
+			Address:     member.Address,
+			State:       member.State,
+			Incarnation: member.Incarnation,
+		})
+	}
+	return members
+}
+
+func (s *StorageServer) GossipPing(ctx context.Context, request *pb.StorageGossipPingRequest) (*pb.StorageGossipPingResponse, error) {
+	if nil == s.Gossip {
+		return nil, status.Errorf(codes.FailedPrecondition, "Gossip is disabled")
+	}
+	if request.Target != s.NodeID {
+		return nil, status.Errorf(codes.FailedPrecondition, "Node[%s] is not Node[%s]", s.NodeID, request.Target)
+	}
+
+	s.Gossip.Membership.Apply(fromStorageMembers(request.Updates))
+	return &pb.StorageGossipPingResponse{
+		Updates: toStorageMembers(s.Gossip.Membership.Piggyback(gossipPiggybackUpdates)),
+	}, nil
+}
+
+func (s *StorageServer) GossipPingReq(ctx context.Context, request *pb.StorageGossipPingReqRequest) (*pb.StorageGossipPingReqResponse, error) {
+	if nil == s.Gossip {
+		return nil, status.Errorf(codes.FailedPrecondition, "Gossip is disabled")
+	}
+	if nil == request.Target {
+		return nil, status.Errorf(codes.InvalidArgument, "Ping request without a target")
+	}
+
+	s.Gossip.Membership.Apply(fromStorageMembers(request.Updates))
+	target := fromStorageMembers([]*pb.StorageMember{request.Target})[0]
+	acked := s.Gossip.ping(ctx, target) == nil
+	return &pb.StorageGossipPingReqResponse{
+		Acked:   acked,
+		Updates: toStorageMembers(s.Gossip.Membership.Piggyback(gossipPiggybackUpdates)),
+	}, nil
+}
+
+func (s *StorageServer) GossipSync(ctx context.Context, request *pb.StorageGossipSyncRequest) (*pb.StorageGossipSyncResponse, error) {
+	if nil == s.Gossip {
+		return nil, status.Errorf(codes.FailedPrecondition, "Gossip is disabled")
+	}
+
+	s.Gossip.Membership.Apply(fromStorageMembers(request.Members))
+	return &pb.StorageGossipSyncResponse{
+		Members: toStorageMembers(s.Gossip.Membership.Members()),
+		Version: s.Gossip.Membership.Version(),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *StorageServer) LeaseGrant(ctx context.Context, request *pb.StorageLeaseGrantRequest) (*pb.StorageLeaseGrantResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.TTLSeconds <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "TTL must be positive")
+	}
+
+	lease := s.HashTable.GrantLease(request.TTLSeconds, s.RInfo)
+	log.Printf("Granted lease[%d] with TTL[%d]", lease.ID, lease.TTLSeconds)
+
+	return &pb.StorageLeaseGrantResponse{
+		ID:         lease.ID,
+		TTLSeconds: lease.TTLSeconds,
+	}, nil
+}
+
+func (s *StorageServer) LeaseKeepAlive(ctx context.Context, request *pb.StorageLeaseKeepAliveRequest) (*pb.StorageLeaseKeepAliveResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	lease, err := s.HashTable.KeepAliveLease(request.ID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageLeaseKeepAliveResponse{
+		TTLSeconds: lease.TTLSeconds,
+	}, nil
+}
+
+func (s *StorageServer) LeaseRevoke(ctx context.Context, request *pb.StorageLeaseRevokeRequest) (*pb.StorageLeaseRevokeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received LeaseRevoke request: Lease[%d]", request.ID)
+
+	deleted, err := s.HashTable.RevokeLease(request.ID, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageLeaseRevokeResponse{
+		KeysDeleted: uint64(deleted),
+	}, nil
+}
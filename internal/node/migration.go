@@ -0,0 +1,189 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toHashRanges(ranges []*pb.StorageHashRange) []utils.HashRange {
+	converted := make([]utils.HashRange, 0, len(ranges))
+	for _, r := range ranges {
+		converted = append(converted, utils.HashRange{Start: r.Start, End: r.End})
+	}
+	return converted
+}
+
+// keyMatch selects the keys of a transfer or drop request. With a placement the transfer selects
+// the keys replicated on owner, the drop the ones no longer replicated on it.
+func keyMatch(ranges []*pb.StorageHashRange, hashName string, placement *pb.StoragePlacement, replicationFactor uint32, owner string, drop bool) (func(string) bool, error) {
+	if nil == placement {
+		hash, err := utils.ParseHash(hashName)
+		if err != nil {
+			return nil, err
+		}
+		return utils.InRanges(toHashRanges(ranges), hash), nil
+	}
+
+	p, err := utils.PlacementFromProto(placement)
+	if err != nil {
+		return nil, err
+	}
+	replicated := utils.ReplicatedBy(p, max(int(replicationFactor), 1), owner)
+	if drop {
+		return func(key string) bool { return !replicated(key) }, nil
+	}
+	return replicated, nil
+}
+
+func toStorageEntry(entry utils.Entry) *pb.StorageEntry {
+	storageEntry := &pb.StorageEntry{
+		Key:       entry.Key,
+		ExpiresAt: entry.ExpiresAt,
+	}
+	switch entry.Type {
+	case utils.TypeString:
+		storageEntry.Value = entry.Value
+	case utils.TypeList:
+		storageEntry.Type = entry.Type.String()
+		storageEntry.Items = entry.List
+	case utils.TypeSet:
+		storageEntry.Type = entry.Type.String()
+		for member := range entry.Set {
+			storageEntry.Items = append(storageEntry.Items, []byte(member))
+		}
+	case utils.TypeHash:
+		storageEntry.Type = entry.Type.String()
+		storageEntry.Fields = entry.Hash
+	case utils.TypeVersioned:
+		storageEntry.Type = entry.Type.String()
+		for _, sibling := range entry.Siblings {
+			storageEntry.Siblings = append(storageEntry.Siblings, toStorageSibling(sibling))
+		}
+	}
+	return storageEntry
+}
+
+func fromStorageEntry(storageEntry *pb.StorageEntry) (utils.Entry, error) {
+	valueType, err := utils.ParseValueType(storageEntry.Type)
+	if err != nil {
+		return utils.Entry{}, err
+	}
+
+	entry := utils.Entry{Key: storageEntry.Key, ExpiresAt: storageEntry.ExpiresAt, Type: valueType}
+	switch valueType {
+	case utils.TypeString:
+		entry.Value = storageEntry.Value
+	case utils.TypeList:
+		entry.List = storageEntry.Items
+	case utils.TypeSet:
+		entry.Set = make(map[string]struct{}, len(storageEntry.Items))
+		for _, item := range storageEntry.Items {
+			entry.Set[string(item)] = struct{}{}
+		}
+	case utils.TypeHash:
+		entry.Hash = storageEntry.Fields
+	case utils.TypeVersioned:
+		for _, sibling := range storageEntry.Siblings {
+			entry.Siblings = append(entry.Siblings, fromStorageSibling(sibling))
+		}
+	}
+	return entry, nil
+}
+
+func (s *StorageServer) TransferKeys(request *pb.StorageTransferKeysRequest, stream pb.Storage_TransferKeysServer) error {
+	if nil == request {
+		log.Println("Empty request received")
+		return fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received TransferKeys request: Ranges[%d] Owner[%s]", len(request.Ranges), request.Owner)
+
+	match, err := keyMatch(request.Ranges, request.Hash, request.Placement, request.ReplicationFactor, request.Owner, false)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	var entries []utils.ReplicaEntry
+	if len(request.Keys) != 0 {
+		entries = s.HashTable.ExportNamed(request.Keys)
+	} else {
+		entries = s.HashTable.ExportKeys(match)
+	}
+	for _, entry := range entries {
+		if err := stream.Send(fromReplicaEntry(entry)); err != nil {
+			return err
+		}
+	}
+	log.Printf("Transferred %d keys", len(entries))
+	return nil
+}
+
+func (s *StorageServer) ImportKeys(stream pb.Storage_ImportKeysServer) error {
+	response := &pb.StorageImportKeysResponse{}
+	for {
+		storageEntry, err := stream.Recv()
+		if err == io.EOF {
+			log.Printf("Imported %d keys, skipped %d", response.Imported, response.Skipped)
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		entry, err := toReplicaEntry(storageEntry)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		imported, err := s.HashTable.Import(entry, s.RInfo)
+		if err != nil {
+			return toStatus(err)
+		}
+		if imported {
+			response.Imported++
+		} else {
+			response.Skipped++
+		}
+	}
+}
+
+func (s *StorageServer) DropKeys(ctx context.Context, request *pb.StorageDropKeysRequest) (*pb.StorageDropKeysResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received DropKeys request: Ranges[%d] Owner[%s]", len(request.Ranges), request.Owner)
+
+	match, err := keyMatch(request.Ranges, request.Hash, request.Placement, request.ReplicationFactor, request.Owner, true)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	deleted := s.HashTable.DropKeys(match, s.RInfo)
+	return &pb.StorageDropKeysResponse{
+		KeysDeleted: uint64(deleted),
+	}, nil
+}
+
+func (s *StorageServer) Drain(ctx context.Context, request *pb.StorageDrainRequest) (*pb.StorageDrainResponse, error) {
+	log.Printf("Received Drain request")
+
+	if stats := s.HashTable.Stats(); stats.NumKeys != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "Node still holds %d keys", stats.NumKeys)
+	}
+	if prepared := s.HashTable.PreparedTxns(); len(prepared) != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "Node still holds %d prepared transactions", len(prepared))
+	}
+
+	log.Printf("Node drained, shutting down")
+	if nil != s.Shutdown {
+		// GracefulStop waits for this call to return
+		go s.Shutdown()
+	}
+	return &pb.StorageDrainResponse{}, nil
+}
@@ -0,0 +1,193 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ownership is the placement pushed by the coordinator. A node without one accepts every key, a
+// node with a RingFile restores the last one pushed when it restarts.
+type ownership struct {
+	mtx       sync.RWMutex
+	nodeID    string
+	version   uint64
+	placement utils.Placement
+	previous  utils.Placement // Before the last change while its keys move away, nil otherwise
+	replicas  int             // Replication factor, the node accepts the keys it replicates
+
+	// The pushes placement and previous come from, saved to the RingFile
+	pushed *pb.StorageSetRingRequest
+	before *pb.StorageSetRingRequest
+}
+
+// savedRing is the content of the RingFile
+type savedRing struct {
+	Ring     *pb.StorageSetRingRequest `json:"ring"`
+	Previous *pb.StorageSetRingRequest `json:"previous,omitempty"`
+}
+
+// placementOf rebuilds the ring or the placement of a push
+func placementOf(request *pb.StorageSetRingRequest) (utils.Placement, error) {
+	switch {
+	case nil != request.Ring:
+		return utils.RingFromProto(request.Ring)
+	case nil != request.Placement:
+		return utils.PlacementFromProto(request.Placement)
+	}
+	return nil, fmt.Errorf("Neither a ring nor a placement")
+}
+
+func (s *StorageServer) SetRing(ctx context.Context, request *pb.StorageSetRingRequest) (*pb.StorageSetRingResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.NodeID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Node ID cannot be empty")
+	}
+
+	placement, err := placementOf(request)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	s.ownership.mtx.Lock()
+	defer s.ownership.mtx.Unlock()
+
+	// A push of the current version replaces the ring as well, a coordinator that restarted may
+	// push another ring at the version it starts over at
+	switch {
+	case request.RingVersion < s.ownership.version:
+		return &pb.StorageSetRingResponse{RingVersion: s.ownership.version}, nil
+	case request.RingVersion == s.ownership.version:
+		if !request.Moving && nil != s.ownership.previous {
+			s.ownership.previous, s.ownership.before = nil, nil
+			log.Printf("Keys of ring version %d moved", request.RingVersion)
+		}
+	default:
+		s.ownership.previous, s.ownership.before = nil, nil
+		if request.Moving {
+			s.ownership.previous, s.ownership.before = s.ownership.placement, s.ownership.pushed
+		}
+		log.Printf("Received ring version %d as Node[%s], moving : %v", request.RingVersion, request.NodeID, request.Moving)
+	}
+	s.ownership.nodeID, s.ownership.version, s.ownership.placement = request.NodeID, request.RingVersion, placement
+	s.ownership.replicas = max(int(request.ReplicationFactor), 1)
+	s.ownership.pushed = request
+
+	if err := s.saveRing(); err != nil {
+		log.Printf("Node[%v] ring not saved : %v", request.NodeID, err)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	return &pb.StorageSetRingResponse{RingVersion: s.ownership.version}, nil
+}
+
+// saveRing writes the pushes of the placement and of the previous one to the RingFile. Caller must
+// hold the lock.
+func (s *StorageServer) saveRing() error {
+	if s.RingFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(savedRing{Ring: s.ownership.pushed, Previous: s.ownership.before})
+	if err != nil {
+		return err
+	}
+	return utils.WriteSynced(s.RingFile, "ring file", data)
+}
+
+// LoadRing restores the placement saved to the RingFile, without one the node accepts every key
+// until the coordinator pushes a ring
+func (s *StorageServer) LoadRing() error {
+	if s.RingFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.RingFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading ring file : %w", err)
+	}
+	var saved savedRing
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("Error decoding ring file : %w", err)
+	}
+	if nil == saved.Ring {
+		return fmt.Errorf("Error decoding ring file : no ring")
+	}
+
+	placement, err := placementOf(saved.Ring)
+	if err != nil {
+		return err
+	}
+	var previous utils.Placement
+	if nil != saved.Previous {
+		if previous, err = placementOf(saved.Previous); err != nil {
+			return err
+		}
+	}
+
+	s.ownership.mtx.Lock()
+	defer s.ownership.mtx.Unlock()
+
+	s.ownership.nodeID, s.ownership.version, s.ownership.placement = saved.Ring.NodeID, saved.Ring.RingVersion, placement
+	s.ownership.replicas = max(int(saved.Ring.ReplicationFactor), 1)
+	s.ownership.previous, s.ownership.pushed, s.ownership.before = previous, saved.Ring, saved.Previous
+	log.Printf("Restored ring version %d as Node[%s]", s.ownership.version, s.ownership.nodeID)
+	return nil
+}
+
+// RingVersion returns the version of the ring pushed by the coordinator, 0 when none was
+func (s *StorageServer) RingVersion() uint64 {
+	s.ownership.mtx.RLock()
+	defer s.ownership.mtx.RUnlock()
+
+	return s.ownership.version
+}
+
+// replicates reports whether a placement makes the node one of the replicas of a key. Caller must
+// hold the lock.
+func (s *StorageServer) replicates(placement utils.Placement, key string) bool {
+	replicas, err := placement.GetNodes(key, s.ownership.replicas)
+	return err == nil && slices.Contains(replicas, s.ownership.nodeID)
+}
+
+// checkOwner fails with FailedPrecondition and a StorageWrongOwner detail when one of the keys is
+// owned by another node and not replicated on this one. Keys the node held before the last change
+// are accepted while they move.
+func (s *StorageServer) checkOwner(keys ...string) error {
+	s.ownership.mtx.RLock()
+	defer s.ownership.mtx.RUnlock()
+
+	if nil == s.ownership.placement {
+		return nil
+	}
+	for _, key := range keys {
+		owner, err := s.ownership.placement.GetNode(key)
+		if err != nil || owner == s.ownership.nodeID || s.replicates(s.ownership.placement, key) {
+			continue
+		}
+		if nil != s.ownership.previous && s.replicates(s.ownership.previous, key) {
+			continue
+		}
+
+		st := status.New(codes.FailedPrecondition, fmt.Sprintf("Key %s is owned by Node[%s] at ring version %d", key, owner, s.ownership.version))
+		detailed, err := st.WithDetails(&pb.StorageWrongOwner{RingVersion: s.ownership.version, Owner: owner})
+		if err != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+	return nil
+}
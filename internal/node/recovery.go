@@ -12,6 +12,7 @@ import (
 
 const WALFlushTimeSeconds = 1
 const CheckpointDurationMinutes = 1
+const ExpirySweepSeconds = 1
 
 func WriteToWAL(done <-chan struct{}, rInfo *utils.CheckpointInfo) {
 	if nil == rInfo {
@@ -23,7 +24,7 @@ func WriteToWAL(done <-chan struct{}, rInfo *utils.CheckpointInfo) {
 
 	file, err := os.OpenFile(rInfo.WALFile, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
-		log.Fatalf("Error opening WAL file : %w", err)
+		log.Fatalf("Error opening WAL file : %v", err)
 		return
 	}
 	defer file.Close()
@@ -43,6 +44,13 @@ func WriteToWAL(done <-chan struct{}, rInfo *utils.CheckpointInfo) {
 			writer.Write(append(data, '\n'))
 		case <-ticker.C:
 			writer.Flush()
+		case done := <-rInfo.SC:
+			// Records come unbuffered, so every record sent before the request is in the writer
+			err := writer.Flush()
+			if err == nil {
+				err = file.Sync()
+			}
+			done <- err
 		case <-rInfo.TC:
 			// Clear all the content of the file
 			err := file.Truncate(0) // Truncate the file to size 0
@@ -85,3 +93,23 @@ func Checkpoint(done <-chan struct{}, ht *utils.HashTable, rInfo *utils.Checkpoi
 		}
 	}
 }
+
+// ExpireKeys periodically removes keys whose TTL has passed
+func ExpireKeys(done <-chan struct{}, ht *utils.HashTable, rInfo *utils.CheckpointInfo) {
+	ticker := time.NewTicker(time.Duration(ExpirySweepSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if expired := ht.DeleteExpired(rInfo); expired > 0 {
+				log.Printf("Expired %d keys", expired)
+			}
+			if expired := ht.ExpireLeases(rInfo); expired > 0 {
+				log.Printf("Expired %d leases", expired)
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toReplicaEntry(storageEntry *pb.StorageEntry) (utils.ReplicaEntry, error) {
+	if storageEntry.Deleted {
+		return utils.ReplicaEntry{Entry: utils.Entry{Key: storageEntry.Key}, Timestamp: storageEntry.Timestamp, Deleted: true, Clock: storageEntry.Clock}, nil
+	}
+	entry, err := fromStorageEntry(storageEntry)
+	return utils.ReplicaEntry{Entry: entry, Timestamp: storageEntry.Timestamp}, err
+}
+
+func fromReplicaEntry(entry utils.ReplicaEntry) *pb.StorageEntry {
+	if entry.Deleted {
+		return &pb.StorageEntry{Key: entry.Key, Timestamp: entry.Timestamp, Deleted: true, Clock: entry.Clock}
+	}
+	storageEntry := toStorageEntry(entry.Entry)
+	storageEntry.Timestamp = entry.Timestamp
+	return storageEntry
+}
+
+// repairScope selects the keys a repair compares, the ones in ranges or when placement is set the
+// ones it replicates on every one of replicas
+type repairScope struct {
+	ranges            []*pb.StorageHashRange
+	hashName          string
+	placement         *pb.StoragePlacement
+	replicationFactor uint32
+	replicas          []string
+	match             func(key string) bool
+}
+
+func newRepairScope(ranges []*pb.StorageHashRange, hashName string, placement *pb.StoragePlacement, replicationFactor uint32, replicas []string) (*repairScope, error) {
+	scope := &repairScope{ranges: ranges, hashName: hashName, placement: placement, replicationFactor: replicationFactor, replicas: replicas}
+	if nil == placement {
+		return scope, nil
+	}
+	p, err := utils.PlacementFromProto(placement)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	n := max(int(replicationFactor), 1)
+	scope.match = func(key string) bool {
+		nodes, err := p.GetNodes(key, n)
+		if err != nil {
+			return false
+		}
+		for _, replica := range replicas {
+			if !slices.Contains(nodes, replica) {
+				return false
+			}
+		}
+		return true
+	}
+	return scope, nil
+}
+
+func (scope *repairScope) tree(ht *utils.HashTable, level int, indexes []uint32) ([]uint64, error) {
+	if nil != scope.match {
+		return ht.MerkleTreeMatching(scope.match, scope.hashName, level, indexes)
+	}
+	return ht.MerkleTree(toHashRanges(scope.ranges), scope.hashName, level, indexes)
+}
+
+func (scope *repairScope) export(ht *utils.HashTable, leaves []uint32) ([]utils.ReplicaEntry, error) {
+	if nil != scope.match {
+		return ht.ExportReplicaMatching(scope.match, scope.hashName, leaves)
+	}
+	return ht.ExportReplica(toHashRanges(scope.ranges), scope.hashName, leaves)
+}
+
+func (s *StorageServer) MerkleTree(ctx context.Context, request *pb.StorageMerkleTreeRequest) (*pb.StorageMerkleTreeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.Level > utils.MerkleDepth {
+		return nil, status.Errorf(codes.InvalidArgument, "Level %d is below the leaves at %d", request.Level, utils.MerkleDepth)
+	}
+
+	scope, err := newRepairScope(request.Ranges, request.Hash, request.Placement, request.ReplicationFactor, request.Replicas)
+	if err != nil {
+		return nil, err
+	}
+	digests, err := scope.tree(s.HashTable, int(request.Level), request.Indexes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return &pb.StorageMerkleTreeResponse{Digests: digests, Depth: utils.MerkleDepth}, nil
+}
+
+func (s *StorageServer) RepairExport(request *pb.StorageRepairExportRequest, stream pb.Storage_RepairExportServer) error {
+	if nil == request {
+		log.Println("Empty request received")
+		return fmt.Errorf("Empty request")
+	}
+
+	scope, err := newRepairScope(request.Ranges, request.Hash, request.Placement, request.ReplicationFactor, request.Replicas)
+	if err != nil {
+		return err
+	}
+	entries, err := scope.export(s.HashTable, request.Leaves)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	for _, entry := range entries {
+		if err := stream.Send(fromReplicaEntry(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StorageServer) RepairImport(stream pb.Storage_RepairImportServer) error {
+	response := &pb.StorageRepairImportResponse{}
+	for {
+		storageEntry, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		merged, err := s.mergeReplica(storageEntry)
+		if err != nil {
+			return err
+		}
+		if merged {
+			response.Merged++
+		} else {
+			response.Skipped++
+		}
+	}
+}
+
+// mergeReplica keeps a key of a replica when it is newer, keys locked by a prepared transaction
+// are left to the next repair
+func (s *StorageServer) mergeReplica(storageEntry *pb.StorageEntry) (bool, error) {
+	entry, err := toReplicaEntry(storageEntry)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	merged, err := s.HashTable.MergeReplica(entry, s.RInfo)
+	if errors.Is(err, utils.ErrTxnConflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, toStatus(err)
+	}
+	return merged, nil
+}
+
+func (s *StorageServer) Repair(ctx context.Context, request *pb.StorageRepairRequest) (*pb.StorageRepairResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.Peer == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Peer cannot be empty")
+	}
+	scope, err := newRepairScope(request.Ranges, request.Hash, request.Placement, request.ReplicationFactor, request.Replicas)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, request.Peer, grpc.WithInsecure())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer conn.Close()
+	peer := pb.NewStorageClient(conn)
+
+	leaves, err := s.diffLeaves(ctx, peer, scope)
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.StorageRepairResponse{Leaves: uint32(len(leaves))}
+	if len(leaves) == 0 {
+		return response, nil
+	}
+
+	// Keys of the peer first, the local keys sent back are then the newer of both
+	export, err := peer.RepairExport(ctx, &pb.StorageRepairExportRequest{
+		Ranges:            scope.ranges,
+		Hash:              scope.hashName,
+		Leaves:            leaves,
+		Placement:         scope.placement,
+		ReplicationFactor: scope.replicationFactor,
+		Replicas:          scope.replicas,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		storageEntry, err := export.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		merged, err := s.mergeReplica(storageEntry)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			response.Received++
+		}
+	}
+
+	entries, err := scope.export(s.HashTable, leaves)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	stream, err := peer.RepairImport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if err := stream.Send(fromReplicaEntry(entry)); err != nil {
+			return nil, err
+		}
+	}
+	imported, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	response.Sent = imported.Merged
+
+	log.Printf("Repaired %d leaves with %s : received %d keys, sent %d", response.Leaves, request.Peer, response.Received, response.Sent)
+	return response, nil
+}
+
+// diffLeaves walks down the Merkle trees of the node and of a peer from the root, only the
+// children of differing nodes are compared. Returns the leaves that differ.
+func (s *StorageServer) diffLeaves(ctx context.Context, peer pb.StorageClient, scope *repairScope) ([]uint32, error) {
+	differing := []uint32{0}
+	for level := 0; level <= utils.MerkleDepth && len(differing) > 0; level++ {
+		indexes := differing
+		if level > 0 {
+			indexes = make([]uint32, 0, 2*len(differing))
+			for _, index := range differing {
+				indexes = append(indexes, 2*index, 2*index+1)
+			}
+		}
+
+		local, err := scope.tree(s.HashTable, level, indexes)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		remote, err := peer.MerkleTree(ctx, &pb.StorageMerkleTreeRequest{
+			Ranges:            scope.ranges,
+			Hash:              scope.hashName,
+			Level:             uint32(level),
+			Indexes:           indexes,
+			Placement:         scope.placement,
+			ReplicationFactor: scope.replicationFactor,
+			Replicas:          scope.replicas,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if remote.Depth != utils.MerkleDepth || len(remote.Digests) != len(indexes) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Peer tree of depth %d does not match", remote.Depth)
+		}
+
+		differing = []uint32{}
+		for i, index := range indexes {
+			if local[i] != remote.Digests[i] {
+				differing = append(differing, index)
+			}
+		}
+	}
+	return differing, nil
+}
@@ -0,0 +1,82 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toStorageSibling(sibling utils.Sibling) *pb.StorageSibling {
+	return &pb.StorageSibling{Value: sibling.Value, Context: sibling.Context, DotNode: sibling.Dot.Node, DotCounter: sibling.Dot.Counter}
+}
+
+func fromStorageSibling(sibling *pb.StorageSibling) utils.Sibling {
+	return utils.Sibling{Value: sibling.Value, Context: sibling.Context, Dot: utils.Dot{Node: sibling.DotNode, Counter: sibling.DotCounter}}
+}
+
+func (s *StorageServer) GetSiblings(ctx context.Context, request *pb.StorageGetSiblingsRequest) (*pb.StorageGetSiblingsResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received GetSiblings request: key[%s]", request.Key)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	siblings, isFound, err := s.HashTable.GetSiblings(request.Key)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &pb.StorageGetSiblingsResponse{Found: isFound}
+	for _, sibling := range siblings {
+		response.Siblings = append(response.Siblings, toStorageSibling(sibling))
+	}
+	return response, nil
+}
+
+func (s *StorageServer) PutSibling(ctx context.Context, request *pb.StoragePutSiblingRequest) (*pb.StoragePutSiblingResponse, error) {
+	if nil == request.Value {
+		return nil, status.Errorf(codes.InvalidArgument, "Value cannot be empty")
+	}
+	if s.NodeID == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "A node without an ID cannot coordinate versioned writes")
+	}
+
+	log.Printf("Received PutSibling request: Key[%s]/Value[%v]/Context[%v]", request.Key, request.Value, request.Context)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	sibling, err := s.HashTable.PutSibling(request.Key, request.Value, request.Context, s.NodeID, request.Timestamp, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.StoragePutSiblingResponse{Sibling: toStorageSibling(sibling)}, nil
+}
+
+func (s *StorageServer) MergeSibling(ctx context.Context, request *pb.StorageMergeSiblingRequest) (*pb.StorageMergeSiblingResponse, error) {
+	if nil == request.Sibling {
+		return nil, status.Errorf(codes.InvalidArgument, "Sibling cannot be empty")
+	}
+
+	log.Printf("Received MergeSibling request: Key[%s]/Dot[%s:%d]", request.Key, request.Sibling.DotNode, request.Sibling.DotCounter)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	merged, err := s.HashTable.MergeSibling(request.Key, fromStorageSibling(request.Sibling), request.Timestamp, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.StorageMergeSiblingResponse{Stale: !merged}, nil
+}
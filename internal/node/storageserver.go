@@ -2,10 +2,13 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"sync"
+	"time"
 
 	pb "github.com/b1acktothefuture/dht-system/gen"
 	"github.com/b1acktothefuture/dht-system/internal/utils"
@@ -16,23 +19,85 @@ import (
 
 type StorageServer struct {
 	pb.UnimplementedStorageServer
+	NodeID    string
 	HashTable *utils.HashTable
 	RInfo     *utils.CheckpointInfo
+
+	// Stops serving once the node is drained, nil keeps it running
+	Shutdown func()
+
+	// SWIM membership of the node, nil when gossip is disabled
+	Gossip *Gossip
+
+	// File the ring pushed by the coordinator is saved to, empty keeps it in memory only
+	RingFile string
+
+	// Keys the node accepts, pushed by the coordinator
+	ownership ownership
 }
 
-func NewStorageServer(config *Config) *StorageServer {
-	storageServer := &StorageServer{}
+func NewStorageServer(config *Config) (*StorageServer, error) {
+	storageServer := &StorageServer{NodeID: config.NodeID}
 	storageServer.HashTable = utils.NewHashTable(config.HashTable.NumBuckets)
 
+	policy, err := utils.ParseEvictionPolicy(config.HashTable.EvictionPolicy)
+	if err != nil {
+		return nil, err
+	}
+	storageServer.HashTable.SetMemoryLimit(config.HashTable.MaxMemoryBytes, policy)
+
+	// Indexes are declared before recovery so the replay rebuilds them
+	for _, index := range config.Indexes {
+		if err := storageServer.HashTable.AddIndex(index.Name, index.Path); err != nil {
+			return nil, err
+		}
+	}
+
 	if config.Checkpoint.Enabled {
 		storageServer.RInfo = &utils.CheckpointInfo{
 			WC:             make(chan utils.WALRecord),
 			WALFile:        config.Checkpoint.WALFile,
 			TC:             make(chan struct{}),
+			SC:             make(chan chan error),
 			CheckPointFile: config.Checkpoint.CheckpointFile,
 		}
 	}
-	return storageServer
+
+	// A restarted node rejects the keys it does not own before the coordinator pushes the ring again
+	storageServer.RingFile = config.Ownership.RingFile
+	if err := storageServer.LoadRing(); err != nil {
+		return nil, err
+	}
+	return storageServer, nil
+}
+
+// toStatus maps hash table errors to gRPC status errors
+func toStatus(err error) error {
+	if errors.Is(err, utils.ErrOutOfMemory) {
+		return status.Errorf(codes.ResourceExhausted, "%v", err)
+	}
+	if errors.Is(err, utils.ErrUnknownIndex) {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	if errors.Is(err, utils.ErrWrongType) {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if errors.Is(err, utils.ErrNotInteger) {
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	if errors.Is(err, utils.ErrOverflow) {
+		return status.Errorf(codes.OutOfRange, "%v", err)
+	}
+	if errors.Is(err, utils.ErrLeaseNotFound) {
+		return status.Errorf(codes.NotFound, "%v", err)
+	}
+	if errors.Is(err, utils.ErrKeyExists) {
+		return status.Errorf(codes.AlreadyExists, "%v", err)
+	}
+	if errors.Is(err, utils.ErrTxnConflict) {
+		return status.Errorf(codes.Aborted, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
 }
 
 func (s *StorageServer) Get(ctx context.Context, request *pb.StorageGetRequest) (*pb.StorageGetResponse, error) {
@@ -43,17 +108,28 @@ func (s *StorageServer) Get(ctx context.Context, request *pb.StorageGetRequest)
 
 	log.Printf("Received Get request: key[%s]", request.Key)
 
-	value, isFound := s.HashTable.Get(request.Key)
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	entry, isFound, err := s.HashTable.GetReplica(request.Key)
+	if err != nil {
+		return nil, toStatus(err)
+	}
 	if false == isFound {
 		return &pb.StorageGetResponse{
-			Found: false,
-			Value: nil,
+			Found:     false,
+			Value:     nil,
+			Timestamp: entry.Timestamp,
+			Deleted:   entry.Deleted,
 		}, nil
 	}
 
 	return &pb.StorageGetResponse{
-		Found: true,
-		Value: value,
+		Found:     true,
+		Value:     entry.Value,
+		Timestamp: entry.Timestamp,
+		ExpiresAt: entry.ExpiresAt,
 	}, nil
 }
 
@@ -64,7 +140,38 @@ func (s *StorageServer) Put(ctx context.Context, request *pb.StoragePutRequest)
 
 	log.Printf("Received Put request: Key[%s]/Value[%v]", request.Key, request.Value)
 
-	isPresent := s.HashTable.Put(request.Key, request.Value, s.RInfo)
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	var expiresAt int64
+	if nil != request.TTLSeconds {
+		if request.GetTTLSeconds() <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "TTL must be positive")
+		}
+		expiresAt = time.Now().Add(time.Duration(request.GetTTLSeconds()) * time.Second).UnixNano()
+	}
+
+	// Replicated writes expire the same time on every replica, a hint replayed later included
+	if request.Timestamp != 0 {
+		if request.Lease != 0 || request.IfAbsent {
+			return nil, status.Errorf(codes.InvalidArgument, "A replicated write takes no lease or condition")
+		}
+		if nil != request.TTLSeconds {
+			expiresAt = request.Timestamp + request.GetTTLSeconds()*int64(time.Second)
+		}
+		applied, err := s.HashTable.PutAt(request.Key, request.Value, expiresAt, request.Timestamp, s.RInfo)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return &pb.StoragePutResponse{Stale: !applied}, nil
+	}
+
+	options := utils.PutOptions{ExpiresAt: expiresAt, Lease: request.Lease, IfAbsent: request.IfAbsent}
+	isPresent, err := s.HashTable.PutWithOptions(request.Key, request.Value, options, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
 
 	return &pb.StoragePutResponse{
 		IsUpdated: isPresent,
@@ -79,7 +186,14 @@ func (s *StorageServer) Update(ctx context.Context, request *pb.StorageUpdateReq
 
 	log.Printf("Received Update request: Key[%s]/Value[%v]", request.Key, request.Value)
 
-	isPresent := s.HashTable.Update(request.Key, request.Value, s.RInfo)
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	isPresent, err := s.HashTable.UpdateWithLimit(request.Key, request.Value, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
 
 	return &pb.StorageUpdateResponse{
 		IsKeyPresent: isPresent,
@@ -94,16 +208,144 @@ func (s *StorageServer) Delete(ctx context.Context, request *pb.StorageDeleteReq
 
 	log.Printf("Received Delete request: Key[%s]", request.Key)
 
-	isPresent := s.HashTable.Delete(request.Key, s.RInfo)
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	if request.Timestamp != 0 {
+		if nil != request.Lease {
+			return nil, status.Errorf(codes.InvalidArgument, "A replicated delete takes no lease")
+		}
+		isPresent, applied, err := s.HashTable.DeleteAt(request.Key, request.Timestamp, s.RInfo)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return &pb.StorageDeleteResponse{IsKeyPresent: isPresent && applied, Stale: !applied}, nil
+	}
+
+	var isPresent bool
+	var err error
+	if nil != request.Lease {
+		isPresent, err = s.HashTable.DeleteOwned(request.Key, request.GetLease(), s.RInfo)
+	} else {
+		isPresent, err = s.HashTable.TryDelete(request.Key, s.RInfo)
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
 	return &pb.StorageDeleteResponse{
 		IsKeyPresent: isPresent,
 	}, nil
 }
 
+func (s *StorageServer) Stats(ctx context.Context, request *pb.StorageStatsRequest) (*pb.StorageStatsResponse, error) {
+	stats := s.HashTable.Stats()
+	return &pb.StorageStatsResponse{
+		NumKeys:         uint64(stats.NumKeys),
+		UsedMemoryBytes: stats.UsedMemory,
+		MaxMemoryBytes:  stats.MaxMemory,
+		EvictionPolicy:  stats.EvictionPolicy.String(),
+		Evictions:       stats.Evictions,
+		Expirations:     stats.Expirations,
+	}, nil
+}
+
+func (s *StorageServer) Range(ctx context.Context, request *pb.StorageRangeRequest) (*pb.StorageRangeResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received Range request: Start[%s]/End[%s]/Limit[%d]/Reverse[%v]", request.Start, request.End, request.Limit, request.Reverse)
+
+	entries := s.HashTable.Range(request.Start, request.End, int(request.Limit), request.Reverse)
+
+	response := &pb.StorageRangeResponse{
+		Entries: make([]*pb.KeyValue, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		stamp, _ := s.HashTable.Stamp(entry.Key)
+		response.Entries = append(response.Entries, &pb.KeyValue{Key: entry.Key, Value: entry.Value, Timestamp: stamp.Timestamp})
+	}
+	return response, nil
+}
+
+func (s *StorageServer) QueryIndex(ctx context.Context, request *pb.StorageQueryIndexRequest) (*pb.StorageQueryIndexResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received QueryIndex request: Index[%s]/Value[%s]/Limit[%d]", request.IndexName, request.Value, request.Limit)
+
+	entries, err := s.HashTable.QueryIndex(request.IndexName, request.Value, int(request.Limit))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &pb.StorageQueryIndexResponse{
+		Entries: make([]*pb.KeyValue, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		stamp, _ := s.HashTable.Stamp(entry.Key)
+		response.Entries = append(response.Entries, &pb.KeyValue{Key: entry.Key, Value: entry.Value, Timestamp: stamp.Timestamp})
+	}
+	return response, nil
+}
+
+func (s *StorageServer) Increment(ctx context.Context, request *pb.StorageIncrementRequest) (*pb.StorageIncrementResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received Increment request: Key[%s]/Delta[%d]", request.Key, request.Delta)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	value, err := s.HashTable.Increment(request.Key, request.Delta, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageIncrementResponse{
+		Value: value,
+	}, nil
+}
+
+func (s *StorageServer) Decrement(ctx context.Context, request *pb.StorageDecrementRequest) (*pb.StorageDecrementResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received Decrement request: Key[%s]/Delta[%d]", request.Key, request.Delta)
+
+	if err := s.checkOwner(request.Key); err != nil {
+		return nil, err
+	}
+
+	if request.Delta == math.MinInt64 {
+		return nil, toStatus(utils.ErrOverflow)
+	}
+
+	value, err := s.HashTable.Increment(request.Key, -request.Delta, s.RInfo)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.StorageDecrementResponse{
+		Value: value,
+	}, nil
+}
+
 func ServeStorage(wg *sync.WaitGroup, config *Config) {
 	defer wg.Done()
 	walDoneChan := make(chan struct{})
 	checkPointDoneChan := make(chan struct{})
+	expiryDoneChan := make(chan struct{})
 
 	if nil == config {
 		log.Printf("Nil config receieved")
@@ -112,12 +354,16 @@ func ServeStorage(wg *sync.WaitGroup, config *Config) {
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", config.Network.Port))
 	if err != nil {
-		log.Printf("Error starting up the server on port[%d] : %w", config.Network.Port, err)
+		log.Printf("Error starting up the server on port[%d] : %v", config.Network.Port, err)
 		return
 	}
 
 	grpcServer := grpc.NewServer()
-	storageServer := NewStorageServer(config)
+	storageServer, err := NewStorageServer(config)
+	if err != nil {
+		log.Printf("Error creating storage server : %v", err)
+		return
+	}
 
 	// TODO: Restore from file
 	utils.CheckpointRestore(storageServer.HashTable, config.Recover.CheckpointFile, config.Recover.WALFile)
@@ -125,14 +371,26 @@ func ServeStorage(wg *sync.WaitGroup, config *Config) {
 	// New thread for WAL and checkpoint, non blocking
 	go WriteToWAL(walDoneChan, storageServer.RInfo)
 	go Checkpoint(checkPointDoneChan, storageServer.HashTable, storageServer.RInfo)
+	go ExpireKeys(expiryDoneChan, storageServer.HashTable, storageServer.RInfo)
 
+	storageServer.Shutdown = grpcServer.GracefulStop
 	pb.RegisterStorageServer(grpcServer, storageServer)
 
+	if config.Gossip.Enabled {
+		storageServer.Gossip = NewGossip(config)
+		storageServer.Gossip.Start()
+		defer func() {
+			storageServer.Gossip.Leave(context.Background())
+			storageServer.Gossip.Stop()
+		}()
+	}
+
 	if err := grpcServer.Serve(listener); err != nil {
-		log.Printf("Failed to serve: %w", err)
+		log.Printf("Failed to serve: %v", err)
 		return
 	}
 
+	expiryDoneChan <- struct{}{}
 	if nil != storageServer.RInfo {
 		walDoneChan <- struct{}{}
 		checkPointDoneChan <- struct{}{}
@@ -0,0 +1,153 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toCompares(compares []*pb.TxnCompare) []utils.Compare {
+	converted := make([]utils.Compare, 0, len(compares))
+	for _, compare := range compares {
+		converted = append(converted, utils.Compare{
+			Key:     compare.Key,
+			Target:  compare.Target,
+			Result:  compare.Result,
+			Version: compare.Version,
+			Value:   compare.Value,
+		})
+	}
+	return converted
+}
+
+func toTxnOps(ops []*pb.TxnOp) []utils.TxnOp {
+	converted := make([]utils.TxnOp, 0, len(ops))
+	for _, op := range ops {
+		converted = append(converted, utils.TxnOp{Type: op.Type, Key: op.Key, Value: op.Value})
+	}
+	return converted
+}
+
+// txnKeys returns the keys a transaction reads or writes
+func txnKeys(compares []*pb.TxnCompare, then, otherwise []*pb.TxnOp) []string {
+	keys := make([]string, 0, len(compares)+len(then)+len(otherwise))
+	for _, compare := range compares {
+		keys = append(keys, compare.Key)
+	}
+	for _, op := range then {
+		keys = append(keys, op.Key)
+	}
+	for _, op := range otherwise {
+		keys = append(keys, op.Key)
+	}
+	return keys
+}
+
+func toTxnOpResults(results []utils.TxnOpResult) []*pb.TxnOpResult {
+	converted := make([]*pb.TxnOpResult, 0, len(results))
+	for _, result := range results {
+		opResult := &pb.TxnOpResult{Key: result.Key, Found: result.Found, Version: result.Version}
+		if nil != result.Value {
+			opResult.Value = result.Value
+		}
+		converted = append(converted, opResult)
+	}
+	return converted
+}
+
+func (s *StorageServer) TxnPrepare(ctx context.Context, request *pb.StorageTxnPrepareRequest) (*pb.StorageTxnPrepareResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+	if request.TxnID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Transaction ID cannot be empty")
+	}
+
+	log.Printf("Received TxnPrepare request: Txn[%s]/Compares[%d]/Then[%d]/Else[%d]", request.TxnID, len(request.Compares), len(request.Then), len(request.Else))
+
+	if err := s.checkOwner(txnKeys(request.Compares, request.Then, request.Else)...); err != nil {
+		return nil, err
+	}
+
+	succeeded, err := s.HashTable.PrepareTxn(request.TxnID, toCompares(request.Compares), toTxnOps(request.Then), toTxnOps(request.Else), s.RInfo)
+	if err != nil {
+		if errors.Is(err, utils.ErrTxnConflict) || errors.Is(err, utils.ErrOutOfMemory) {
+			return nil, toStatus(err)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// The vote only counts once the prepare survives a crash, a later commit relies on it
+	if err := s.RInfo.Sync(); err != nil {
+		s.HashTable.AbortTxn(request.TxnID, s.RInfo)
+		return nil, status.Errorf(codes.Unavailable, "Error syncing WAL : %v", err)
+	}
+
+	return &pb.StorageTxnPrepareResponse{
+		Succeeded: succeeded,
+	}, nil
+}
+
+func (s *StorageServer) TxnCommit(ctx context.Context, request *pb.StorageTxnCommitRequest) (*pb.StorageTxnCommitResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received TxnCommit request: Txn[%s]/Succeeded[%v]", request.TxnID, request.Succeeded)
+
+	results, err := s.HashTable.CommitTxn(request.TxnID, request.Succeeded, s.RInfo)
+	if err != nil {
+		// The transaction is committed, the failed write is only reported
+		log.Printf("Txn[%s] write failed on commit : %v", request.TxnID, err)
+	}
+
+	return &pb.StorageTxnCommitResponse{
+		Results: toTxnOpResults(results),
+	}, nil
+}
+
+func (s *StorageServer) TxnAbort(ctx context.Context, request *pb.StorageTxnAbortRequest) (*pb.StorageTxnAbortResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received TxnAbort request: Txn[%s]", request.TxnID)
+
+	s.HashTable.AbortTxn(request.TxnID, s.RInfo)
+	return &pb.StorageTxnAbortResponse{}, nil
+}
+
+func (s *StorageServer) Txn(ctx context.Context, request *pb.StorageTxnRequest) (*pb.StorageTxnResponse, error) {
+	if nil == request {
+		log.Println("Empty request received")
+		return nil, fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received Txn request: Compares[%d]/Then[%d]/Else[%d]", len(request.Compares), len(request.Then), len(request.Else))
+
+	if err := s.checkOwner(txnKeys(request.Compares, request.Then, request.Else)...); err != nil {
+		return nil, err
+	}
+
+	succeeded, results, err := s.HashTable.Txn(toCompares(request.Compares), toTxnOps(request.Then), toTxnOps(request.Else), s.RInfo)
+	if err != nil {
+		if errors.Is(err, utils.ErrTxnConflict) || errors.Is(err, utils.ErrOutOfMemory) {
+			return nil, toStatus(err)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	return &pb.StorageTxnResponse{
+		Succeeded: succeeded,
+		Results:   toTxnOpResults(results),
+	}, nil
+}
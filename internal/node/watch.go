@@ -0,0 +1,142 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toWatchEvent(record utils.WALRecord) *pb.StorageWatchEvent {
+	event := &pb.StorageWatchEvent{
+		LSN:       record.LSN,
+		Operation: record.Operation,
+		Key:       record.Key,
+		Type:      record.Type,
+	}
+	if nil != record.Value {
+		event.Value = record.Value
+	}
+	return event
+}
+
+func (s *StorageServer) toChangeEvent(record utils.WALRecord) *pb.StorageChangeEvent {
+	event := &pb.StorageChangeEvent{
+		NodeID:    s.NodeID,
+		LSN:       record.LSN,
+		Operation: record.Operation,
+		Key:       record.Key,
+		ExpiresAt: record.ExpiresAt,
+		Type:      record.Type,
+		Items:     record.Items,
+		Fields:    record.Fields,
+		Count:     uint32(record.Count),
+	}
+	if nil != record.Value {
+		event.Value = record.Value
+	}
+	if record.Operation == "TXN" {
+		for _, change := range record.Changes() {
+			event.Group = append(event.Group, s.toChangeEvent(change))
+		}
+	}
+	return event
+}
+
+// streamRecords sends the records of the matching keys from startLSN, first from the WAL, then the ones
+// retained in memory and then live ones until ctx is done
+func (s *StorageServer) streamRecords(ctx context.Context, key string, prefix bool, startLSN uint64, send func(record utils.WALRecord) error) error {
+	subscription, backlog, retainedFrom := s.HashTable.Watch(key, prefix, startLSN)
+	defer subscription.Close()
+
+	// Older changes than the ones in memory come from the WAL
+	if startLSN != 0 && startLSN < retainedFrom {
+		if nil == s.RInfo {
+			return status.Errorf(codes.OutOfRange, "LSN %d is no longer retained, WAL is disabled", startLSN)
+		}
+		// Records below retainedFrom were handed to the WAL writer before the watch, flush them to the file,
+		// a gap left after that is compacted history and fails the stream
+		if err := s.RInfo.Sync(); err != nil {
+			return status.Errorf(codes.Unavailable, "Error syncing WAL : %v", err)
+		}
+		history, err := utils.ReadWAL(s.RInfo.WALFile, startLSN, retainedFrom)
+		if err != nil {
+			return status.Errorf(codes.OutOfRange, "%v", err)
+		}
+		for _, record := range history {
+			if !subscription.Accepts(record) {
+				continue
+			}
+			if err := send(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, record := range backlog {
+		if err := send(record); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case record, ok := <-subscription.C:
+			if !ok {
+				if err := subscription.Err(); err != nil {
+					return status.Errorf(codes.ResourceExhausted, "%v", err)
+				}
+				return nil
+			}
+			if record.LSN < startLSN {
+				continue
+			}
+			if err := send(record); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *StorageServer) Watch(request *pb.StorageWatchRequest, stream pb.Storage_WatchServer) error {
+	if nil == request {
+		log.Println("Empty request received")
+		return fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received Watch request: Key[%s]/Prefix[%v]/StartLSN[%d]", request.Key, request.Prefix, request.StartLSN)
+
+	// Writes of a transaction are sent one by one, only the watched ones
+	watched := &utils.Subscription{Key: request.Key, Prefix: request.Prefix}
+	return s.streamRecords(stream.Context(), request.Key, request.Prefix, request.StartLSN, func(record utils.WALRecord) error {
+		for _, change := range record.Changes() {
+			if !watched.Matches(change.Key) {
+				continue
+			}
+			if err := stream.Send(toWatchEvent(change)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *StorageServer) StreamChanges(request *pb.StorageStreamChangesRequest, stream pb.Storage_StreamChangesServer) error {
+	if nil == request {
+		log.Println("Empty request received")
+		return fmt.Errorf("Empty request")
+	}
+
+	log.Printf("Received StreamChanges request: StartLSN[%d]", request.StartLSN)
+
+	// An empty prefix matches every key
+	return s.streamRecords(stream.Context(), "", true, request.StartLSN, func(record utils.WALRecord) error {
+		return stream.Send(s.toChangeEvent(record))
+	})
+}
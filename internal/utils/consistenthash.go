@@ -3,111 +3,571 @@ package utils
 import (
 	"errors"
 	"hash/crc32"
+	"math"
+	"slices"
 	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
-// ConsistentHash represents a consistent hashing ring with virtual nodes.
+// ConsistentHash represents a consistent hashing ring with virtual nodes. It is safe for concurrent
+// use: changes are serialized and each publishes a new immutable snapshot of the ring, lookups read
+// the current snapshot without locking.
 type ConsistentHash struct {
-	VirtualNodes   int               // Number of virtual nodes for each physical node.
+	VirtualNodes int // Number of virtual nodes for each physical node of weight 1, fixed at creation.
+
+	mtx  sync.Mutex // Serializes the changes.
+	ring atomic.Pointer[RingSnapshot]
+}
+
+// RingSnapshot is the ring at a version, it never changes once published.
+type RingSnapshot struct {
+	version        uint64            // Incremented by every change of the ring.
+	virtualNodes   int               // Number of virtual nodes for each physical node of weight 1.
 	hashSortedKeys []uint32          // Sorted keys for efficient lookup.
 	hashRing       map[uint32]string // Mapping of hash keys to node names.
-	nodes          map[string]bool   // Set of physical nodes.
+	nodes          map[string]int    // Weight of each physical node.
+	owners         []string          // Node owning the arc ending at each sorted key.
+	hash           HashFunc
+	hashName       string
+	loadFactor     float64 // Bound of the share of a node over its fair share, 0 when unbounded.
 }
 
 // NewConsistentHash creates a new consistent hash instance with the specified number of virtual nodes.
 func NewConsistentHash(virtualNodes int) *ConsistentHash {
-	return &ConsistentHash{
-		VirtualNodes:   virtualNodes,
+	return newConsistentHash(virtualNodes, HashCRC32, crc32Hash, 0)
+}
+
+// NewConsistentHashWithHash creates a ring placing keys and virtual nodes with the named hash function.
+func NewConsistentHashWithHash(virtualNodes int, hashName string) (*ConsistentHash, error) {
+	hash, hashName, err := resolveHash(hashName)
+	if err != nil {
+		return nil, err
+	}
+	return newConsistentHash(virtualNodes, hashName, hash, 0), nil
+}
+
+// NewBoundedConsistentHash creates a ring with bounded loads: no node owns more than loadFactor
+// times its weighted share of the ring, the arcs past the bound go to the next node clockwise
+// with room left. loadFactor must be at least 1.
+func NewBoundedConsistentHash(virtualNodes int, hashName string, loadFactor float64) (*ConsistentHash, error) {
+	if loadFactor < 1 {
+		return nil, errors.New("load factor must be at least 1")
+	}
+	hash, hashName, err := resolveHash(hashName)
+	if err != nil {
+		return nil, err
+	}
+	return newConsistentHash(virtualNodes, hashName, hash, loadFactor), nil
+}
+
+func newConsistentHash(virtualNodes int, hashName string, hash HashFunc, loadFactor float64) *ConsistentHash {
+	ch := &ConsistentHash{VirtualNodes: virtualNodes}
+	ch.ring.Store(&RingSnapshot{
+		virtualNodes:   virtualNodes,
 		hashRing:       make(map[uint32]string),
-		nodes:          make(map[string]bool),
+		nodes:          make(map[string]int),
 		hashSortedKeys: make([]uint32, 0),
-	}
+		hash:           hash,
+		hashName:       hashName,
+		loadFactor:     loadFactor,
+	})
+	return ch
+}
+
+// Snapshot returns the current ring, it is not affected by later changes.
+func (ch *ConsistentHash) Snapshot() *RingSnapshot {
+	return ch.ring.Load()
+}
+
+// Version returns the version of the current ring.
+func (ch *ConsistentHash) Version() uint64 {
+	return ch.Snapshot().version
 }
 
 // GetNode returns the closest node for the given object in the consistent hash ring.
 func (ch *ConsistentHash) GetNode(obj string) (string, error) {
-	if len(ch.nodes) == 0 {
-		return "", errors.New("consistent hash ring is empty")
-	}
+	return ch.Snapshot().GetNode(obj)
+}
+
+// GetNodes returns the owner of a key followed by the next distinct nodes clockwise, up to n.
+func (ch *ConsistentHash) GetNodes(key string, n int) ([]string, error) {
+	return ch.Snapshot().GetNodes(key, n)
+}
 
-	key := ch.hashKey(obj)
-	index := ch.searchNearestKeyIndex(key)
-	return ch.hashRing[ch.hashSortedKeys[index]], nil
+// Position returns the position of a key on the ring, the ranges of RangeMove are made of positions.
+func (ch *ConsistentHash) Position(key string) uint32 {
+	return ch.Snapshot().Position(key)
+}
+
+// change applies a change to a copy of the current ring and publishes it, the change returns
+// false when it left the ring as it was.
+func (ch *ConsistentHash) change(apply func(next *RingSnapshot) bool) {
+	ch.mtx.Lock()
+	defer ch.mtx.Unlock()
+
+	next := ch.Snapshot().copy()
+	if !apply(next) {
+		return
+	}
+	next.updateSortedKeys()
+	next.version++
+	ch.ring.Store(next)
 }
 
 // AddNode adds a node and its virtual nodes to the consistent hash ring.
 func (ch *ConsistentHash) AddNode(node string) {
-	if ch.nodes[node] {
-		return // Node already exists
+	ch.AddWeightedNode(node, 1)
+}
+
+// AddWeightedNode adds a node with weight times the virtual nodes of a node of weight 1, it gets
+// a share of the keys proportional to its weight. A weight below 1 counts as 1.
+func (ch *ConsistentHash) AddWeightedNode(node string, weight int) {
+	ch.change(func(next *RingSnapshot) bool {
+		if _, ok := next.nodes[node]; ok {
+			return false // Node already exists
+		}
+		weight = max(weight, 1)
+
+		next.nodes[node] = weight
+		next.addVirtualNodes(node, 0, next.virtualNodeCount(weight))
+		return true
+	})
+}
+
+// RemoveNode removes a node and its virtual nodes from the consistent hash ring.
+func (ch *ConsistentHash) RemoveNode(node string) {
+	ch.change(func(next *RingSnapshot) bool {
+		weight, ok := next.nodes[node]
+		if !ok {
+			return false // Node does not exist
+		}
+
+		delete(next.nodes, node)
+		next.removeVirtualNodes(node, 0, next.virtualNodeCount(weight))
+		return true
+	})
+}
+
+// SetWeight changes the weight of a node. Only the virtual nodes above the smaller weight are
+// added or removed, so only their ranges change owner.
+func (ch *ConsistentHash) SetWeight(node string, weight int) {
+	ch.change(func(next *RingSnapshot) bool {
+		current, ok := next.nodes[node]
+		weight = max(weight, 1)
+		if !ok || current == weight {
+			return false // Node does not exist or keeps its weight
+		}
+
+		next.nodes[node] = weight
+		if weight > current {
+			next.addVirtualNodes(node, next.virtualNodeCount(current), next.virtualNodeCount(weight))
+		} else {
+			next.removeVirtualNodes(node, next.virtualNodeCount(weight), next.virtualNodeCount(current))
+		}
+		return true
+	})
+}
+
+// Weight returns the weight of a node, 0 when it is not in the ring.
+func (ch *ConsistentHash) Weight(node string) int {
+	return ch.Snapshot().Weight(node)
+}
+
+// ListNodes returns a list of all physical nodes in the consistent hash ring.
+func (ch *ConsistentHash) ListNodes() []string {
+	return ch.Snapshot().ListNodes()
+}
+
+// Clone returns a copy of the ring that is not affected by later changes
+func (ch *ConsistentHash) Clone() *ConsistentHash {
+	clone := &ConsistentHash{VirtualNodes: ch.VirtualNodes}
+	// Snapshots never change, the copy shares the current one
+	clone.ring.Store(ch.Snapshot())
+	return clone
+}
+
+// copy returns a copy of the snapshot to apply a change to.
+func (s *RingSnapshot) copy() *RingSnapshot {
+	next := *s
+	next.hashRing = make(map[uint32]string, len(s.hashRing))
+	for key, node := range s.hashRing {
+		next.hashRing[key] = node
+	}
+	next.nodes = make(map[string]int, len(s.nodes))
+	for node, weight := range s.nodes {
+		next.nodes[node] = weight
 	}
+	return &next
+}
 
-	ch.nodes[node] = true
+// Version returns the version of the ring.
+func (s *RingSnapshot) Version() uint64 {
+	return s.version
+}
 
-	// Add physical and virtual nodes
-	for i := 0; i <= ch.VirtualNodes; i++ {
-		virtualKey := ch.virtualNodeKey(i, node)
-		ch.hashRing[virtualKey] = node
+// GetNode returns the closest node for the given object in the ring.
+func (s *RingSnapshot) GetNode(obj string) (string, error) {
+	if len(s.nodes) == 0 {
+		return "", errors.New("consistent hash ring is empty")
 	}
 
-	ch.updateSortedKeys()
+	key := s.hashKey(obj)
+	index := s.searchNearestKeyIndex(key)
+	return s.owners[index], nil
 }
 
-// RemoveNode removes a node and its virtual nodes from the consistent hash ring.
-func (ch *ConsistentHash) RemoveNode(node string) {
-	if !ch.nodes[node] {
-		return // Node does not exist
+// GetNodes returns the owner of a key followed by the next distinct nodes clockwise, up to n.
+func (s *RingSnapshot) GetNodes(key string, n int) ([]string, error) {
+	if len(s.nodes) == 0 {
+		return nil, errors.New("consistent hash ring is empty")
 	}
 
-	delete(ch.nodes, node)
+	return s.replicasAt(s.searchNearestKeyIndex(s.hashKey(key)), n), nil
+}
 
-	// Remove physical and virtual nodes
-	for i := 0; i <= ch.VirtualNodes; i++ {
-		virtualKey := ch.virtualNodeKey(i, node)
-		delete(ch.hashRing, virtualKey)
+// replicasAt returns the owner of the arc ending at a sorted key followed by the next distinct
+// nodes clockwise, up to n
+func (s *RingSnapshot) replicasAt(start int, n int) []string {
+	n = min(n, len(s.nodes))
+	nodes := make([]string, 0, n)
+	for i := 0; i < len(s.owners) && len(nodes) < n; i++ {
+		owner := s.owners[(start+i)%len(s.owners)]
+		if !contains(nodes, owner) {
+			nodes = append(nodes, owner)
+		}
 	}
+	return nodes
+}
 
-	ch.updateSortedKeys()
+// Position returns the position of a key on the ring.
+func (s *RingSnapshot) Position(key string) uint32 {
+	return s.hashKey(key)
 }
 
-// ListNodes returns a list of all physical nodes in the consistent hash ring.
-func (ch *ConsistentHash) ListNodes() []string {
-	nodes := make([]string, 0, len(ch.nodes))
-	for node := range ch.nodes {
+// RingToken is a virtual node, Owner holds the arc ending at Position. It is Node unless loads are
+// bounded.
+type RingToken struct {
+	Position uint32
+	Node     string
+	Owner    string
+}
+
+// Tokens returns the virtual nodes in ring order.
+func (s *RingSnapshot) Tokens() []RingToken {
+	tokens := make([]RingToken, 0, len(s.hashSortedKeys))
+	for i, key := range s.hashSortedKeys {
+		tokens = append(tokens, RingToken{Position: key, Node: s.hashRing[key], Owner: s.owners[i]})
+	}
+	return tokens
+}
+
+// Token returns the virtual node whose arc holds a key.
+func (s *RingSnapshot) Token(key string) (RingToken, error) {
+	if len(s.nodes) == 0 {
+		return RingToken{}, errors.New("consistent hash ring is empty")
+	}
+	index := s.searchNearestKeyIndex(s.hashKey(key))
+	return RingToken{Position: s.hashSortedKeys[index], Node: s.hashRing[s.hashSortedKeys[index]], Owner: s.owners[index]}, nil
+}
+
+// Shares returns the fraction of the ring each node owns.
+func (s *RingSnapshot) Shares() map[string]float64 {
+	shares := make(map[string]float64, len(s.nodes))
+	keys := s.hashSortedKeys
+	for i := range keys {
+		arc := float64(keys[i] - keys[(i+len(keys)-1)%len(keys)])
+		if len(keys) == 1 {
+			arc = math.Exp2(32) // The only token holds the whole ring
+		}
+		shares[s.owners[i]] += arc / math.Exp2(32)
+	}
+	return shares
+}
+
+// Weight returns the weight of a node, 0 when it is not in the ring.
+func (s *RingSnapshot) Weight(node string) int {
+	return s.nodes[node]
+}
+
+// ListNodes returns a list of all physical nodes in the ring.
+func (s *RingSnapshot) ListNodes() []string {
+	nodes := make([]string, 0, len(s.nodes))
+	for node := range s.nodes {
 		nodes = append(nodes, node)
 	}
 	return nodes
 }
 
+// virtualNodeCount returns the number of virtual nodes of a node of the given weight.
+func (s *RingSnapshot) virtualNodeCount(weight int) int {
+	return (s.virtualNodes + 1) * weight
+}
+
+// addVirtualNodes adds the virtual nodes of indexes [from, to) of a node.
+func (s *RingSnapshot) addVirtualNodes(node string, from, to int) {
+	for i := from; i < to; i++ {
+		s.hashRing[s.virtualNodeKey(i, node)] = node
+	}
+}
+
+// removeVirtualNodes removes the virtual nodes of indexes [from, to) of a node.
+func (s *RingSnapshot) removeVirtualNodes(node string, from, to int) {
+	for i := from; i < to; i++ {
+		virtualKey := s.virtualNodeKey(i, node)
+		// Another node may own the same position after a collision
+		if s.hashRing[virtualKey] == node {
+			delete(s.hashRing, virtualKey)
+		}
+	}
+}
+
 // virtualNodeKey computes the hash key for a virtual node.
-func (ch *ConsistentHash) virtualNodeKey(index int, node string) uint32 {
-	return ch.hashKey(strconv.Itoa(index) + "-" + node)
+func (s *RingSnapshot) virtualNodeKey(index int, node string) uint32 {
+	return s.hashKey(strconv.Itoa(index) + "-" + node)
 }
 
 // searchNearestKeyIndex finds the index of the nearest hash key in the ring for the given key.
-func (ch *ConsistentHash) searchNearestKeyIndex(key uint32) int {
-	index := sort.Search(len(ch.hashSortedKeys), func(i int) bool {
-		return ch.hashSortedKeys[i] >= key
+func (s *RingSnapshot) searchNearestKeyIndex(key uint32) int {
+	index := sort.Search(len(s.hashSortedKeys), func(i int) bool {
+		return s.hashSortedKeys[i] >= key
 	})
-	if index == len(ch.hashSortedKeys) {
+	if index == len(s.hashSortedKeys) {
 		index = 0 // Wrap around to the start of the ring
 	}
 	return index
 }
 
 // updateSortedKeys refreshes the sorted list of hash keys in the ring.
-func (ch *ConsistentHash) updateSortedKeys() {
-	keys := make([]uint32, 0, len(ch.hashRing))
-	for key := range ch.hashRing {
+func (s *RingSnapshot) updateSortedKeys() {
+	keys := make([]uint32, 0, len(s.hashRing))
+	for key := range s.hashRing {
 		keys = append(keys, key)
 	}
 	sort.Slice(keys, func(i, j int) bool {
 		return keys[i] < keys[j]
 	})
-	ch.hashSortedKeys = keys
+	s.hashSortedKeys = keys
+	s.updateOwners()
+}
+
+// updateOwners assigns each arc to the node of its virtual node. With bounded loads the arcs are
+// assigned in ring order and an arc that would take a node past its bound goes to the next node
+// clockwise with room left, or to the least loaded node when none has room.
+func (s *RingSnapshot) updateOwners() {
+	keys := s.hashSortedKeys
+	owners := make([]string, len(keys))
+	for i, key := range keys {
+		owners[i] = s.hashRing[key]
+	}
+	s.owners = owners
+	if s.loadFactor == 0 || len(s.nodes) < 2 {
+		return
+	}
+
+	totalWeight := 0
+	for _, weight := range s.nodes {
+		totalWeight += weight
+	}
+	capacity := make(map[string]float64, len(s.nodes))
+	for node, weight := range s.nodes {
+		capacity[node] = s.loadFactor * math.Exp2(32) * float64(weight) / float64(totalWeight)
+	}
+
+	load := make(map[string]float64, len(s.nodes))
+	for i := range keys {
+		// Arc after the previous key up to this one, wrapping past zero for the first
+		arc := float64(keys[i] - keys[(i+len(keys)-1)%len(keys)])
+		owner := ""
+		for j := 0; j < len(keys) && owner == ""; j++ {
+			candidate := s.hashRing[keys[(i+j)%len(keys)]]
+			if load[candidate]+arc <= capacity[candidate] {
+				owner = candidate
+			}
+		}
+		if owner == "" {
+			for _, node := range s.sortedNodes() {
+				if owner == "" || load[node]/capacity[node] < load[owner]/capacity[owner] {
+					owner = node
+				}
+			}
+		}
+		owners[i] = owner
+		load[owner] += arc
+	}
+}
+
+// sortedNodes returns the physical nodes sorted by name.
+func (s *RingSnapshot) sortedNodes() []string {
+	nodes := s.ListNodes()
+	sort.Strings(nodes)
+	return nodes
+}
+
+// hashKey calculates the hash of the ring for a given object.
+func (s *RingSnapshot) hashKey(obj string) uint32 {
+	return s.hash(obj)
+}
+
+// KeyHash is the position of a key on a ring using the default CRC32 hash
+func KeyHash(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
+
+// HashRange is the arc of the ring after Start up to and including End. It wraps past zero
+// when Start >= End, Start == End is the whole ring.
+type HashRange struct {
+	Start uint32
+	End   uint32
+}
+
+// Contains reports whether a ring position falls in the range
+func (r HashRange) Contains(hash uint32) bool {
+	if r.Start < r.End {
+		return hash > r.Start && hash <= r.End
+	}
+	return hash > r.Start || hash <= r.End
 }
 
-// hashKey calculates the CRC32 hash for a given object.
-func (ch *ConsistentHash) hashKey(obj string) uint32 {
-	return crc32.ChecksumIEEE([]byte(obj))
+// RangeMove is a range of keys owned by From in one ring and by To in another
+type RangeMove struct {
+	Range HashRange
+	From  string
+	To    string
+}
+
+// ReplicaMove is a range of keys replicated on Before in one ring and on After in another, owner first
+type ReplicaMove struct {
+	Range  HashRange
+	Before []string
+	After  []string
+}
+
+// MovedReplicas returns the ranges whose n replicas are not the same nodes in two rings, adjacent
+// ranges with the same replicas are merged. As MovedRanges otherwise.
+func MovedReplicas(before, after *ConsistentHash, n int) []ReplicaMove {
+	return before.Snapshot().DiffReplicas(after.Snapshot(), n)
+}
+
+// sameNodes reports whether two lists hold the same nodes in any order
+func sameNodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, node := range a {
+		if !contains(b, node) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffReplicas returns the ranges whose n replicas differ between the snapshot and a later one, as
+// MovedReplicas.
+func (s *RingSnapshot) DiffReplicas(after *RingSnapshot, n int) []ReplicaMove {
+	before := s
+	if len(before.nodes) == 0 || len(after.nodes) == 0 {
+		return nil
+	}
+
+	moves := []ReplicaMove{}
+	unique := before.boundaries(after)
+	for i, point := range unique {
+		start := unique[(i+len(unique)-1)%len(unique)]
+		from := before.replicasAt(before.searchNearestKeyIndex(point), n)
+		to := after.replicasAt(after.searchNearestKeyIndex(point), n)
+		if sameNodes(from, to) {
+			continue
+		}
+
+		if last := len(moves) - 1; last >= 0 && slices.Equal(moves[last].Before, from) && slices.Equal(moves[last].After, to) && moves[last].Range.End == start {
+			moves[last].Range.End = point
+			continue
+		}
+		moves = append(moves, ReplicaMove{Range: HashRange{Start: start, End: point}, Before: from, After: to})
+	}
+	return moves
+}
+
+// boundaries returns the sorted positions of the virtual nodes of two rings, between two
+// consecutive ones the owners of both rings are fixed
+func (s *RingSnapshot) boundaries(after *RingSnapshot) []uint32 {
+	points := append(append([]uint32{}, s.hashSortedKeys...), after.hashSortedKeys...)
+	sort.Slice(points, func(i, j int) bool {
+		return points[i] < points[j]
+	})
+	unique := points[:0]
+	for i, point := range points {
+		if i == 0 || point != points[i-1] {
+			unique = append(unique, point)
+		}
+	}
+	return unique
+}
+
+// MovedRanges returns the ranges whose owner differs between two rings, adjacent ranges moving
+// between the same nodes are merged. Both rings must use the same hash function. Nothing moves
+// from or to an empty ring.
+func MovedRanges(before, after *ConsistentHash) []RangeMove {
+	return before.Snapshot().Diff(after.Snapshot())
+}
+
+// Diff returns the ranges whose owner differs between the snapshot and a later one, as MovedRanges.
+func (s *RingSnapshot) Diff(after *RingSnapshot) []RangeMove {
+	before := s
+	if len(before.nodes) == 0 || len(after.nodes) == 0 {
+		return nil
+	}
+
+	moves := []RangeMove{}
+	unique := before.boundaries(after)
+	for i, point := range unique {
+		start := unique[(i+len(unique)-1)%len(unique)]
+		from := before.owners[before.searchNearestKeyIndex(point)]
+		to := after.owners[after.searchNearestKeyIndex(point)]
+		if from == to {
+			continue
+		}
+
+		if last := len(moves) - 1; last >= 0 && moves[last].From == from && moves[last].To == to && moves[last].Range.End == start {
+			moves[last].Range.End = point
+			continue
+		}
+		moves = append(moves, RangeMove{Range: HashRange{Start: start, End: point}, From: from, To: to})
+	}
+	return moves
+}
+
+// ReplicaRange is an arc of the ring and the nodes holding its keys, the owner first
+type ReplicaRange struct {
+	Range HashRange
+	Nodes []string
+}
+
+// ReplicaRanges returns every arc of the ring with the n nodes GetNodes returns for its keys,
+// adjacent arcs held by the same nodes are merged.
+func (s *RingSnapshot) ReplicaRanges(n int) []ReplicaRange {
+	if len(s.nodes) == 0 {
+		return nil
+	}
+
+	n = min(max(n, 1), len(s.nodes))
+	ranges := []ReplicaRange{}
+	for i, point := range s.hashSortedKeys {
+		start := s.hashSortedKeys[(i+len(s.hashSortedKeys)-1)%len(s.hashSortedKeys)]
+		nodes := make([]string, 0, n)
+		for j := 0; j < len(s.owners) && len(nodes) < n; j++ {
+			if owner := s.owners[(i+j)%len(s.owners)]; !contains(nodes, owner) {
+				nodes = append(nodes, owner)
+			}
+		}
+
+		if last := len(ranges) - 1; last >= 0 && ranges[last].Range.End == start && slices.Equal(ranges[last].Nodes, nodes) {
+			ranges[last].Range.End = point
+			continue
+		}
+		ranges = append(ranges, ReplicaRange{Range: HashRange{Start: start, End: point}, Nodes: nodes})
+	}
+	return ranges
 }
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+var ErrNotInteger = errors.New("value is not a signed 64 bit integer")
+var ErrOverflow = errors.New("increment would overflow")
+
+// Increment atomically adds delta to the integer stored at key and returns the result.
+// A missing or expired key starts at 0, the TTL of an existing key is kept.
+func (ht *HashTable) Increment(key string, delta int64, RInfo *CheckpointInfo) (int64, error) {
+	bucketIndex := hashKey(key, ht.bucketSize)
+
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return 0, err
+	}
+
+	node, isFound := ht.buckets[bucketIndex].search(key)
+	if isFound && isExpired(&node.entry, time.Now().UnixNano()) {
+		// Start over like a fresh key
+		ht.record(RInfo, WALRecord{Operation: "EXPIRE", Key: key})
+		ht.remove(key)
+		ht.expirations++
+		isFound = false
+	}
+
+	var current int64
+	var expiresAt, lease int64
+	if isFound {
+		if node.entry.Type != TypeString {
+			return 0, ErrWrongType
+		}
+		parsed, err := strconv.ParseInt(string(node.entry.Value), 10, 64)
+		if err != nil {
+			return 0, ErrNotInteger
+		}
+		current = parsed
+		expiresAt = node.entry.ExpiresAt
+		lease = node.entry.Lease
+	}
+
+	if (delta > 0 && current > math.MaxInt64-delta) || (delta < 0 && current < math.MinInt64-delta) {
+		return 0, ErrOverflow
+	}
+	result := current + delta
+	value := []byte(strconv.FormatInt(result, 10))
+
+	sizeDelta := entrySize(key, value)
+	if isFound {
+		sizeDelta -= node.entry.size()
+	}
+	if err := ht.reserve(sizeDelta, key, RInfo); err != nil {
+		return 0, err
+	}
+	// Evictions rebalance the trees, look the node up again
+	node, isFound = ht.buckets[bucketIndex].search(key)
+
+	// Logged as the resulting value so a replay does not add twice
+	version := ht.record(RInfo, WALRecord{Operation: "INCR", Key: key, Value: value, ExpiresAt: expiresAt, Lease: lease})
+
+	ht.usedMemory += sizeDelta
+	if isFound {
+		ht.setValue(node, value)
+		node.entry.Version = version
+		ht.touch(&node.entry)
+		return result, nil
+	}
+
+	entry := Entry{Key: key, Value: value, Version: version}
+	ht.touch(&entry)
+	ht.add(bucketIndex, entry)
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// EvictionPolicy decides which entry is dropped when the table is over its memory limit
+type EvictionPolicy int
+
+const (
+	NoEviction  EvictionPolicy = iota // Reject writes once the limit is reached
+	AllKeysLRU                        // Evict the least recently used key
+	AllKeysLFU                        // Evict the least frequently used key
+	VolatileTTL                       // Evict the key with the nearest expiry, only keys with a TTL
+)
+
+// Approximate per entry overhead (tree node, pointers, bookkeeping) added to key and value sizes
+const entryOverhead = 64
+
+// Number of candidates sampled per eviction, tables smaller than this are scanned fully
+const evictionSamples = 16
+
+var ErrOutOfMemory = errors.New("memory limit reached")
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case AllKeysLRU:
+		return "allkeys-lru"
+	case AllKeysLFU:
+		return "allkeys-lfu"
+	case VolatileTTL:
+		return "volatile-ttl"
+	default:
+		return "noeviction"
+	}
+}
+
+// ParseEvictionPolicy converts a config value to an EvictionPolicy, empty means noeviction
+func ParseEvictionPolicy(policy string) (EvictionPolicy, error) {
+	switch strings.ToLower(policy) {
+	case "", "noeviction":
+		return NoEviction, nil
+	case "allkeys-lru":
+		return AllKeysLRU, nil
+	case "allkeys-lfu":
+		return AllKeysLFU, nil
+	case "volatile-ttl":
+		return VolatileTTL, nil
+	}
+	return NoEviction, fmt.Errorf("Unknown eviction policy: %s", policy)
+}
+
+func entrySize(key string, value []byte) int64 {
+	return int64(len(key) + len(value) + entryOverhead)
+}
+
+// isBetterVictim reports if candidate should be evicted before current under the given policy
+func isBetterVictim(policy EvictionPolicy, candidate, current *Entry) bool {
+	if current == nil {
+		return true
+	}
+	switch policy {
+	case AllKeysLFU:
+		if candidate.frequency != current.frequency {
+			return candidate.frequency < current.frequency
+		}
+		return candidate.lastAccess < current.lastAccess
+	case VolatileTTL:
+		return candidate.ExpiresAt < current.ExpiresAt
+	default:
+		return candidate.lastAccess < current.lastAccess
+	}
+}
+
+// selectVictim returns the key to evict next, skipping the key being written. Caller must hold the write lock.
+func (ht *HashTable) selectVictim(skip string) (string, bool) {
+	var victim *Entry
+
+	consider := func(entry *Entry) {
+		if entry.Key == skip {
+			return
+		}
+		if _, isLocked := ht.locks[entry.Key]; isLocked {
+			return
+		}
+		if ht.policy == VolatileTTL && entry.ExpiresAt == 0 {
+			return
+		}
+		if isBetterVictim(ht.policy, entry, victim) {
+			victim = entry
+		}
+	}
+
+	if ht.numKeys > evictionSamples {
+		// Approximate like redis : random walk into random buckets
+		for i := 0; i < evictionSamples; i++ {
+			current := ht.buckets[rand.Intn(ht.bucketSize)].root
+			for current != nil {
+				next := current.left
+				if rand.Intn(2) == 0 {
+					next = current.right
+				}
+				if next == nil || rand.Intn(4) == 0 {
+					break
+				}
+				current = next
+			}
+			if current != nil {
+				consider(&current.entry)
+			}
+		}
+	}
+
+	// Small table or nothing usable sampled, fall back to a full scan
+	if victim == nil {
+		for _, bucket := range ht.buckets {
+			bucket.walk(func(node *TreeNode) bool {
+				consider(&node.entry)
+				return true
+			})
+		}
+	}
+
+	if victim == nil {
+		return "", false
+	}
+	return victim.Key, true
+}
+
+// reserve evicts entries until delta more bytes fit in the memory limit next to the memory held
+// by prepared transactions. Caller must hold the write lock.
+func (ht *HashTable) reserve(delta int64, skip string, RInfo *CheckpointInfo) error {
+	if ht.maxMemory <= 0 {
+		return nil
+	}
+	if delta > ht.maxMemory {
+		return ErrOutOfMemory
+	}
+
+	for ht.usedMemory+ht.reserved+delta > ht.maxMemory {
+		if ht.policy == NoEviction {
+			return ErrOutOfMemory
+		}
+
+		victim, ok := ht.selectVictim(skip)
+		if !ok {
+			return ErrOutOfMemory
+		}
+
+		ht.record(RInfo, WALRecord{Operation: "EVICT", Key: victim})
+		ht.remove(victim)
+		ht.evictions++
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"container/heap"
+)
+
+// keyItem is a key due at a time in unix nano
+type keyItem struct {
+	key string
+	at  int64
+}
+
+// keyHeap orders keys by the time they are due, the first one is due first. A key has one item at
+// most, index locates it so a key rewritten or deleted moves or leaves the heap in place.
+type keyHeap struct {
+	items []keyItem
+	index map[string]int
+}
+
+func (h *keyHeap) Len() int           { return len(h.items) }
+func (h *keyHeap) Less(i, j int) bool { return h.items[i].at < h.items[j].at }
+func (h *keyHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.index[h.items[i].key] = i
+	h.index[h.items[j].key] = j
+}
+func (h *keyHeap) Push(x any) {
+	item := x.(keyItem)
+	h.index[item.key] = len(h.items)
+	h.items = append(h.items, item)
+}
+func (h *keyHeap) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	delete(h.index, item.key)
+	return item
+}
+
+// set schedules a key at a time, replacing its previous item
+func (h *keyHeap) set(key string, at int64) {
+	if nil == h.index {
+		h.index = make(map[string]int)
+	}
+	if i, ok := h.index[key]; ok {
+		h.items[i].at = at
+		heap.Fix(h, i)
+		return
+	}
+	heap.Push(h, keyItem{key: key, at: at})
+}
+
+// remove unschedules a key
+func (h *keyHeap) remove(key string) {
+	if i, ok := h.index[key]; ok {
+		heap.Remove(h, i)
+	}
+}
+
+// popDue pops the first key when it is due at now
+func (h *keyHeap) popDue(now int64) (keyItem, bool) {
+	if len(h.items) == 0 || h.items[0].at > now {
+		return keyItem{}, false
+	}
+	return heap.Pop(h).(keyItem), true
+}
+
+// expireAt schedules the expiry of a key, a key that never expires is unscheduled. Caller must
+// hold the write lock.
+func (ht *HashTable) expireAt(key string, expiresAt int64) {
+	if expiresAt == 0 {
+		ht.expiries.remove(key)
+		return
+	}
+	ht.expiries.set(key, expiresAt)
+}
+
+// dueExpiries pops the keys whose TTL has passed at now. Keys locked by a prepared transaction are
+// scheduled again, they expire once released. Caller must hold the write lock.
+func (ht *HashTable) dueExpiries(now int64) []string {
+	due, locked := []string{}, []keyItem{}
+	for {
+		item, ok := ht.expiries.popDue(now)
+		if !ok {
+			break
+		}
+		if _, isLocked := ht.locks[item.key]; isLocked {
+			locked = append(locked, item)
+			continue
+		}
+		due = append(due, item.key)
+	}
+	for _, item := range locked {
+		ht.expiries.set(item.key, item.at)
+	}
+	return due
+}
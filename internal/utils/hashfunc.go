@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+)
+
+// HashFunc places keys and virtual nodes on the ring
+type HashFunc func(key string) uint32
+
+// Hash functions of the placements
+const (
+	HashCRC32   = "crc32"
+	HashFNV1a   = "fnv1a"
+	HashXXHash  = "xxhash"
+	HashMurmur3 = "murmur3"
+)
+
+var ErrUnknownHash = errors.New("unknown hash function")
+
+// ParseHash returns the hash function of a name, crc32 when empty
+func ParseHash(name string) (HashFunc, error) {
+	hash, _, err := resolveHash(name)
+	return hash, err
+}
+
+// resolveHash returns the hash function of a name and the name, crc32 when empty
+func resolveHash(name string) (HashFunc, string, error) {
+	if name == "" {
+		name = HashCRC32
+	}
+	hash, err := parseHash(name)
+	return hash, name, err
+}
+
+func parseHash(name string) (HashFunc, error) {
+	switch name {
+	case HashCRC32:
+		return crc32Hash, nil
+	case HashFNV1a:
+		return fnv1aHash, nil
+	case HashXXHash:
+		return xxHash32, nil
+	case HashMurmur3:
+		return murmur3Hash, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownHash, name)
+}
+
+func crc32Hash(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
+
+// fnv1aHash is the 32 bit FNV-1a hash
+func fnv1aHash(key string) uint32 {
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return hash
+}
+
+func le32(b string) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+var (
+	xxPrime1 uint32 = 2654435761
+	xxPrime2 uint32 = 2246822519
+	xxPrime3 uint32 = 3266489917
+	xxPrime4 uint32 = 668265263
+	xxPrime5 uint32 = 374761393
+)
+
+func xxRound(acc, input uint32) uint32 {
+	return bits.RotateLeft32(acc+input*xxPrime2, 13) * xxPrime1
+}
+
+// xxHash32 is xxHash32 with a seed of 0
+func xxHash32(key string) uint32 {
+	n := len(key)
+	var hash uint32
+	if n >= 16 {
+		v1, v2, v3, v4 := xxPrime1+xxPrime2, xxPrime2, uint32(0), -xxPrime1
+		for len(key) >= 16 {
+			v1 = xxRound(v1, le32(key[0:]))
+			v2 = xxRound(v2, le32(key[4:]))
+			v3 = xxRound(v3, le32(key[8:]))
+			v4 = xxRound(v4, le32(key[12:]))
+			key = key[16:]
+		}
+		hash = bits.RotateLeft32(v1, 1) + bits.RotateLeft32(v2, 7) + bits.RotateLeft32(v3, 12) + bits.RotateLeft32(v4, 18)
+	} else {
+		hash = xxPrime5
+	}
+	hash += uint32(n)
+
+	for len(key) >= 4 {
+		hash = bits.RotateLeft32(hash+le32(key)*xxPrime3, 17) * xxPrime4
+		key = key[4:]
+	}
+	for i := 0; i < len(key); i++ {
+		hash = bits.RotateLeft32(hash+uint32(key[i])*xxPrime5, 11) * xxPrime1
+	}
+
+	hash ^= hash >> 15
+	hash *= xxPrime2
+	hash ^= hash >> 13
+	hash *= xxPrime3
+	hash ^= hash >> 16
+	return hash
+}
+
+// murmur3Hash is the 32 bit MurmurHash3 with a seed of 0
+func murmur3Hash(key string) uint32 {
+	const c1, c2 uint32 = 0xcc9e2d51, 0x1b873593
+	n := len(key)
+	var hash uint32
+
+	for len(key) >= 4 {
+		k := le32(key) * c1
+		k = bits.RotateLeft32(k, 15) * c2
+		hash ^= k
+		hash = bits.RotateLeft32(hash, 13)*5 + 0xe6546b64
+		key = key[4:]
+	}
+
+	var k uint32
+	switch len(key) {
+	case 3:
+		k ^= uint32(key[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(key[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(key[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15) * c2
+		hash ^= k
+	}
+
+	hash ^= uint32(n)
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	hash *= 0xc2b2ae35
+	hash ^= hash >> 16
+	return hash
+}
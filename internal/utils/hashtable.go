@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"hash/fnv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -27,9 +29,23 @@ type TreeNode struct {
 }
 
 type Entry struct {
-	Key   string
-	Value []byte
-	mutex sync.RWMutex
+	Key       string
+	Value     []byte
+	ExpiresAt int64  // Unix nano, 0 if the key never expires
+	Version   uint64 // LSN of the last change to the key
+	Lease     int64  // Lease the key is deleted with, 0 if none
+
+	// Typed values, only the field matching Type is set. Value is used by TypeString.
+	Type ValueType
+	List [][]byte
+	Set  map[string]struct{}
+	Hash map[string][]byte
+
+	// Concurrent values of TypeVersioned
+	Siblings []Sibling
+
+	lastAccess int64  // Logical access clock, used by LRU
+	frequency  uint32 // Access counter, used by LFU
 }
 
 type Bucket struct {
@@ -216,9 +232,10 @@ func (b *Bucket) transplant(u, v *TreeNode) {
 }
 
 // insert inserts a new entry into the Red-Black Tree and ensures balancing.
-func (b *Bucket) insert(key string, value []byte) {
+func (b *Bucket) insert(entry Entry) {
+	key := entry.Key
 	newNode := &TreeNode{
-		entry: Entry{Key: key, Value: value},
+		entry: entry,
 		color: RED, // New nodes are always red initially
 	}
 
@@ -302,6 +319,30 @@ func (b *Bucket) delete(key string) bool {
 	return true
 }
 
+// walk visits the nodes in key order until fn returns false
+func (b *Bucket) walk(fn func(node *TreeNode) bool) {
+	stack := []*TreeNode{}
+	current := b.root
+
+	for current != nil || len(stack) > 0 {
+		// Go to the leftmost node
+		for current != nil {
+			stack = append(stack, current)
+			current = current.left
+		}
+
+		// Pop from stack and process node
+		current = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fn(current) {
+			return
+		}
+
+		// Move to the right node
+		current = current.right
+	}
+}
+
 // Search for a key in the Red-Black Tree
 func (b *Bucket) search(key string) (*TreeNode, bool) {
 	current := b.root
@@ -322,6 +363,59 @@ type HashTable struct {
 	buckets    []*Bucket
 	bucketSize int
 	mtx        sync.RWMutex
+
+	// Keys of every bucket in one ordered tree, values live in the buckets
+	index *Bucket
+
+	// Secondary indexes on JSON value fields
+	indexes []*SecondaryIndex
+
+	// Log sequence numbers and change notifications
+	lsn       uint64
+	feed      *changeFeed
+	replaying bool
+	replayLSN uint64
+
+	// Leases by ID, their keys are deleted when they expire
+	leases map[int64]*Lease
+
+	// Transactions prepared for a two-phase commit and the keys they lock
+	prepared map[string]*PreparedTxn
+	locks    map[string]string // key -> transaction ID
+
+	// Records of a transaction being applied, logged together as one TXN record
+	grouping bool
+	group    []WALRecord
+
+	// Time of the last write of every key and the Merkle tree over them, writeStamp is the time
+	// of a write merged from a replica
+	merkle     *merkleIndex
+	writeStamp int64
+
+	// Memory accounting and eviction
+	numKeys     int
+	usedMemory  int64
+	reserved    int64 // Held by prepared transactions for their writes
+	maxMemory   int64 // 0 means unlimited
+	policy      EvictionPolicy
+	evictions   uint64
+	expirations uint64
+	clock       int64
+
+	// Keys with a TTL by expiry, DeleteExpired only visits the due ones
+	expiries keyHeap
+}
+
+// TableStats is a point in time view of the table counters
+type TableStats struct {
+	NumKeys        int
+	UsedMemory     int64
+	MaxMemory      int64
+	EvictionPolicy EvictionPolicy
+	Evictions      uint64
+	Expirations    uint64
+	Expiring       int // Keys with a TTL, scheduled to expire
+	Tombstones     int // Deleted keys remembered for the repairs
 }
 
 func hashKey(key string, bucketSize int) int {
@@ -343,33 +437,171 @@ func NewHashTable(numBuckets int) *HashTable {
 	return &HashTable{
 		buckets:    buckets,
 		bucketSize: numBuckets,
+		index:      &Bucket{},
+		feed:       newChangeFeed(),
+		leases:     make(map[int64]*Lease),
+		prepared:   make(map[string]*PreparedTxn),
+		locks:      make(map[string]string),
+		merkle:     newMerkleIndex(),
 	}
 }
 
+// SetMemoryLimit caps the memory used by keys and values, 0 removes the limit
+func (ht *HashTable) SetMemoryLimit(maxMemoryBytes int64, policy EvictionPolicy) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	ht.maxMemory = maxMemoryBytes
+	ht.policy = policy
+}
+
+func (ht *HashTable) Stats() TableStats {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	return TableStats{
+		NumKeys:        ht.numKeys,
+		UsedMemory:     ht.usedMemory,
+		MaxMemory:      ht.maxMemory,
+		EvictionPolicy: ht.policy,
+		Evictions:      ht.evictions,
+		Expirations:    ht.expirations,
+		Expiring:       ht.expiries.Len(),
+		Tombstones:     ht.merkle.tombstones.Len(),
+	}
+}
+
+// touch records an access for the eviction policies, safe under the read lock
+func (ht *HashTable) touch(entry *Entry) {
+	atomic.StoreInt64(&entry.lastAccess, atomic.AddInt64(&ht.clock, 1))
+	if atomic.LoadUint32(&entry.frequency) < ^uint32(0) {
+		atomic.AddUint32(&entry.frequency, 1)
+	}
+}
+
+func isExpired(entry *Entry, now int64) bool {
+	return entry.ExpiresAt != 0 && entry.ExpiresAt <= now
+}
+
+// remove deletes a key and releases its memory. Caller must hold the write lock.
+func (ht *HashTable) remove(key string) bool {
+	bucket := ht.buckets[hashKey(key, ht.bucketSize)]
+	node, isFound := bucket.search(key)
+	if !isFound {
+		return false
+	}
+
+	ht.usedMemory -= node.entry.size()
+	ht.numKeys--
+	ht.expiries.remove(key)
+	ht.attach(&node.entry, 0)
+	ht.index.delete(key)
+	ht.reindex(key, node.entry.Value, nil)
+	return bucket.delete(key)
+}
+
+// add inserts a key that is not present yet. Caller must hold the write lock.
+func (ht *HashTable) add(bucketIndex int, entry Entry) {
+	ht.buckets[bucketIndex].insert(entry)
+	ht.expireAt(entry.Key, entry.ExpiresAt)
+	ht.index.insert(Entry{Key: entry.Key})
+	ht.reindex(entry.Key, nil, entry.Value)
+	ht.numKeys++
+}
+
+// setValue replaces the value of an existing node with a string. Caller must hold the write lock.
+func (ht *HashTable) setValue(node *TreeNode, value []byte) {
+	ht.reindex(node.entry.Key, node.entry.Value, value)
+	node.entry.Value = value
+	node.entry.Type = TypeString
+	node.entry.List, node.entry.Set, node.entry.Hash, node.entry.Siblings = nil, nil, nil, nil
+}
+
 // Returns true if a new entry was added, false if an existing entry was updated.
 func (ht *HashTable) Put(key string, value []byte, RInfo *CheckpointInfo) bool {
-	bucketIndex := hashKey(key, ht.bucketSize)
+	isAdded, _ := ht.PutWithExpiry(key, value, 0, RInfo)
+	return isAdded
+}
+
+// PutWithExpiry is Put with an absolute expiry in unix nano, 0 for no expiry.
+// Fails with ErrOutOfMemory when the value does not fit and nothing can be evicted.
+func (ht *HashTable) PutWithExpiry(key string, value []byte, expiresAt int64, RInfo *CheckpointInfo) (bool, error) {
+	return ht.PutWithOptions(key, value, PutOptions{ExpiresAt: expiresAt}, RInfo)
+}
 
+// PutWithOptions is Put with an expiry, a lease or a create only condition. Putting a key
+// without a lease detaches it from its previous lease.
+func (ht *HashTable) PutWithOptions(key string, value []byte, options PutOptions, RInfo *CheckpointInfo) (bool, error) {
 	ht.mtx.Lock()
 	defer ht.mtx.Unlock()
 
-	// WAL
-	if nil != RInfo {
-		RInfo.WC <- WALRecord{Operation: "PUT", Key: key, Value: value}
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
+	}
+	return ht.put(key, value, options, RInfo)
+}
+
+// put is PutWithOptions without the transaction locks. Caller must hold the write lock.
+func (ht *HashTable) put(key string, value []byte, options PutOptions, RInfo *CheckpointInfo) (bool, error) {
+	bucketIndex := hashKey(key, ht.bucketSize)
+	expiresAt := options.ExpiresAt
+	now := time.Now().UnixNano()
+
+	// A lease that expired is not attached to even before it is revoked
+	if lease, ok := ht.leases[options.Lease]; options.Lease != 0 && (!ok || lease.ExpiresAt <= now) {
+		return false, ErrLeaseNotFound
 	}
 
 	node, isFound := ht.buckets[bucketIndex].search(key)
+	if options.IfAbsent && isFound && !isExpired(&node.entry, now) {
+		return false, ErrKeyExists
+	}
 
+	delta := entrySize(key, value)
 	if isFound {
-		node.entry.Value = value
-		return false
+		delta -= node.entry.size()
 	}
+	if err := ht.reserve(delta, key, RInfo); err != nil {
+		return false, err
+	}
+	// Evictions rebalance the trees, look the node up again
+	node, isFound = ht.buckets[bucketIndex].search(key)
 
-	ht.buckets[bucketIndex].insert(key, value)
-	return true
+	// WAL
+	version := ht.record(RInfo, WALRecord{Operation: "PUT", Key: key, Value: value, ExpiresAt: expiresAt, Lease: options.Lease})
+
+	ht.usedMemory += delta
+	if isFound {
+		ht.setValue(node, value)
+		node.entry.ExpiresAt = expiresAt
+		ht.expireAt(key, expiresAt)
+		node.entry.Version = version
+		ht.attach(&node.entry, options.Lease)
+		ht.touch(&node.entry)
+		return false, nil
+	}
+
+	entry := Entry{Key: key, Value: value, ExpiresAt: expiresAt, Version: version}
+	ht.touch(&entry)
+	ht.add(bucketIndex, entry)
+	if options.Lease != 0 {
+		node, _ = ht.buckets[bucketIndex].search(key)
+		ht.attach(&node.entry, options.Lease)
+	}
+	return true, nil
 }
 
+// Get returns the string value of key, keys holding other types are reported as missing
 func (ht *HashTable) Get(key string) ([]byte, bool) {
+	value, isFound, err := ht.GetValue(key)
+	if err != nil {
+		return nil, false
+	}
+	return value, isFound
+}
+
+// GetValue is Get that fails with ErrWrongType when the key holds a list, set or hash
+func (ht *HashTable) GetValue(key string) ([]byte, bool, error) {
 	bucketIndex := hashKey(key, ht.bucketSize)
 
 	ht.mtx.RLock()
@@ -377,17 +609,28 @@ func (ht *HashTable) Get(key string) ([]byte, bool) {
 
 	node, isFound := ht.buckets[bucketIndex].search(key)
 
-	if !isFound {
-		return nil, false
+	// Expired keys are removed by DeleteExpired, until then they are hidden
+	if !isFound || isExpired(&node.entry, time.Now().UnixNano()) {
+		return nil, false, nil
+	}
+	if node.entry.Type != TypeString {
+		return nil, false, ErrWrongType
 	}
+	ht.touch(&node.entry)
 
 	value := make([]byte, len(node.entry.Value))
 	copy(value, node.entry.Value)
 
-	return value, true
+	return value, true, nil
 }
 
 func (ht *HashTable) Update(key string, value []byte, RInfo *CheckpointInfo) bool {
+	isPresent, _ := ht.UpdateWithLimit(key, value, RInfo)
+	return isPresent
+}
+
+// UpdateWithLimit is Update that reports ErrOutOfMemory when the new value does not fit
+func (ht *HashTable) UpdateWithLimit(key string, value []byte, RInfo *CheckpointInfo) (bool, error) {
 
 	bucketIndex := hashKey(key, ht.bucketSize)
 
@@ -395,51 +638,88 @@ func (ht *HashTable) Update(key string, value []byte, RInfo *CheckpointInfo) boo
 	ht.mtx.Lock()
 	defer ht.mtx.Unlock()
 
-	if nil != RInfo {
-		RInfo.WC <- WALRecord{Operation: "UPDATE", Key: key, Value: value}
-	}
-
 	node, isFound := ht.buckets[bucketIndex].search(key)
 
-	if !isFound {
-		return false
+	if !isFound || isExpired(&node.entry, time.Now().UnixNano()) {
+		return false, nil
+	}
+	if node.entry.Type != TypeString {
+		return false, ErrWrongType
+	}
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
 	}
 
-	node.entry.Value = value
+	delta := entrySize(key, value) - node.entry.size()
+	if err := ht.reserve(delta, key, RInfo); err != nil {
+		return false, err
+	}
+	node, _ = ht.buckets[bucketIndex].search(key)
 
-	return true
+	version := ht.record(RInfo, WALRecord{Operation: "UPDATE", Key: key, Value: value})
+
+	ht.usedMemory += delta
+	ht.setValue(node, value)
+	node.entry.Version = version
+	ht.touch(&node.entry)
+
+	return true, nil
 }
 
 func (ht *HashTable) Delete(key string, RInfo *CheckpointInfo) bool {
-	bucketIndex := hashKey(key, ht.bucketSize)
+	isPresent, _ := ht.TryDelete(key, RInfo)
+	return isPresent
+}
 
+// TryDelete is Delete that fails with ErrTxnConflict when the key is locked by a transaction
+func (ht *HashTable) TryDelete(key string, RInfo *CheckpointInfo) (bool, error) {
 	ht.mtx.Lock()
 	defer ht.mtx.Unlock()
-	if nil != RInfo {
-		RInfo.WC <- WALRecord{Operation: "DELETE", Key: key}
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
 	}
-	return ht.buckets[bucketIndex].delete(key)
+	return ht.deleteKey(key, RInfo), nil
 }
 
-func (ht *HashTable) Print() {
-	for _, bucket := range ht.buckets {
-		stack := []*TreeNode{}
-		current := bucket.root
+// deleteKey logs and removes a key if it exists. Caller must hold the write lock.
+func (ht *HashTable) deleteKey(key string, RInfo *CheckpointInfo) bool {
+	return ht.deleteKeyAt(key, ht.tombstoneClock(key, nil), RInfo)
+}
 
-		for current != nil || len(stack) > 0 {
-			// Go to the leftmost node
-			for current != nil {
-				stack = append(stack, current)
-				current = current.left
-			}
+// deleteKeyAt is deleteKey leaving clock on the tombstone, see tombstoneClock. Caller must hold
+// the write lock.
+func (ht *HashTable) deleteKeyAt(key string, clock VectorClock, RInfo *CheckpointInfo) bool {
+	if _, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key); !isFound {
+		return false
+	}
+	ht.record(RInfo, WALRecord{Operation: "DELETE", Key: key, Clock: clock})
+	return ht.remove(key)
+}
 
-			// Pop from stack and process node
-			current = stack[len(stack)-1]
-			stack = stack[:len(stack)-1]
-			fmt.Printf("%v : %v\n", current.entry.Key, string(current.entry.Value))
+// DeleteExpired removes every key whose TTL has passed and returns how many were removed. Only
+// the due keys are visited, see dueExpiries.
+func (ht *HashTable) DeleteExpired(RInfo *CheckpointInfo) int {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
 
-			// Move to the right node
-			current = current.right
-		}
+	now := time.Now().UnixNano()
+	expired := ht.dueExpiries(now)
+
+	for _, key := range expired {
+		ht.record(RInfo, WALRecord{Operation: "EXPIRE", Key: key})
+		ht.remove(key)
+		ht.expirations++
+	}
+	ht.merkle.purge(now - int64(TombstoneTTL))
+	return len(expired)
+}
+
+func (ht *HashTable) Print() {
+	for _, bucket := range ht.buckets {
+		bucket.walk(func(node *TreeNode) bool {
+			fmt.Printf("%v : %v\n", node.entry.Key, string(node.entry.Value))
+			return true
+		})
 	}
 }
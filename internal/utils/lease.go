@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrLeaseNotFound = errors.New("lease not found or expired")
+var ErrKeyExists = errors.New("key already exists")
+
+// Lease deletes the keys attached to it when it is not kept alive for TTLSeconds
+type Lease struct {
+	ID         int64 // LSN of the grant, unique for the lifetime of the node data
+	TTLSeconds int64
+	ExpiresAt  int64 // Unix nano
+
+	keys map[string]struct{}
+}
+
+// PutOptions changes how PutWithOptions writes a key
+type PutOptions struct {
+	ExpiresAt int64 // Unix nano, 0 for no expiry
+	Lease     int64 // Lease the key is attached to, 0 for none
+	IfAbsent  bool  // Fail with ErrKeyExists when the key is present
+}
+
+func (lease *Lease) renew(now time.Time) {
+	lease.ExpiresAt = now.Add(time.Duration(lease.TTLSeconds) * time.Second).UnixNano()
+}
+
+// attach moves a key to a lease, 0 detaches it. Caller must hold the write lock.
+func (ht *HashTable) attach(entry *Entry, leaseID int64) {
+	if entry.Lease == leaseID {
+		return
+	}
+	if lease, ok := ht.leases[entry.Lease]; ok {
+		delete(lease.keys, entry.Key)
+	}
+	entry.Lease = leaseID
+	if lease, ok := ht.leases[leaseID]; ok {
+		lease.keys[entry.Key] = struct{}{}
+	}
+}
+
+// GrantLease creates a lease expiring after ttlSeconds unless it is kept alive.
+// Recovered leases start a full TTL again as keep alives are not logged.
+func (ht *HashTable) GrantLease(ttlSeconds int64, RInfo *CheckpointInfo) Lease {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	id := int64(ht.record(RInfo, WALRecord{Operation: "GRANT", LeaseTTL: ttlSeconds}))
+	lease := &Lease{ID: id, TTLSeconds: ttlSeconds, keys: make(map[string]struct{})}
+	lease.renew(time.Now())
+	ht.leases[id] = lease
+	return *lease
+}
+
+// KeepAliveLease pushes the expiry of a lease a full TTL from now
+func (ht *HashTable) KeepAliveLease(id int64) (Lease, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	now := time.Now()
+	lease, ok := ht.leases[id]
+	if !ok || lease.ExpiresAt <= now.UnixNano() {
+		return Lease{}, ErrLeaseNotFound
+	}
+	lease.renew(now)
+	return *lease, nil
+}
+
+// RevokeLease deletes a lease and every key attached to it, returns the number of keys deleted
+func (ht *HashTable) RevokeLease(id int64, RInfo *CheckpointInfo) (int, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if _, ok := ht.leases[id]; !ok {
+		return 0, ErrLeaseNotFound
+	}
+	return ht.revoke(id, "DELETE", RInfo), nil
+}
+
+// ExpireLeases revokes every lease that was not kept alive and returns how many were revoked
+func (ht *HashTable) ExpireLeases(RInfo *CheckpointInfo) int {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	now := time.Now().UnixNano()
+	expired := 0
+	for id, lease := range ht.leases {
+		if lease.ExpiresAt <= now {
+			ht.expirations += uint64(ht.revoke(id, "EXPIRE", RInfo))
+			expired++
+		}
+	}
+	return expired
+}
+
+// revoke logs the removal of the keys of a lease with operation and then the lease itself.
+// Caller must hold the write lock.
+func (ht *HashTable) revoke(id int64, operation string, RInfo *CheckpointInfo) int {
+	lease := ht.leases[id]
+	keys := make([]string, 0, len(lease.keys))
+	for key := range lease.keys {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		ht.record(RInfo, WALRecord{Operation: operation, Key: key})
+		ht.remove(key)
+	}
+
+	ht.record(RInfo, WALRecord{Operation: "REVOKE", Lease: id})
+	delete(ht.leases, id)
+	return len(keys)
+}
+
+// Leases returns the live leases
+func (ht *HashTable) Leases() []Lease {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	leases := make([]Lease, 0, len(ht.leases))
+	for _, lease := range ht.leases {
+		leases = append(leases, Lease{ID: lease.ID, TTLSeconds: lease.TTLSeconds, ExpiresAt: lease.ExpiresAt})
+	}
+	return leases
+}
+
+// restoreLease recreates a lease with its original ID while recovering
+func (ht *HashTable) restoreLease(id, ttlSeconds int64) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	lease := &Lease{ID: id, TTLSeconds: ttlSeconds, keys: make(map[string]struct{})}
+	lease.renew(time.Now())
+	ht.leases[id] = lease
+}
+
+// DeleteOwned deletes key only while it is attached to the lease, so a holder whose lease
+// expired can't delete the key of the next holder
+func (ht *HashTable) DeleteOwned(key string, leaseID int64, RInfo *CheckpointInfo) (bool, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound || node.entry.Lease != leaseID {
+		return false, nil
+	}
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
+	}
+	return ht.deleteKey(key, RInfo), nil
+}
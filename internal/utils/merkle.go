@@ -0,0 +1,603 @@
+package utils
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sort"
+	"time"
+)
+
+// MerkleDepth is the depth of the Merkle trees of a table, their leaves split the ring positions
+// in 2^MerkleDepth slices of equal size. Level 0 is the root.
+const MerkleDepth = 12
+
+const merkleShift = 32 - MerkleDepth
+
+// TombstoneTTL is how long a deleted key is remembered for the repairs. A replica that missed the
+// delete and stays away longer brings the key back.
+const TombstoneTTL = 7 * 24 * time.Hour
+
+// KeyStamp is the time of the last write of a key in unix nano, the newer write wins when replicas
+// are repaired. A deleted key keeps one as a tombstone, with the clock of the siblings it deleted
+// when the key was versioned with vector clocks.
+type KeyStamp struct {
+	Timestamp int64
+	Deleted   bool
+	Clock     VectorClock
+}
+
+// ReplicaEntry is a key as exchanged by the repairs, a tombstone has Deleted set and no value
+type ReplicaEntry struct {
+	Entry
+	Timestamp int64
+	Deleted   bool
+	Clock     VectorClock // Of a tombstone, see KeyStamp
+}
+
+func tombstoneEntry(key string, stamp KeyStamp) ReplicaEntry {
+	return ReplicaEntry{Entry: Entry{Key: key}, Timestamp: stamp.Timestamp, Deleted: true, Clock: stamp.Clock}
+}
+
+// MerkleLeaf returns the leaf holding a ring position
+func MerkleLeaf(position uint32) uint32 {
+	return position >> merkleShift
+}
+
+// MerkleLeafRange returns the ring positions of a leaf
+func MerkleLeafRange(leaf uint32) HashRange {
+	return HashRange{Start: leaf<<merkleShift - 1, End: (leaf+1)<<merkleShift - 1}
+}
+
+// merkleIndex keeps the Merkle tree of a table up to date with the writes. The digest of a leaf is
+// the XOR of the digests of its keys, a write updates it without reading the other keys, then the
+// inner nodes above it. Keys are placed on the leaves with the hash function of the ring.
+type merkleIndex struct {
+	hashName   string
+	hash       HashFunc
+	stamps     map[string]KeyStamp
+	values     map[string]uint64     // Value digests of the live keys in the tree, see valueDigest
+	pending    map[string]struct{}   // Live keys written since the tree was last settled
+	tree       []uint64              // Node i of a level is at 1<<level + i
+	keys       []map[string]struct{} // Keys of each leaf, nil until one is written
+	tombstones keyHeap               // Deleted keys by the time of their delete
+}
+
+func newMerkleIndex() *merkleIndex {
+	hash, hashName, _ := resolveHash("")
+	return &merkleIndex{
+		hashName: hashName,
+		hash:     hash,
+		stamps:   make(map[string]KeyStamp),
+		values:   make(map[string]uint64),
+		pending:  make(map[string]struct{}),
+		tree:     make([]uint64, 2<<MerkleDepth),
+		keys:     make([]map[string]struct{}, 1<<MerkleDepth),
+	}
+}
+
+// keyDigest is the digest of a key in its leaf, value is the digest of its value, 0 for a tombstone
+func keyDigest(key string, stamp KeyStamp, value uint64) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+	digest := mix64(hash.Sum64() ^ uint64(stamp.Timestamp))
+	if stamp.Deleted {
+		digest = mix64(digest)
+	}
+	return mix64(digest ^ value)
+}
+
+func bytesDigest(parts ...[]byte) uint64 {
+	hash := fnv.New64a()
+	for _, part := range parts {
+		_ = binary.Write(hash, binary.BigEndian, uint32(len(part)))
+		_, _ = hash.Write(part)
+	}
+	return mix64(hash.Sum64())
+}
+
+// valueDigest hashes the value of an entry with its type and expiry, replicas holding the same
+// value have the same digest. Members, fields and siblings are hashed in any order.
+func valueDigest(entry *Entry) uint64 {
+	digest := mix64(uint64(entry.Type)<<56 ^ uint64(entry.ExpiresAt))
+	switch entry.Type {
+	case TypeList:
+		for _, item := range entry.List {
+			digest = mix64(digest ^ bytesDigest(item))
+		}
+	case TypeSet:
+		for member := range entry.Set {
+			digest ^= bytesDigest([]byte(member))
+		}
+	case TypeHash:
+		for field, value := range entry.Hash {
+			digest ^= bytesDigest([]byte(field), value)
+		}
+	case TypeVersioned:
+		for _, sibling := range entry.Siblings {
+			parts := [][]byte{sibling.Value, []byte(sibling.Dot.Node), binary.BigEndian.AppendUint64(nil, sibling.Dot.Counter)}
+			nodes := make([]string, 0, len(sibling.Context))
+			for node := range sibling.Context {
+				nodes = append(nodes, node)
+			}
+			sort.Strings(nodes)
+			for _, node := range nodes {
+				parts = append(parts, []byte(node), binary.BigEndian.AppendUint64(nil, sibling.Context[node]))
+			}
+			digest ^= bytesDigest(parts...)
+		}
+	default:
+		digest = mix64(digest ^ bytesDigest(entry.Value))
+	}
+	return digest
+}
+
+// combine returns the digest of an inner node, empty subtrees have a zero digest
+func combine(left, right uint64) uint64 {
+	if left == 0 && right == 0 {
+		return 0
+	}
+	return mix64(left ^ mix64(right^0x9e3779b97f4a7c15))
+}
+
+// flip XORs a key digest into a leaf and recomputes the nodes above it
+func (m *merkleIndex) flip(leaf uint32, digest uint64) {
+	i := 1<<MerkleDepth + int(leaf)
+	m.tree[i] ^= digest
+	for i > 1 {
+		i /= 2
+		m.tree[i] = combine(m.tree[2*i], m.tree[2*i+1])
+	}
+}
+
+func (m *merkleIndex) set(key string, stamp KeyStamp) {
+	m.forget(key)
+	leaf := MerkleLeaf(m.hash(key))
+	if nil == m.keys[leaf] {
+		m.keys[leaf] = make(map[string]struct{})
+	}
+	m.keys[leaf][key] = struct{}{}
+	m.stamps[key] = stamp
+	if !stamp.Deleted {
+		// The value is written after its record, it is hashed once the tree is settled
+		m.pending[key] = struct{}{}
+		return
+	}
+	m.flip(leaf, keyDigest(key, stamp, 0))
+	m.tombstones.set(key, stamp.Timestamp)
+}
+
+func (m *merkleIndex) forget(key string) {
+	stamp, ok := m.stamps[key]
+	if !ok {
+		return
+	}
+	leaf := MerkleLeaf(m.hash(key))
+	if _, ok := m.pending[key]; ok {
+		delete(m.pending, key)
+	} else {
+		m.flip(leaf, keyDigest(key, stamp, m.values[key]))
+	}
+	delete(m.keys[leaf], key)
+	delete(m.stamps, key)
+	delete(m.values, key)
+	m.tombstones.remove(key)
+}
+
+// settle hashes the values of the keys written since the last settle into their leaves
+func (m *merkleIndex) settle(valueOf func(key string) uint64) {
+	for key := range m.pending {
+		value := valueOf(key)
+		m.values[key] = value
+		m.flip(MerkleLeaf(m.hash(key)), keyDigest(key, m.stamps[key], value))
+	}
+	clear(m.pending)
+}
+
+// rehash places the keys with another hash function
+func (m *merkleIndex) rehash(hashName string) error {
+	hash, hashName, err := resolveHash(hashName)
+	if err != nil || hashName == m.hashName {
+		return err
+	}
+	stamps := m.stamps
+	*m = merkleIndex{
+		hashName: hashName,
+		hash:     hash,
+		stamps:   make(map[string]KeyStamp, len(stamps)),
+		values:   make(map[string]uint64, len(stamps)),
+		pending:  make(map[string]struct{}),
+		tree:     make([]uint64, 2<<MerkleDepth),
+		keys:     make([]map[string]struct{}, 1<<MerkleDepth),
+	}
+	for key, stamp := range stamps {
+		m.set(key, stamp)
+	}
+	return nil
+}
+
+// purge forgets the tombstones older than before, only the due ones are visited
+func (m *merkleIndex) purge(before int64) {
+	for {
+		item, ok := m.tombstones.popDue(before - 1)
+		if !ok {
+			return
+		}
+		m.forget(item.key)
+	}
+}
+
+// coverage is the union of ranges as sorted disjoint spans of positions, every position when
+// there are no ranges. match further selects the keys, the digests are then computed from them.
+type coverage struct {
+	ranges []HashRange
+	spans  [][2]uint64 // Inclusive
+	match  func(key string) bool
+}
+
+func newCoverage(ranges []HashRange) coverage {
+	c := coverage{ranges: ranges}
+	spans := [][2]uint64{}
+	for _, r := range ranges {
+		if r.Start < r.End {
+			spans = append(spans, [2]uint64{uint64(r.Start) + 1, uint64(r.End)})
+			continue
+		}
+		if r.Start != math.MaxUint32 {
+			spans = append(spans, [2]uint64{uint64(r.Start) + 1, math.MaxUint32})
+		}
+		spans = append(spans, [2]uint64{0, uint64(r.End)})
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i][0] < spans[j][0]
+	})
+	for _, span := range spans {
+		if last := len(c.spans) - 1; last >= 0 && span[0] <= c.spans[last][1]+1 {
+			c.spans[last][1] = max(c.spans[last][1], span[1])
+			continue
+		}
+		c.spans = append(c.spans, span)
+	}
+	return c
+}
+
+// node reports whether the positions under a node of the tree are all covered or none is
+func (c coverage) node(level int, index uint32) (all, none bool) {
+	if len(c.ranges) == 0 {
+		return nil == c.match, false
+	}
+	shift := 32 - level
+	lo := uint64(index) << shift
+	hi := lo + 1<<shift - 1
+	i := c.search(lo)
+	if i == len(c.spans) || c.spans[i][0] > hi {
+		return false, true
+	}
+	return c.spans[i][0] <= lo && c.spans[i][1] >= hi && nil == c.match, false
+}
+
+// search returns the first span ending at or after position
+func (c coverage) search(position uint64) int {
+	return sort.Search(len(c.spans), func(i int) bool {
+		return c.spans[i][1] >= position
+	})
+}
+
+func (c coverage) contains(position uint32) bool {
+	if len(c.ranges) == 0 {
+		return true
+	}
+	i := c.search(uint64(position))
+	return i < len(c.spans) && c.spans[i][0] <= uint64(position)
+}
+
+// keysOf calls fn with the keys of a leaf that are covered
+func (m *merkleIndex) keysOf(leaf uint32, c coverage, fn func(key string, stamp KeyStamp)) {
+	all, none := c.node(MerkleDepth, leaf)
+	if none {
+		return
+	}
+	for key := range m.keys[leaf] {
+		if (all || c.contains(m.hash(key))) && (nil == c.match || c.match(key)) {
+			fn(key, m.stamps[key])
+		}
+	}
+}
+
+// node returns the digest of a node of the tree over the covered keys, only the keys of the leaves
+// a range ends in are read
+func (m *merkleIndex) node(level int, index uint32, c coverage) uint64 {
+	all, none := c.node(level, index)
+	switch {
+	case none:
+		return 0
+	case all:
+		return m.tree[1<<level+int(index)]
+	case level < MerkleDepth:
+		return combine(m.node(level+1, 2*index, c), m.node(level+1, 2*index+1, c))
+	}
+	var digest uint64
+	m.keysOf(index, c, func(key string, stamp KeyStamp) {
+		digest ^= keyDigest(key, stamp, m.values[key])
+	})
+	return digest
+}
+
+// stamp sets the time of a keyed record and updates the Merkle tree with it. A merged record keeps
+// the time of the replica it comes from, a new one is later than the previous write of the key.
+// Caller must hold the write lock.
+func (ht *HashTable) stamp(record *WALRecord) {
+	switch record.Operation {
+	case "PUT", "UPDATE", "INCR", "DELETE", "LPUSH", "RPUSH", "LPOP", "RPOP", "SADD", "SREM", "HSET", "HDEL":
+		if record.Moved {
+			ht.merkle.forget(record.Key)
+			return
+		}
+		timestamp := ht.writeStamp
+		if timestamp == 0 {
+			timestamp = time.Now().UnixNano()
+			if previous, ok := ht.merkle.stamps[record.Key]; ok && previous.Timestamp >= timestamp {
+				timestamp = previous.Timestamp + 1
+			}
+		}
+		record.Timestamp = timestamp
+		ht.merkle.set(record.Key, KeyStamp{Timestamp: timestamp, Deleted: record.Operation == "DELETE", Clock: record.Clock})
+	case "EXPIRE", "EVICT":
+		ht.merkle.forget(record.Key)
+	}
+}
+
+// restoreStamp updates the Merkle tree with a replayed record once it is applied. Records logged
+// without a time leave no tombstone.
+func (ht *HashTable) restoreStamp(record WALRecord) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	switch record.Operation {
+	case "PUT", "UPDATE", "INCR", "LPUSH", "RPUSH", "LPOP", "RPOP", "SADD", "SREM", "HSET", "HDEL":
+		if _, isFound := ht.buckets[hashKey(record.Key, ht.bucketSize)].search(record.Key); isFound {
+			ht.merkle.set(record.Key, KeyStamp{Timestamp: record.Timestamp})
+		} else {
+			ht.merkle.forget(record.Key)
+		}
+	case "DELETE":
+		if record.Moved || record.Timestamp == 0 {
+			ht.merkle.forget(record.Key)
+		} else {
+			ht.merkle.set(record.Key, KeyStamp{Timestamp: record.Timestamp, Deleted: true, Clock: record.Clock})
+		}
+	case "EXPIRE", "EVICT":
+		ht.merkle.forget(record.Key)
+	}
+}
+
+// Stamp returns the time of the last write of a key, deleted keys are reported until their
+// tombstone is purged
+func (ht *HashTable) Stamp(key string) (KeyStamp, bool) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	stamp, ok := ht.merkle.stamps[key]
+	return stamp, ok
+}
+
+// GetReplica is GetValue with the time of the last write of the key, a deleted key is not found and
+// returns its tombstone
+func (ht *HashTable) GetReplica(key string) (ReplicaEntry, bool, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	stamp := ht.merkle.stamps[key]
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound || isExpired(&node.entry, time.Now().UnixNano()) {
+		if stamp.Deleted {
+			return tombstoneEntry(key, stamp), false, nil
+		}
+		return ReplicaEntry{Entry: Entry{Key: key}}, false, nil
+	}
+	if node.entry.Type != TypeString {
+		return ReplicaEntry{}, false, ErrWrongType
+	}
+	ht.touch(&node.entry)
+	return ReplicaEntry{Entry: exportEntry(&node.entry), Timestamp: stamp.Timestamp}, true, nil
+}
+
+// rlockMerkle takes the read lock once the keys are placed with the named hash function and the
+// tree is settled, they are placed again and the written values hashed under the write lock first
+func (ht *HashTable) rlockMerkle(hashName string) error {
+	_, hashName, err := resolveHash(hashName)
+	if err != nil {
+		return err
+	}
+	for {
+		ht.mtx.RLock()
+		if ht.merkle.hashName == hashName && len(ht.merkle.pending) == 0 {
+			return nil
+		}
+		ht.mtx.RUnlock()
+
+		ht.mtx.Lock()
+		err := ht.merkle.rehash(hashName)
+		if nil == err {
+			ht.merkle.settle(ht.valueDigestOf)
+		}
+		ht.mtx.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// valueDigestOf returns the digest of the value of a key, 0 when it is missing. Caller must hold
+// the write lock.
+func (ht *HashTable) valueDigestOf(key string) uint64 {
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound {
+		return 0
+	}
+	return valueDigest(&node.entry)
+}
+
+// MerkleTree returns the digests of the nodes of a level of the Merkle tree over the keys and
+// tombstones in ranges, every key when there are no ranges. indexes picks nodes of the level, all
+// of them when empty. Keys are placed with the named hash function, crc32 when empty.
+func (ht *HashTable) MerkleTree(ranges []HashRange, hashName string, level int, indexes []uint32) ([]uint64, error) {
+	return ht.merkleTree(newCoverage(ranges), hashName, level, indexes)
+}
+
+// MerkleTreeMatching is MerkleTree over the keys and tombstones match selects, used when the
+// replicas are not ranges of a ring. The digests are computed from the keys on every call.
+func (ht *HashTable) MerkleTreeMatching(match func(key string) bool, hashName string, level int, indexes []uint32) ([]uint64, error) {
+	return ht.merkleTree(coverage{match: match}, hashName, level, indexes)
+}
+
+func (ht *HashTable) merkleTree(c coverage, hashName string, level int, indexes []uint32) ([]uint64, error) {
+	if err := ht.rlockMerkle(hashName); err != nil {
+		return nil, err
+	}
+	defer ht.mtx.RUnlock()
+	level = min(max(level, 0), MerkleDepth)
+	if len(indexes) == 0 {
+		indexes = make([]uint32, 1<<level)
+		for i := range indexes {
+			indexes[i] = uint32(i)
+		}
+	}
+
+	digests := make([]uint64, 0, len(indexes))
+	for _, index := range indexes {
+		if index >= 1<<level {
+			digests = append(digests, 0)
+			continue
+		}
+		digests = append(digests, ht.merkle.node(level, index, c))
+	}
+	return digests, nil
+}
+
+// ExportReplica returns a copy of the keys and tombstones of the leaves that are in ranges, for a
+// replica to merge. Expired keys are left out.
+func (ht *HashTable) ExportReplica(ranges []HashRange, hashName string, leaves []uint32) ([]ReplicaEntry, error) {
+	return ht.exportReplica(newCoverage(ranges), hashName, leaves)
+}
+
+// ExportReplicaMatching is ExportReplica of the keys and tombstones match selects, see
+// MerkleTreeMatching
+func (ht *HashTable) ExportReplicaMatching(match func(key string) bool, hashName string, leaves []uint32) ([]ReplicaEntry, error) {
+	return ht.exportReplica(coverage{match: match}, hashName, leaves)
+}
+
+func (ht *HashTable) exportReplica(c coverage, hashName string, leaves []uint32) ([]ReplicaEntry, error) {
+	if err := ht.rlockMerkle(hashName); err != nil {
+		return nil, err
+	}
+	defer ht.mtx.RUnlock()
+
+	now := time.Now().UnixNano()
+	entries := []ReplicaEntry{}
+	for _, leaf := range leaves {
+		if leaf >= 1<<MerkleDepth {
+			continue
+		}
+		ht.merkle.keysOf(leaf, c, func(key string, stamp KeyStamp) {
+			if stamp.Deleted {
+				entries = append(entries, tombstoneEntry(key, stamp))
+				return
+			}
+			node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+			if !isFound || isExpired(&node.entry, now) {
+				return
+			}
+			entries = append(entries, ReplicaEntry{Entry: exportEntry(&node.entry), Timestamp: stamp.Timestamp})
+		})
+	}
+	return entries, nil
+}
+
+// MergeReplica writes a key of another replica when its write is newer than the local one, a
+// tombstone deletes the key. A tombstone wins over a write at the same time, of two writes at the
+// same time the one with the larger value digest wins. Returns whether the
+// entry was applied.
+func (ht *HashTable) MergeReplica(entry ReplicaEntry, RInfo *CheckpointInfo) (bool, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	return ht.merge(entry, RInfo)
+}
+
+// PutAt writes a key as of timestamp, a later write of the key wins over it. Returns whether the
+// write was applied.
+func (ht *HashTable) PutAt(key string, value []byte, expiresAt, timestamp int64, RInfo *CheckpointInfo) (bool, error) {
+	return ht.MergeReplica(ReplicaEntry{Entry: Entry{Key: key, Value: value, ExpiresAt: expiresAt}, Timestamp: timestamp}, RInfo)
+}
+
+// DeleteAt deletes a key as of timestamp and leaves a tombstone, a later write of the key wins
+// over it. Returns whether the key was present and whether the delete was applied.
+func (ht *HashTable) DeleteAt(key string, timestamp int64, RInfo *CheckpointInfo) (present, applied bool, err error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	present = isFound && !isExpired(&node.entry, time.Now().UnixNano())
+	applied, err = ht.merge(ReplicaEntry{Entry: Entry{Key: key}, Timestamp: timestamp, Deleted: true}, RInfo)
+	return present, applied, err
+}
+
+// tieBreak reports whether a live entry wins over the live local key written at the same time, the
+// larger value digest wins so replicas that took different values converge. Caller must hold the
+// write lock.
+func (ht *HashTable) tieBreak(entry ReplicaEntry) bool {
+	return valueDigest(&entry.Entry) > ht.valueDigestOf(entry.Key)
+}
+
+// mergeVersioned keeps the siblings of both replicas unless a later delete removed the key, the
+// later time of the two is kept. Fails with ErrWrongType when the key holds a list, set or hash.
+// Caller must hold the write lock.
+func (ht *HashTable) mergeVersioned(entry ReplicaEntry, RInfo *CheckpointInfo) (bool, error) {
+	local, ok := ht.merkle.stamps[entry.Key]
+	if ok && local.Deleted && local.Timestamp >= entry.Timestamp {
+		return false, nil
+	}
+	siblings, err := ht.siblingsOf(entry.Key)
+	if err != nil {
+		return false, err
+	}
+	merged, err := ht.mergeSiblings(entry.Key, siblings, entry.Siblings, entry.Timestamp, RInfo)
+	if err != nil || merged || local.Timestamp >= entry.Timestamp {
+		return merged, err
+	}
+	return true, ht.writeSiblings(entry.Key, MergeSiblings(siblings, entry.Siblings...), entry.Timestamp, RInfo)
+}
+
+// merge is MergeReplica. Caller must hold the write lock.
+func (ht *HashTable) merge(entry ReplicaEntry, RInfo *CheckpointInfo) (bool, error) {
+	if err := ht.checkUnlocked(entry.Key); err != nil {
+		return false, err
+	}
+	if entry.Type == TypeVersioned && !entry.Deleted {
+		if merged, err := ht.mergeVersioned(entry, RInfo); err != ErrWrongType {
+			return merged, err
+		}
+	}
+	if local, ok := ht.merkle.stamps[entry.Key]; ok {
+		if local.Timestamp > entry.Timestamp || local.Timestamp == entry.Timestamp && (local.Deleted || !entry.Deleted && !ht.tieBreak(entry)) {
+			return false, nil
+		}
+	}
+
+	ht.writeStamp = entry.Timestamp
+	defer func() { ht.writeStamp = 0 }()
+
+	if entry.Deleted {
+		// A missing key is logged as well so its tombstone survives a restart
+		clock := ht.tombstoneClock(entry.Key, entry.Clock)
+		if !ht.deleteKeyAt(entry.Key, clock, RInfo) {
+			ht.record(RInfo, WALRecord{Operation: "DELETE", Key: entry.Key, Clock: clock})
+		}
+		return true, nil
+	}
+	if isExpired(&entry.Entry, time.Now().UnixNano()) {
+		return false, nil
+	}
+	return ht.write(entry.Entry, RInfo)
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"time"
+)
+
+// InRanges matches the keys whose position given by hash falls in one of the ranges
+func InRanges(ranges []HashRange, hash HashFunc) func(key string) bool {
+	return func(key string) bool {
+		position := hash(key)
+		for _, r := range ranges {
+			if r.Contains(position) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ReplicatedBy matches the keys a placement replicates on node, out of n replicas
+func ReplicatedBy(placement Placement, n int, node string) func(key string) bool {
+	return func(key string) bool {
+		replicas, err := placement.GetNodes(key, n)
+		return err == nil && contains(replicas, node)
+	}
+}
+
+// exportEntry returns a copy of an entry without its lease. Caller must hold the lock.
+func exportEntry(entry *Entry) Entry {
+	exported := Entry{
+		Key:       entry.Key,
+		Value:     append([]byte{}, entry.Value...),
+		ExpiresAt: entry.ExpiresAt,
+		Version:   entry.Version,
+		Type:      entry.Type,
+		List:      copyItems(entry.List),
+		Hash:      copyFields(entry.Hash),
+		Siblings:  copySiblings(entry.Siblings),
+	}
+	if nil != entry.Set {
+		exported.Set = make(map[string]struct{}, len(entry.Set))
+		for member := range entry.Set {
+			exported.Set[member] = struct{}{}
+		}
+	}
+	return exported
+}
+
+// ExportKeys returns a copy of the live keys and the tombstones that match with the time of their
+// last write. Leases are local to a node, exported keys do not keep theirs.
+func (ht *HashTable) ExportKeys(match func(key string) bool) []ReplicaEntry {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	now := time.Now().UnixNano()
+	entries := []ReplicaEntry{}
+	for _, bucket := range ht.buckets {
+		bucket.walk(func(node *TreeNode) bool {
+			if isExpired(&node.entry, now) || !match(node.entry.Key) {
+				return true
+			}
+			entries = append(entries, ReplicaEntry{Entry: exportEntry(&node.entry), Timestamp: ht.merkle.stamps[node.entry.Key].Timestamp})
+			return true
+		})
+	}
+	for key, stamp := range ht.merkle.stamps {
+		if stamp.Deleted && match(key) {
+			entries = append(entries, tombstoneEntry(key, stamp))
+		}
+	}
+	return entries
+}
+
+// ExportNamed is ExportKeys for the listed keys, it looks them up instead of walking the table
+func (ht *HashTable) ExportNamed(keys []string) []ReplicaEntry {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	now := time.Now().UnixNano()
+	entries := []ReplicaEntry{}
+	for _, key := range keys {
+		stamp := ht.merkle.stamps[key]
+		node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+		switch {
+		case isFound && !isExpired(&node.entry, now):
+			entries = append(entries, ReplicaEntry{Entry: exportEntry(&node.entry), Timestamp: stamp.Timestamp})
+		case stamp.Deleted:
+			entries = append(entries, tombstoneEntry(key, stamp))
+		}
+	}
+	return entries
+}
+
+// Import writes a key moved from another node unless the local copy holds a later write or
+// delete, then it is kept and false is returned. A tombstone deletes the key.
+func (ht *HashTable) Import(entry ReplicaEntry, RInfo *CheckpointInfo) (bool, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	return ht.merge(entry, RInfo)
+}
+
+// write puts a copy of a key of another node, replacing the local one. Caller must hold the write lock.
+func (ht *HashTable) write(entry Entry, RInfo *CheckpointInfo) (bool, error) {
+	bucketIndex := hashKey(entry.Key, ht.bucketSize)
+	if entry.Type == TypeString {
+		_, err := ht.put(entry.Key, entry.Value, PutOptions{ExpiresAt: entry.ExpiresAt}, RInfo)
+		return err == nil, err
+	}
+
+	imported := Entry{Key: entry.Key, Type: entry.Type, ExpiresAt: entry.ExpiresAt, List: entry.List, Set: entry.Set, Hash: entry.Hash, Siblings: entry.Siblings}
+	if imported.isEmpty() {
+		return false, nil
+	}
+	delta := imported.size()
+	if node, isFound := ht.buckets[bucketIndex].search(entry.Key); isFound {
+		delta -= node.entry.size()
+	}
+	if err := ht.reserve(delta, entry.Key, RInfo); err != nil {
+		return false, err
+	}
+	// The local copy is replaced, it is not logged as the replay puts over it
+	ht.remove(entry.Key)
+
+	imported.Version = ht.record(RInfo, WALRecord{
+		Operation: "PUT",
+		Key:       imported.Key,
+		ExpiresAt: imported.ExpiresAt,
+		Type:      imported.Type.String(),
+		Items:     imported.items(),
+		Fields:    copyFields(imported.Hash),
+		Siblings:  imported.Siblings,
+	})
+	ht.touch(&imported)
+	ht.add(bucketIndex, imported)
+	ht.usedMemory += imported.size()
+	return true, nil
+}
+
+// DropKeys deletes the keys that match once they moved to another node. Keys locked by a
+// prepared transaction are kept. Returns the number of keys deleted.
+func (ht *HashTable) DropKeys(match func(key string) bool, RInfo *CheckpointInfo) int {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	keys := []string{}
+	for _, bucket := range ht.buckets {
+		bucket.walk(func(node *TreeNode) bool {
+			if _, locked := ht.locks[node.entry.Key]; !locked && match(node.entry.Key) {
+				keys = append(keys, node.entry.Key)
+			}
+			return true
+		})
+	}
+
+	// Moved keys leave no tombstone, a replica of their new owner must not lose them to a repair
+	for _, key := range keys {
+		ht.record(RInfo, WALRecord{Operation: "DELETE", Key: key, Moved: true})
+		ht.remove(key)
+	}
+	return len(keys)
+}
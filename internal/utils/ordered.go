@@ -0,0 +1,74 @@
+package utils
+
+import "time"
+
+// ascend visits keys in [start, end) in increasing order until fn returns false, empty end means no upper bound
+func (b *Bucket) ascend(node *TreeNode, start, end string, fn func(node *TreeNode) bool) bool {
+	if node == nil {
+		return true
+	}
+	if start < node.entry.Key {
+		if !b.ascend(node.left, start, end, fn) {
+			return false
+		}
+	}
+	if end != "" && node.entry.Key >= end {
+		return true
+	}
+	if node.entry.Key >= start {
+		if !fn(node) {
+			return false
+		}
+	}
+	return b.ascend(node.right, start, end, fn)
+}
+
+// descend visits keys in [start, end) in decreasing order until fn returns false, empty end means no upper bound
+func (b *Bucket) descend(node *TreeNode, start, end string, fn func(node *TreeNode) bool) bool {
+	if node == nil {
+		return true
+	}
+	if end == "" || node.entry.Key < end {
+		if !b.descend(node.right, start, end, fn) {
+			return false
+		}
+		if node.entry.Key >= start {
+			if !fn(node) {
+				return false
+			}
+		}
+	}
+	if node.entry.Key <= start {
+		return true
+	}
+	return b.descend(node.left, start, end, fn)
+}
+
+// Range returns up to limit string entries with keys in [start, end) in key order, descending if reverse is set.
+// An empty end has no upper bound and a limit of 0 returns every match. Lists, sets and hashes are skipped.
+func (ht *HashTable) Range(start, end string, limit int, reverse bool) []Entry {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	now := time.Now().UnixNano()
+	entries := []Entry{}
+	visit := func(indexNode *TreeNode) bool {
+		key := indexNode.entry.Key
+		node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+		if !isFound || isExpired(&node.entry, now) || node.entry.Type != TypeString {
+			return true
+		}
+
+		value := make([]byte, len(node.entry.Value))
+		copy(value, node.entry.Value)
+		entries = append(entries, Entry{Key: key, Value: value, ExpiresAt: node.entry.ExpiresAt, Version: node.entry.Version})
+		return limit <= 0 || len(entries) < limit
+	}
+
+	if reverse {
+		ht.index.descend(ht.index.root, start, end, visit)
+	} else {
+		ht.index.ascend(ht.index.root, start, end, visit)
+	}
+	return entries
+}
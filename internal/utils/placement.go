@@ -0,0 +1,367 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Placement strategies
+const (
+	PlacementRing       = "ring"       // Consistent hashing with virtual nodes
+	PlacementBounded    = "bounded"    // Consistent hashing with bounded loads
+	PlacementRendezvous = "rendezvous" // Highest random weight
+	PlacementJump       = "jump"       // Jump consistent hash
+)
+
+const defaultLoadFactor = 1.25
+
+var ErrUnknownPlacement = errors.New("unknown placement strategy")
+
+// PlacementConfig selects how keys are assigned to nodes
+type PlacementConfig struct {
+	Strategy string `yaml:"Strategy"` // ring when empty
+	Hash     string `yaml:"Hash"`     // crc32 when empty
+
+	// Bounded placement, a node owns at most LoadFactor times its share of the ring. 1.25 when 0.
+	LoadFactor float64 `yaml:"LoadFactor" json:",omitempty"`
+
+	// Ring and bounded placements, set from NumberOfVirtualNodes
+	VirtualNodes int `yaml:"-" json:",omitempty"`
+}
+
+// Placement assigns keys to weighted nodes. A node of weight 4 gets four times the keys of a node
+// of weight 1, a weight below 1 counts as 1. Copies are not affected by later changes.
+type Placement interface {
+	GetNode(key string) (string, error)
+	GetNodes(key string, n int) ([]string, error) // Owner first then the next replicas, up to n
+	AddNode(node string)
+	AddWeightedNode(node string, weight int)
+	RemoveNode(node string)
+	SetWeight(node string, weight int)
+	Weight(node string) int
+	ListNodes() []string
+	Config() PlacementConfig
+	Copy() Placement
+}
+
+// NewPlacement creates an empty placement
+func NewPlacement(config PlacementConfig) (Placement, error) {
+	switch config.Strategy {
+	case "", PlacementRing:
+		return NewConsistentHashWithHash(config.VirtualNodes, config.Hash)
+	case PlacementBounded:
+		loadFactor := config.LoadFactor
+		if loadFactor == 0 {
+			loadFactor = defaultLoadFactor
+		}
+		return NewBoundedConsistentHash(config.VirtualNodes, config.Hash, loadFactor)
+	case PlacementRendezvous:
+		return NewRendezvous(config.Hash)
+	case PlacementJump:
+		return NewJumpHash(config.Hash)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownPlacement, config.Strategy)
+}
+
+// Config returns the settings the ring was created with
+func (ch *ConsistentHash) Config() PlacementConfig {
+	ring := ch.Snapshot()
+	config := PlacementConfig{Strategy: PlacementRing, Hash: ring.hashName, VirtualNodes: ring.virtualNodes}
+	if ring.loadFactor != 0 {
+		config.Strategy, config.LoadFactor = PlacementBounded, ring.loadFactor
+	}
+	return config
+}
+
+// Copy returns a copy of the ring as a Placement
+func (ch *ConsistentHash) Copy() Placement {
+	return ch.Clone()
+}
+
+// Shares returns the fraction of the keys each node is expected to own: its share of the ring for
+// rings, its share of the weights otherwise.
+func Shares(placement Placement) map[string]float64 {
+	if ring, ok := placement.(*ConsistentHash); ok {
+		return ring.Snapshot().Shares()
+	}
+
+	total := 0
+	for _, node := range placement.ListNodes() {
+		total += placement.Weight(node)
+	}
+	shares := make(map[string]float64)
+	for _, node := range placement.ListNodes() {
+		shares[node] = float64(placement.Weight(node)) / float64(total)
+	}
+	return shares
+}
+
+func contains(nodes []string, node string) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+// mix64 is the splitmix64 finalizer, it spreads the bits of a 32 bit hash over 64 bits
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// weightedNodes holds the weights of the nodes of a placement in name order
+type weightedNodes struct {
+	weights map[string]int
+	sorted  []string
+}
+
+func newWeightedNodes() weightedNodes {
+	return weightedNodes{weights: make(map[string]int)}
+}
+
+// set adds or changes a node, returns false when a node to add already exists
+func (wn *weightedNodes) set(node string, weight int, add bool) bool {
+	current, ok := wn.weights[node]
+	if ok == add {
+		return false
+	}
+	weight = max(weight, 1)
+	if current == weight {
+		return false
+	}
+	wn.weights[node] = weight
+	if !ok {
+		wn.sorted = append(wn.sorted, node)
+		sort.Strings(wn.sorted)
+	}
+	return true
+}
+
+func (wn *weightedNodes) remove(node string) bool {
+	if _, ok := wn.weights[node]; !ok {
+		return false
+	}
+	delete(wn.weights, node)
+	index := sort.SearchStrings(wn.sorted, node)
+	wn.sorted = append(wn.sorted[:index], wn.sorted[index+1:]...)
+	return true
+}
+
+func (wn *weightedNodes) clone() weightedNodes {
+	clone := weightedNodes{weights: make(map[string]int, len(wn.weights)), sorted: append([]string{}, wn.sorted...)}
+	for node, weight := range wn.weights {
+		clone.weights[node] = weight
+	}
+	return clone
+}
+
+// Rendezvous assigns a key to the node of highest weighted score. Only the keys of a changed node
+// move but every lookup scores every node.
+type Rendezvous struct {
+	nodes    weightedNodes
+	seeds    []uint64 // Hash of each sorted node
+	hash     HashFunc
+	hashName string
+}
+
+// NewRendezvous creates an empty rendezvous placement with the named hash function
+func NewRendezvous(hashName string) (*Rendezvous, error) {
+	hash, hashName, err := resolveHash(hashName)
+	if err != nil {
+		return nil, err
+	}
+	return &Rendezvous{nodes: newWeightedNodes(), hash: hash, hashName: hashName}, nil
+}
+
+// GetNode returns the node of highest score w / -ln(u), u uniform in (0, 1) for a key and node
+func (r *Rendezvous) GetNode(key string) (string, error) {
+	if len(r.nodes.sorted) == 0 {
+		return "", errors.New("rendezvous placement is empty")
+	}
+
+	keyHash := uint64(r.hash(key))
+	best, bestScore := "", math.Inf(-1)
+	for i, node := range r.nodes.sorted {
+		if score := r.score(keyHash, i); score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	return best, nil
+}
+
+// GetNodes returns the nodes of highest score for a key, up to n
+func (r *Rendezvous) GetNodes(key string, n int) ([]string, error) {
+	if len(r.nodes.sorted) == 0 {
+		return nil, errors.New("rendezvous placement is empty")
+	}
+
+	keyHash := uint64(r.hash(key))
+	scores := make(map[string]float64, len(r.nodes.sorted))
+	nodes := append([]string{}, r.nodes.sorted...)
+	for i, node := range r.nodes.sorted {
+		scores[node] = r.score(keyHash, i)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return scores[nodes[i]] > scores[nodes[j]]
+	})
+	return nodes[:min(n, len(nodes))], nil
+}
+
+// score is the weighted score of the sorted node i for a key hash
+func (r *Rendezvous) score(keyHash uint64, i int) float64 {
+	u := (float64(mix64(keyHash<<32^r.seeds[i])>>11) + 0.5) / (1 << 53)
+	return float64(r.nodes.weights[r.nodes.sorted[i]]) / -math.Log(u)
+}
+
+func (r *Rendezvous) AddNode(node string) {
+	r.AddWeightedNode(node, 1)
+}
+
+func (r *Rendezvous) AddWeightedNode(node string, weight int) {
+	if r.nodes.set(node, weight, true) {
+		r.updateSeeds()
+	}
+}
+
+func (r *Rendezvous) RemoveNode(node string) {
+	if r.nodes.remove(node) {
+		r.updateSeeds()
+	}
+}
+
+func (r *Rendezvous) SetWeight(node string, weight int) {
+	r.nodes.set(node, weight, false)
+}
+
+func (r *Rendezvous) Weight(node string) int {
+	return r.nodes.weights[node]
+}
+
+func (r *Rendezvous) ListNodes() []string {
+	return append([]string{}, r.nodes.sorted...)
+}
+
+func (r *Rendezvous) Config() PlacementConfig {
+	return PlacementConfig{Strategy: PlacementRendezvous, Hash: r.hashName}
+}
+
+func (r *Rendezvous) Copy() Placement {
+	return &Rendezvous{nodes: r.nodes.clone(), seeds: append([]uint64{}, r.seeds...), hash: r.hash, hashName: r.hashName}
+}
+
+func (r *Rendezvous) updateSeeds() {
+	r.seeds = make([]uint64, len(r.nodes.sorted))
+	for i, node := range r.nodes.sorted {
+		r.seeds[i] = uint64(r.hash(node))
+	}
+}
+
+// JumpHash assigns keys to buckets with jump consistent hash, a node of weight w holds w buckets
+// and the buckets follow the order of the node names. Lookups need no memory per node, but only
+// changes of the node sorting last move the minimum of keys, other changes renumber the buckets
+// after it.
+type JumpHash struct {
+	nodes    weightedNodes
+	buckets  []string
+	hash     HashFunc
+	hashName string
+}
+
+// NewJumpHash creates an empty jump consistent hash placement with the named hash function
+func NewJumpHash(hashName string) (*JumpHash, error) {
+	hash, hashName, err := resolveHash(hashName)
+	if err != nil {
+		return nil, err
+	}
+	return &JumpHash{nodes: newWeightedNodes(), hash: hash, hashName: hashName}, nil
+}
+
+// jumpBucket is the jump consistent hash of Lamping and Veach
+func jumpBucket(key uint64, buckets int) int {
+	b, j := int64(-1), int64(0)
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+func (jh *JumpHash) GetNode(key string) (string, error) {
+	if len(jh.buckets) == 0 {
+		return "", errors.New("jump hash placement is empty")
+	}
+	return jh.buckets[jumpBucket(mix64(uint64(jh.hash(key))), len(jh.buckets))], nil
+}
+
+// GetNodes returns the node of the bucket of a key followed by the nodes of the next buckets, up to n
+func (jh *JumpHash) GetNodes(key string, n int) ([]string, error) {
+	if len(jh.buckets) == 0 {
+		return nil, errors.New("jump hash placement is empty")
+	}
+
+	n = min(n, len(jh.nodes.sorted))
+	nodes := make([]string, 0, n)
+	start := jumpBucket(mix64(uint64(jh.hash(key))), len(jh.buckets))
+	for i := 0; i < len(jh.buckets) && len(nodes) < n; i++ {
+		if node := jh.buckets[(start+i)%len(jh.buckets)]; !contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+func (jh *JumpHash) AddNode(node string) {
+	jh.AddWeightedNode(node, 1)
+}
+
+func (jh *JumpHash) AddWeightedNode(node string, weight int) {
+	if jh.nodes.set(node, weight, true) {
+		jh.updateBuckets()
+	}
+}
+
+func (jh *JumpHash) RemoveNode(node string) {
+	if jh.nodes.remove(node) {
+		jh.updateBuckets()
+	}
+}
+
+func (jh *JumpHash) SetWeight(node string, weight int) {
+	if jh.nodes.set(node, weight, false) {
+		jh.updateBuckets()
+	}
+}
+
+func (jh *JumpHash) Weight(node string) int {
+	return jh.nodes.weights[node]
+}
+
+func (jh *JumpHash) ListNodes() []string {
+	return append([]string{}, jh.nodes.sorted...)
+}
+
+func (jh *JumpHash) Config() PlacementConfig {
+	return PlacementConfig{Strategy: PlacementJump, Hash: jh.hashName}
+}
+
+func (jh *JumpHash) Copy() Placement {
+	return &JumpHash{nodes: jh.nodes.clone(), buckets: append([]string{}, jh.buckets...), hash: jh.hash, hashName: jh.hashName}
+}
+
+func (jh *JumpHash) updateBuckets() {
+	jh.buckets = jh.buckets[:0:0]
+	for _, node := range jh.nodes.sorted {
+		for i := 0; i < jh.nodes.weights[node]; i++ {
+			jh.buckets = append(jh.buckets, node)
+		}
+	}
+}
@@ -3,20 +3,86 @@ package utils
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 )
 
+// Largest WAL or checkpoint line, list, set and hash operations are logged alone and their values
+// are checkpointed in chunks of checkpointChunkBytes
+const maxRecordBytes = 64 * 1024 * 1024
+
+const checkpointChunkBytes = 1024 * 1024
+
+var ErrCompacted = errors.New("LSN is no longer retained")
+
 // WALRecord represents a single operation in the WAL
 type WALRecord struct {
-	Operation string `json:"operation"` // "PUT" or "DELETE"
-	Key       string `json:"key"`
-	Value     []byte `json:"value,omitempty"` // Empty for "DELETE"
+	LSN       uint64 `json:"lsn,omitempty"`        // Node wide sequence number, also the version of the key
+	Operation string `json:"operation"`            // "PUT", "UPDATE", "INCR", "DELETE", "EVICT", "EXPIRE", "GRANT", "REVOKE", "PREPARE", "COMMIT", "ABORT", "TXN" or one of typedOps
+	Key       string `json:"key"`                  // Empty for lease and transaction records
+	Value     []byte `json:"value,omitempty"`      // Empty for "DELETE"
+	ExpiresAt int64  `json:"expires_at,omitempty"` // Unix nano, set by "PUT" with a TTL
+	Lease     int64  `json:"lease,omitempty"`      // Lease of the key, or the lease revoked by "REVOKE"
+	LeaseTTL  int64  `json:"lease_ttl,omitempty"`  // Seconds, set by "GRANT" whose LSN is the lease ID
+	Timestamp int64  `json:"ts,omitempty"`         // Unix nano of a write, compared between replicas
+	Moved     bool   `json:"moved,omitempty"`      // Set by the "DELETE" of a key moved to another node, it leaves no tombstone
+
+	// Writes of a versioned key deleted by "DELETE", kept by its tombstone
+	Clock VectorClock `json:"clock,omitempty"`
+
+	// Transaction of "PREPARE", "COMMIT" and "ABORT", the writes of a commit are logged before it as a "TXN"
+	Txn *PreparedTxn `json:"txn,omitempty"`
+
+	// Writes of a "TXN" applied all at once, they share its LSN
+	Group []WALRecord `json:"group,omitempty"`
+
+	// Whole value of a list, set, hash or versioned key written by "PUT", empty type for strings.
+	// Items and Fields also hold the values of typedOps, "HDEL" has the fields in Items.
+	Type     string            `json:"type,omitempty"`
+	Items    [][]byte          `json:"items,omitempty"`    // List elements or set members
+	Fields   map[string][]byte `json:"fields,omitempty"`   // Hash fields
+	Siblings []Sibling         `json:"siblings,omitempty"` // Concurrent values of a versioned key
+	Count    int               `json:"count,omitempty"`    // Values removed by "LPOP" and "RPOP"
 }
 
+// The last line of a checkpoint only carries the LSN of the table, entries carry their Version.
+// Leases come first as lines with only Lease and LeaseTTL, then prepared transactions with only Txn.
+// Tombstones follow the entries as lines with only Key, Timestamp, Deleted and Clock. The items and
+// fields of a list, set or hash that do not fit a line follow it on lines with Append set.
 type CheckPointRecord struct {
-	Key   string `json:"key"`
-	Value []byte `json:"value,omitempty"`
+	Key       string            `json:"key"`
+	Value     []byte            `json:"value,omitempty"`
+	ExpiresAt int64             `json:"expires_at,omitempty"`
+	Version   uint64            `json:"version,omitempty"`
+	Lease     int64             `json:"lease,omitempty"`
+	LeaseTTL  int64             `json:"lease_ttl,omitempty"`
+	Txn       *PreparedTxn      `json:"txn,omitempty"`
+	LSN       uint64            `json:"lsn,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Items     [][]byte          `json:"items,omitempty"`
+	Fields    map[string][]byte `json:"fields,omitempty"`
+	Siblings  []Sibling         `json:"siblings,omitempty"`
+	Timestamp int64             `json:"ts,omitempty"`
+	Deleted   bool              `json:"deleted,omitempty"`
+	Clock     VectorClock       `json:"clock,omitempty"`
+	Append    bool              `json:"append,omitempty"`
+}
+
+// restore puts a record holding a value of any type on the table without logging it
+func restore(ht *HashTable, key string, value []byte, expiresAt, lease int64, valueType string, items [][]byte, fields map[string][]byte, siblings []Sibling) error {
+	parsed, err := ParseValueType(valueType)
+	if err != nil {
+		return err
+	}
+	if parsed == TypeString {
+		ht.PutWithOptions(key, value, PutOptions{ExpiresAt: expiresAt, Lease: lease}, nil)
+		return nil
+	}
+	ht.putTyped(key, parsed, items, fields, siblings, expiresAt)
+	return nil
 }
 
 type CheckpointInfo struct {
@@ -24,9 +90,31 @@ type CheckpointInfo struct {
 	CheckPointFile string
 	WC             chan WALRecord // WAL Channel
 	TC             chan struct{}
+	SC             chan chan error // Sync requests, answered once the records sent before are on disk
+}
+
+// Sync waits until every record sent to the WAL before is written and synced to the file.
+// Returns right away when no WAL writer takes sync requests.
+func (rInfo *CheckpointInfo) Sync() error {
+	if nil == rInfo || nil == rInfo.SC {
+		return nil
+	}
+	done := make(chan error)
+	rInfo.SC <- done
+	return <-done
+}
+
+func newRecordScanner(file io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordBytes)
+	return scanner
 }
 
 func CheckpointRestore(ht *HashTable, checkpointFile *string, walFile *string) error {
+	defer ht.endReplay()
+
+	// Operations of lists, sets and hashes the checkpoint already holds are not applied again
+	var checkpointLSN uint64
 	if nil != checkpointFile {
 		chkpt, err := os.OpenFile(*checkpointFile, os.O_RDONLY, 0644)
 		if err != nil {
@@ -35,13 +123,40 @@ func CheckpointRestore(ht *HashTable, checkpointFile *string, walFile *string) e
 		defer chkpt.Close()
 
 		chkpt.Seek(0, 0)
-		scanner := bufio.NewScanner(chkpt)
+		scanner := newRecordScanner(chkpt)
 		for scanner.Scan() {
 			var record CheckPointRecord
 			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
 				return err
 			}
-			ht.Put(record.Key, record.Value, nil)
+			if record.LSN != 0 {
+				ht.replayAt(record.LSN)
+				checkpointLSN = record.LSN
+				continue
+			}
+			if record.Append {
+				if err := ht.appendTyped(record.Key, record.Items, record.Fields); err != nil {
+					return err
+				}
+				continue
+			}
+			if record.LeaseTTL != 0 {
+				ht.restoreLease(record.Lease, record.LeaseTTL)
+				continue
+			}
+			if nil != record.Txn {
+				ht.restoreTxn(record.Txn)
+				continue
+			}
+			if record.Deleted {
+				ht.restoreStamp(WALRecord{Operation: "DELETE", Key: record.Key, Timestamp: record.Timestamp, Clock: record.Clock})
+				continue
+			}
+			ht.replayAt(record.Version)
+			if err := restore(ht, record.Key, record.Value, record.ExpiresAt, record.Lease, record.Type, record.Items, record.Fields, record.Siblings); err != nil {
+				return err
+			}
+			ht.restoreStamp(WALRecord{Operation: "PUT", Key: record.Key, Timestamp: record.Timestamp})
 		}
 		if nil != scanner.Err() {
 			return scanner.Err()
@@ -56,32 +171,83 @@ func CheckpointRestore(ht *HashTable, checkpointFile *string, walFile *string) e
 		defer wal.Close()
 
 		wal.Seek(0, 0)
-		scanner := bufio.NewScanner(wal)
+		scanner := newRecordScanner(wal)
 
 		for scanner.Scan() {
 			var record WALRecord
 			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
 				return err
 			}
-
-			switch record.Operation {
-			case "PUT":
-				// Validate
-				ht.Put(record.Key, record.Value, nil)
-			case "DELETE":
-				// Validate
-				ht.Delete(record.Key, nil)
-			case "UPDATE":
-				// Validate
-				ht.Update(record.Key, record.Value, nil)
+			if typedOps[record.Operation] && record.LSN <= checkpointLSN {
+				continue
 			}
-		}
 
+			applyWALRecord(ht, record)
+		}
+		if nil != scanner.Err() {
+			return scanner.Err()
+		}
 	}
 	return nil
 }
 
+// applyWALRecord replays a single WAL record on the table without logging it again
+func applyWALRecord(ht *HashTable, record WALRecord) {
+	ht.replayAt(record.LSN)
+
+	switch record.Operation {
+	case "PUT", "INCR":
+		// Validate
+		restore(ht, record.Key, record.Value, record.ExpiresAt, record.Lease, record.Type, record.Items, record.Fields, record.Siblings)
+	case "GRANT":
+		ht.GrantLease(record.LeaseTTL, nil)
+	case "REVOKE":
+		// The keys of the lease were logged before it
+		ht.RevokeLease(record.Lease, nil)
+	case "PREPARE":
+		ht.restoreTxn(record.Txn)
+	case "COMMIT", "ABORT":
+		ht.finishTxn(record.Txn.ID)
+	case "TXN":
+		for _, change := range record.Changes() {
+			applyWALRecord(ht, change)
+		}
+	case "DELETE", "EXPIRE":
+		// Validate
+		ht.Delete(record.Key, nil)
+	case "EVICT":
+		ht.Delete(record.Key, nil)
+		ht.mtx.Lock()
+		ht.evictions++
+		ht.mtx.Unlock()
+	case "UPDATE":
+		// Validate
+		ht.Update(record.Key, record.Value, nil)
+	case "LPUSH", "RPUSH":
+		ht.ListPush(record.Key, record.Items, record.Operation == "LPUSH", nil)
+	case "LPOP", "RPOP":
+		ht.ListPop(record.Key, record.Count, record.Operation == "LPOP", nil)
+	case "SADD":
+		ht.SetAdd(record.Key, record.Items, nil)
+	case "SREM":
+		ht.SetRemove(record.Key, record.Items, nil)
+	case "HSET":
+		for field, value := range record.Fields {
+			ht.HashSet(record.Key, field, value, nil)
+		}
+	case "HDEL":
+		fields := make([]string, 0, len(record.Items))
+		for _, field := range record.Items {
+			fields = append(fields, string(field))
+		}
+		ht.HashDelete(record.Key, fields, nil)
+	}
+	ht.restoreStamp(record)
+}
+
 func RecoverFromWAL(ht *HashTable, walFile string) error {
+	defer ht.endReplay()
+
 	wal, err := os.OpenFile(walFile, os.O_RDONLY, 0644)
 	if err != nil {
 		return fmt.Errorf("Error opening WAL file : %w", err)
@@ -89,7 +255,7 @@ func RecoverFromWAL(ht *HashTable, walFile string) error {
 	defer wal.Close()
 
 	wal.Seek(0, 0)
-	scanner := bufio.NewScanner(wal)
+	scanner := newRecordScanner(wal)
 
 	for scanner.Scan() {
 		var record WALRecord
@@ -97,21 +263,81 @@ func RecoverFromWAL(ht *HashTable, walFile string) error {
 			return err
 		}
 
-		switch record.Operation {
-		case "PUT":
-			// Validate
-			ht.Put(record.Key, record.Value, nil)
-		case "DELETE":
-			// Validate
-			ht.Delete(record.Key, nil)
-		case "UPDATE":
-			// Validate
-			ht.Update(record.Key, record.Value, nil)
-		}
+		applyWALRecord(ht, record)
 	}
 	return scanner.Err()
 }
 
+// ReadWAL returns the records of the WAL file from fromLSN up to toLSN excluded.
+// Fails with ErrCompacted when the file does not hold every record of the interval,
+// the WAL is truncated on checkpoints and flushed periodically.
+func ReadWAL(walFile string, fromLSN, toLSN uint64) ([]WALRecord, error) {
+	records := []WALRecord{}
+	if fromLSN >= toLSN {
+		return records, nil
+	}
+
+	wal, err := os.OpenFile(walFile, os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening WAL file : %w", err)
+	}
+	defer wal.Close()
+
+	next := fromLSN
+	scanner := newRecordScanner(wal)
+	for scanner.Scan() {
+		var record WALRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+		if record.LSN < next {
+			continue
+		}
+		if record.LSN >= toLSN {
+			break
+		}
+		if record.LSN != next {
+			return nil, fmt.Errorf("%w: %d", ErrCompacted, next)
+		}
+		records = append(records, record)
+		next++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if next != toLSN {
+		return nil, fmt.Errorf("%w: %d", ErrCompacted, next)
+	}
+	return records, nil
+}
+
+// checkpointChunks splits the items or fields of a value in lines of about checkpointChunkBytes,
+// every line but the first has Append set. There is one line at least.
+func checkpointChunks(key string, items [][]byte, fields map[string][]byte) []CheckPointRecord {
+	chunks := []CheckPointRecord{{}}
+	size := 0
+	next := func(length int) *CheckPointRecord {
+		if size > 0 && size+length > checkpointChunkBytes {
+			chunks = append(chunks, CheckPointRecord{Key: key, Append: true})
+			size = 0
+		}
+		size += length
+		return &chunks[len(chunks)-1]
+	}
+	for _, item := range items {
+		chunk := next(len(item))
+		chunk.Items = append(chunk.Items, item)
+	}
+	for field, value := range fields {
+		chunk := next(len(field) + len(value))
+		if nil == chunk.Fields {
+			chunk.Fields = make(map[string][]byte)
+		}
+		chunk.Fields[field] = value
+	}
+	return chunks
+}
+
 func TakeCheckpoint(ht *HashTable, rInfo *CheckpointInfo) error {
 	ht.mtx.RLock()
 	defer ht.mtx.RUnlock()
@@ -123,32 +349,102 @@ func TakeCheckpoint(ht *HashTable, rInfo *CheckpointInfo) error {
 	defer checkpointFile.Close()
 
 	writer := bufio.NewWriter(checkpointFile)
+	var data []byte
+
+	// Leases before the keys attached to them
+	for _, lease := range ht.leases {
+		data, err = json.Marshal(CheckPointRecord{Lease: lease.ID, LeaseTTL: lease.TTLSeconds})
+		if err != nil {
+			return fmt.Errorf("Error in marshilling lease: %v", err)
+		}
+		writer.Write(append(data, '\n'))
+	}
+	for _, txn := range ht.prepared {
+		data, err = json.Marshal(CheckPointRecord{Txn: txn})
+		if err != nil {
+			return fmt.Errorf("Error in marshilling transaction: %v", err)
+		}
+		writer.Write(append(data, '\n'))
+	}
+
 	// Iterate over the hash table
 	for _, bucket := range ht.buckets {
-		stack := []*TreeNode{}
-		current := bucket.root
-
-		for current != nil || len(stack) > 0 {
-			// Go to the leftmost node
-			for current != nil {
-				stack = append(stack, current)
-				current = current.left
+		bucket.walk(func(node *TreeNode) bool {
+			record := CheckPointRecord{Key: node.entry.Key, Value: node.entry.Value, ExpiresAt: node.entry.ExpiresAt, Version: node.entry.Version, Lease: node.entry.Lease, Timestamp: ht.merkle.stamps[node.entry.Key].Timestamp}
+			chunks := []CheckPointRecord{}
+			if node.entry.Type != TypeString {
+				record.Type = node.entry.Type.String()
+				record.Siblings = node.entry.Siblings
+				chunks = checkpointChunks(node.entry.Key, node.entry.items(), node.entry.Hash)
+				record.Items, record.Fields = chunks[0].Items, chunks[0].Fields
+				chunks = chunks[1:]
 			}
-
-			// Pop from stack and process node
-			current = stack[len(stack)-1]
-			stack = stack[:len(stack)-1]
-
-			data, err := json.Marshal(CheckPointRecord{Key: current.entry.Key, Value: current.entry.Value})
-			if err != nil {
-				return fmt.Errorf("Error in marshilling wal log: %v", err)
+			for _, line := range append([]CheckPointRecord{record}, chunks...) {
+				data, err = json.Marshal(line)
+				if err != nil {
+					err = fmt.Errorf("Error in marshilling wal log: %v", err)
+					return false
+				}
+				writer.Write(append(data, '\n'))
 			}
-			writer.Write(append(data, '\n'))
 			writer.Flush()
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-			// Move to the right node
-			current = current.right
+	for key, stamp := range ht.merkle.stamps {
+		if !stamp.Deleted {
+			continue
+		}
+		data, err = json.Marshal(CheckPointRecord{Key: key, Timestamp: stamp.Timestamp, Deleted: true, Clock: stamp.Clock})
+		if err != nil {
+			return fmt.Errorf("Error in marshilling tombstone: %v", err)
 		}
+		writer.Write(append(data, '\n'))
+	}
+
+	data, err = json.Marshal(CheckPointRecord{LSN: ht.lsn})
+	if err != nil {
+		return fmt.Errorf("Error in marshilling checkpoint LSN: %v", err)
+	}
+	writer.Write(append(data, '\n'))
+	return writer.Flush()
+}
+
+// WriteSynced replaces a file with data, the file and the directory are synced before it returns
+func WriteSynced(path string, what string, data []byte) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("Error creating %s : %w", what, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("Error writing %s : %w", what, err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("Error syncing %s : %w", what, err)
+	}
+	file.Close()
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("Error replacing %s : %w", what, err)
+	}
+	return SyncDir(path)
+}
+
+// SyncDir syncs the directory of a file, a file created or renamed in it survives a crash
+func SyncDir(path string) error {
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("Error opening directory : %w", err)
+	}
+	defer dir.Close()
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("Error syncing directory : %w", err)
 	}
 	return nil
 }
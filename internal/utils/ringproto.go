@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"fmt"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+// ToProto serializes the ring with its version, to be sent to the nodes and the clients
+func (s *RingSnapshot) ToProto() *pb.StorageRing {
+	ring := &pb.StorageRing{
+		Version:      s.version,
+		VirtualNodes: int64(s.virtualNodes),
+		Hash:         s.hashName,
+		LoadFactor:   s.loadFactor,
+		Weights:      make(map[string]int64, len(s.nodes)),
+		Tokens:       make([]*pb.StorageRingToken, 0, len(s.hashSortedKeys)),
+	}
+	for node, weight := range s.nodes {
+		ring.Weights[node] = int64(weight)
+	}
+	for _, key := range s.hashSortedKeys {
+		ring.Tokens = append(ring.Tokens, &pb.StorageRingToken{Position: key, Node: s.hashRing[key]})
+	}
+	return ring
+}
+
+// RingFromProto rebuilds a serialized ring at its version, it places keys as the ring it was
+// serialized from
+func RingFromProto(ring *pb.StorageRing) (*ConsistentHash, error) {
+	if nil == ring {
+		return nil, fmt.Errorf("Empty ring")
+	}
+	hash, hashName, err := resolveHash(ring.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if ring.LoadFactor != 0 && ring.LoadFactor < 1 {
+		return nil, fmt.Errorf("Invalid load factor %v", ring.LoadFactor)
+	}
+
+	ch := newConsistentHash(int(ring.VirtualNodes), hashName, hash, ring.LoadFactor)
+	s := ch.Snapshot()
+	s.version = ring.Version
+	for node, weight := range ring.Weights {
+		s.nodes[node] = max(int(weight), 1)
+	}
+	for _, token := range ring.Tokens {
+		if _, ok := s.nodes[token.Node]; !ok {
+			return nil, fmt.Errorf("Token %d of unknown node %s", token.Position, token.Node)
+		}
+		s.hashRing[token.Position] = token.Node
+	}
+	s.updateSortedKeys()
+	return ch, nil
+}
+
+// PlacementToProto serializes a placement that is not a ring, its keys are placed from the weights
+// of the nodes alone
+func PlacementToProto(p Placement) *pb.StoragePlacement {
+	config := p.Config()
+	placement := &pb.StoragePlacement{Strategy: config.Strategy, Hash: config.Hash, Weights: make(map[string]int64)}
+	for _, nodeID := range p.ListNodes() {
+		placement.Weights[nodeID] = int64(p.Weight(nodeID))
+	}
+	return placement
+}
+
+// PlacementFromProto rebuilds a serialized placement that is not a ring
+func PlacementFromProto(placement *pb.StoragePlacement) (Placement, error) {
+	if nil == placement {
+		return nil, fmt.Errorf("Empty placement")
+	}
+	p, err := NewPlacement(PlacementConfig{Strategy: placement.Strategy, Hash: placement.Hash})
+	if err != nil {
+		return nil, err
+	}
+	for node, weight := range placement.Weights {
+		p.AddWeightedNode(node, int(weight))
+	}
+	return p, nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnknownIndex = errors.New("unknown index")
+
+// SecondaryIndex maps the value of a JSON field to the keys holding it
+type SecondaryIndex struct {
+	Name string
+	Path string // Dotted path into the JSON document, e.g. profile.email
+
+	fields  []string
+	entries map[string]map[string]struct{} // field value -> set of keys
+}
+
+func newSecondaryIndex(name, path string) (*SecondaryIndex, error) {
+	if name == "" || path == "" {
+		return nil, fmt.Errorf("Index name and path cannot be empty")
+	}
+	fields := strings.Split(strings.TrimPrefix(path, "$."), ".")
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("Invalid index path: %s", path)
+		}
+	}
+	return &SecondaryIndex{
+		Name:    name,
+		Path:    path,
+		fields:  fields,
+		entries: make(map[string]map[string]struct{}),
+	}, nil
+}
+
+// extract returns the indexed field of a JSON value, false if the value is not JSON or the field is not a scalar
+func (idx *SecondaryIndex) extract(value []byte) (string, bool) {
+	var document interface{}
+	if err := json.Unmarshal(value, &document); err != nil {
+		return "", false
+	}
+
+	for _, field := range idx.fields {
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if document, ok = object[field]; !ok {
+			return "", false
+		}
+	}
+
+	switch field := document.(type) {
+	case string:
+		return field, true
+	case float64:
+		return strconv.FormatFloat(field, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(field), true
+	}
+	return "", false
+}
+
+func (idx *SecondaryIndex) add(key string, value []byte) {
+	field, ok := idx.extract(value)
+	if !ok {
+		return
+	}
+	if nil == idx.entries[field] {
+		idx.entries[field] = make(map[string]struct{})
+	}
+	idx.entries[field][key] = struct{}{}
+}
+
+func (idx *SecondaryIndex) remove(key string, value []byte) {
+	field, ok := idx.extract(value)
+	if !ok {
+		return
+	}
+	delete(idx.entries[field], key)
+	if len(idx.entries[field]) == 0 {
+		delete(idx.entries, field)
+	}
+}
+
+// AddIndex declares a secondary index and builds it over the keys already present
+func (ht *HashTable) AddIndex(name, path string) error {
+	idx, err := newSecondaryIndex(name, path)
+	if err != nil {
+		return err
+	}
+
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	for _, existing := range ht.indexes {
+		if existing.Name == name {
+			return fmt.Errorf("Index %s already exists", name)
+		}
+	}
+
+	for _, bucket := range ht.buckets {
+		bucket.walk(func(node *TreeNode) bool {
+			idx.add(node.entry.Key, node.entry.Value)
+			return true
+		})
+	}
+	ht.indexes = append(ht.indexes, idx)
+	return nil
+}
+
+// reindex moves a key between index entries when its value changes. Caller must hold the write lock.
+// A nil oldValue is an insert and a nil newValue is a delete.
+func (ht *HashTable) reindex(key string, oldValue, newValue []byte) {
+	for _, idx := range ht.indexes {
+		if nil != oldValue {
+			idx.remove(key, oldValue)
+		}
+		if nil != newValue {
+			idx.add(key, newValue)
+		}
+	}
+}
+
+// QueryIndex returns up to limit entries, in key order, whose indexed field equals value. A limit of 0 returns every match.
+func (ht *HashTable) QueryIndex(name, value string, limit int) ([]Entry, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	var idx *SecondaryIndex
+	for _, existing := range ht.indexes {
+		if existing.Name == name {
+			idx = existing
+		}
+	}
+	if nil == idx {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIndex, name)
+	}
+
+	keys := make([]string, 0, len(idx.entries[value]))
+	for key := range idx.entries[value] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := time.Now().UnixNano()
+	entries := []Entry{}
+	for _, key := range keys {
+		if limit > 0 && len(entries) >= limit {
+			break
+		}
+		node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+		if !isFound || isExpired(&node.entry, now) {
+			continue
+		}
+		copied := make([]byte, len(node.entry.Value))
+		copy(copied, node.entry.Value)
+		entries = append(entries, Entry{Key: key, Value: copied, ExpiresAt: node.entry.ExpiresAt, Version: node.entry.Version})
+	}
+	return entries, nil
+}
@@ -0,0 +1,307 @@
+package utils
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Member states of the gossip membership
+const (
+	MemberAlive   = "ALIVE"
+	MemberSuspect = "SUSPECT"
+	MemberDead    = "DEAD"
+)
+
+// Member is what a node knows about another one. A higher incarnation is newer, only the member
+// itself increments it, to refute a suspicion.
+type Member struct {
+	ID          string
+	Address     string
+	State       string
+	Incarnation uint64
+}
+
+type memberState struct {
+	Member
+	suspectedAt time.Time
+}
+
+// update is a membership change piggybacked on the gossip messages until it was sent enough times
+type update struct {
+	member Member
+	sent   int
+}
+
+// Membership is the SWIM view of the cluster held by a node. Members are probed in a shuffled
+// round robin, a member that does not answer is suspected and declared dead once the suspicion
+// timeout expires without the member refuting it.
+type Membership struct {
+	mtx              sync.Mutex
+	self             string
+	members          map[string]*memberState
+	updates          []*update
+	probeOrder       []string
+	probeIndex       int
+	suspicionTimeout time.Duration
+}
+
+// NewMembership creates the membership of the node id reachable at address
+func NewMembership(id, address string, suspicionTimeout time.Duration) *Membership {
+	membership := &Membership{
+		self:             id,
+		members:          make(map[string]*memberState),
+		suspicionTimeout: suspicionTimeout,
+	}
+	self := Member{ID: id, Address: address, State: MemberAlive}
+	membership.members[id] = &memberState{Member: self}
+	return membership
+}
+
+// Self returns the member of the node holding the membership
+func (m *Membership) Self() Member {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.members[m.self].Member
+}
+
+// Members returns every known member sorted by ID, dead ones included
+func (m *Membership) Members() []Member {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	members := make([]Member, 0, len(m.members))
+	for _, member := range m.members {
+		members = append(members, member.Member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+	return members
+}
+
+// Live returns the alive and suspected members sorted by ID, they are the nodes of the ring
+func (m *Membership) Live() []Member {
+	live := []Member{}
+	for _, member := range m.Members() {
+		if member.State != MemberDead {
+			live = append(live, member)
+		}
+	}
+	return live
+}
+
+// Version identifies the live members, every node with the same view reports the same version
+func (m *Membership) Version() uint64 {
+	return MembershipVersion(m.Live())
+}
+
+// MembershipVersion hashes the IDs and addresses of the live members
+func MembershipVersion(members []Member) uint64 {
+	live := make([]Member, 0, len(members))
+	for _, member := range members {
+		if member.State != MemberDead {
+			live = append(live, member)
+		}
+	}
+	sort.Slice(live, func(i, j int) bool {
+		return live[i].ID < live[j].ID
+	})
+
+	hash := fnv.New64a()
+	for _, member := range live {
+		hash.Write([]byte(member.ID))
+		hash.Write([]byte{0})
+		hash.Write([]byte(member.Address))
+		hash.Write([]byte{0})
+	}
+	return hash.Sum64()
+}
+
+// Apply merges the members received from another node. Newer incarnations win, for the same
+// incarnation dead overrides suspect which overrides alive. A suspicion of the node itself is
+// refuted with a higher incarnation. Returns the number of members that changed.
+func (m *Membership) Apply(members []Member) int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	changed := 0
+	for _, member := range members {
+		if m.apply(member) {
+			changed++
+		}
+	}
+	return changed
+}
+
+// apply merges one member. Caller must hold mtx.
+func (m *Membership) apply(member Member) bool {
+	if member.ID == "" {
+		return false
+	}
+	current, ok := m.members[member.ID]
+	if member.ID == m.self {
+		if member.State != MemberAlive && member.Incarnation >= current.Incarnation {
+			// The node always sends itself, the new incarnation spreads with the next message
+			current.Incarnation = member.Incarnation + 1
+			return true
+		}
+		return false
+	}
+	if !ok {
+		m.members[member.ID] = &memberState{Member: member, suspectedAt: time.Now()}
+		m.broadcast(member)
+		return true
+	}
+
+	if !overrides(member, current.Member) {
+		return false
+	}
+	if member.State == MemberSuspect && current.State != MemberSuspect {
+		current.suspectedAt = time.Now()
+	}
+	current.Member = member
+	m.broadcast(member)
+	return true
+}
+
+// overrides reports whether update is newer than current
+func overrides(update, current Member) bool {
+	switch update.State {
+	case MemberAlive:
+		return update.Incarnation > current.Incarnation
+	case MemberSuspect:
+		if current.State == MemberAlive {
+			return update.Incarnation >= current.Incarnation
+		}
+		return update.Incarnation > current.Incarnation
+	case MemberDead:
+		if current.State == MemberDead {
+			return false
+		}
+		return update.Incarnation >= current.Incarnation
+	}
+	return false
+}
+
+// Suspect marks a member that did not answer a probe, it is declared dead unless it refutes
+// the suspicion before the timeout
+func (m *Membership) Suspect(id string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	current, ok := m.members[id]
+	if !ok || id == m.self || current.State != MemberAlive {
+		return
+	}
+	m.apply(Member{ID: id, Address: current.Address, State: MemberSuspect, Incarnation: current.Incarnation})
+}
+
+// ExpireSuspects declares dead the members suspected for longer than the suspicion timeout and
+// returns their IDs
+func (m *Membership) ExpireSuspects(now time.Time) []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	dead := []string{}
+	for id, current := range m.members {
+		if current.State == MemberSuspect && now.Sub(current.suspectedAt) >= m.suspicionTimeout {
+			current.State = MemberDead
+			m.broadcast(current.Member)
+			dead = append(dead, id)
+		}
+	}
+	sort.Strings(dead)
+	return dead
+}
+
+// NextProbe returns the next member to probe. Every live member is probed once per round, in an
+// order shuffled at the start of the round.
+func (m *Membership) NextProbe() (Member, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for attempt := 0; attempt < 2; attempt++ {
+		for m.probeIndex < len(m.probeOrder) {
+			member, ok := m.members[m.probeOrder[m.probeIndex]]
+			m.probeIndex++
+			if ok && member.State != MemberDead {
+				return member.Member, true
+			}
+		}
+
+		m.probeOrder = m.probeOrder[:0]
+		for id, member := range m.members {
+			if id != m.self && member.State != MemberDead {
+				m.probeOrder = append(m.probeOrder, id)
+			}
+		}
+		rand.Shuffle(len(m.probeOrder), func(i, j int) {
+			m.probeOrder[i], m.probeOrder[j] = m.probeOrder[j], m.probeOrder[i]
+		})
+		m.probeIndex = 0
+	}
+	return Member{}, false
+}
+
+// RandomMembers returns up to k random live members other than the node and exclude, used for
+// the indirect probes
+func (m *Membership) RandomMembers(k int, exclude string) []Member {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	candidates := []Member{}
+	for id, member := range m.members {
+		if id != m.self && id != exclude && member.State == MemberAlive {
+			candidates = append(candidates, member.Member)
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+	return candidates
+}
+
+// Piggyback returns up to max updates to send with the next message, the least sent ones first.
+// The node itself always comes first so receivers learn it. An update is sent about 3 log(n) times.
+func (m *Membership) Piggyback(max int) []Member {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	members := []Member{m.members[m.self].Member}
+	sort.SliceStable(m.updates, func(i, j int) bool {
+		return m.updates[i].sent < m.updates[j].sent
+	})
+	limit := 3 * int(math.Ceil(math.Log2(float64(len(m.members)+1))))
+
+	kept := m.updates[:0]
+	for _, u := range m.updates {
+		if len(members) < max {
+			members = append(members, u.member)
+			u.sent++
+		}
+		if u.sent < limit {
+			kept = append(kept, u)
+		}
+	}
+	m.updates = kept
+	return members
+}
+
+// broadcast queues a member to be piggybacked, replacing an older update of the same member. Caller must hold mtx.
+func (m *Membership) broadcast(member Member) {
+	for _, u := range m.updates {
+		if u.member.ID == member.ID {
+			u.member, u.sent = member, 0
+			return
+		}
+	}
+	m.updates = append(m.updates, &update{member: member})
+}
@@ -0,0 +1,386 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTxnConflict = errors.New("key is locked by a prepared transaction")
+
+// Compare is a condition of a transaction on the existence, version or value of a key.
+// A missing key has version 0 and fails every value compare. EXISTS only supports "=" for a
+// present key and "!=" for a missing one.
+type Compare struct {
+	Key     string `json:"key"`
+	Target  string `json:"target"` // "EXISTS", "VERSION" or "VALUE"
+	Result  string `json:"result"` // "=", "!=", "<" or ">"
+	Version uint64 `json:"version,omitempty"`
+	Value   []byte `json:"value,omitempty"`
+}
+
+// TxnOp is a single operation of a transaction branch
+type TxnOp struct {
+	Type  string `json:"type"` // "PUT", "DELETE" or "GET"
+	Key   string `json:"key"`
+	Value []byte `json:"value,omitempty"`
+}
+
+// TxnOpResult is the outcome of a TxnOp, Found is the presence of the key for GET and DELETE
+type TxnOpResult struct {
+	Key     string
+	Found   bool
+	Value   []byte
+	Version uint64
+}
+
+// PreparedTxn is the part of a transaction held by this node between prepare and commit.
+// Its keys are locked against every other writer until it is committed or aborted.
+type PreparedTxn struct {
+	ID        string   `json:"id"`
+	Then      []TxnOp  `json:"then,omitempty"`
+	Else      []TxnOp  `json:"else,omitempty"`
+	Keys      []string `json:"keys,omitempty"`
+	Succeeded bool     `json:"succeeded,omitempty"` // Local result of the compares
+	Reserved  int64    `json:"reserved,omitempty"`  // Memory held for its writes until the decision
+}
+
+func validateTxn(compares []Compare, branches ...[]TxnOp) error {
+	for _, compare := range compares {
+		if compare.Target != "EXISTS" && compare.Target != "VERSION" && compare.Target != "VALUE" {
+			return fmt.Errorf("Invalid compare target: %s", compare.Target)
+		}
+		if compare.Target == "EXISTS" && compare.Result != "=" && compare.Result != "!=" {
+			return fmt.Errorf("EXISTS only compares with = or !=")
+		}
+		switch compare.Result {
+		case "=", "!=", "<", ">":
+		default:
+			return fmt.Errorf("Invalid compare result: %s", compare.Result)
+		}
+	}
+	for _, ops := range branches {
+		for _, op := range ops {
+			if op.Type != "PUT" && op.Type != "DELETE" && op.Type != "GET" {
+				return fmt.Errorf("Invalid transaction operation: %s", op.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUnlocked fails writes to keys of prepared transactions. Caller must hold the lock.
+func (ht *HashTable) checkUnlocked(key string) error {
+	if ht.replaying {
+		return nil
+	}
+	if id, ok := ht.locks[key]; ok {
+		return fmt.Errorf("%w: %s by %s", ErrTxnConflict, key, id)
+	}
+	return nil
+}
+
+// evaluate checks a compare against the current state. Caller must hold the lock.
+func (ht *HashTable) evaluate(compare Compare) bool {
+	node, isFound := ht.buckets[hashKey(compare.Key, ht.bucketSize)].search(compare.Key)
+	if isFound && isExpired(&node.entry, time.Now().UnixNano()) {
+		isFound = false
+	}
+
+	var order int
+	switch compare.Target {
+	case "EXISTS":
+		if !isFound {
+			order = 1
+		}
+	case "VERSION":
+		var version uint64
+		if isFound {
+			version = node.entry.Version
+		}
+		switch {
+		case version < compare.Version:
+			order = -1
+		case version > compare.Version:
+			order = 1
+		}
+	case "VALUE":
+		if !isFound || node.entry.Type != TypeString {
+			return false
+		}
+		order = bytes.Compare(node.entry.Value, compare.Value)
+	}
+
+	switch compare.Result {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case ">":
+		return order > 0
+	}
+	return false
+}
+
+// PrepareTxn evaluates the compares, locks every key of the transaction on this node and holds
+// the memory its writes need. Returns whether the compares held, fails with ErrTxnConflict when a
+// key is locked by another transaction and with ErrOutOfMemory when the writes don't fit.
+// Preparing the same ID twice returns the first result.
+func (ht *HashTable) PrepareTxn(id string, compares []Compare, then, els []TxnOp, RInfo *CheckpointInfo) (bool, error) {
+	if err := validateTxn(compares, then, els); err != nil {
+		return false, err
+	}
+
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if txn, ok := ht.prepared[id]; ok {
+		return txn.Succeeded, nil
+	}
+
+	seen := make(map[string]struct{})
+	keys := []string{}
+	addKey := func(key string) {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	for _, compare := range compares {
+		addKey(compare.Key)
+	}
+	for _, op := range append(append([]TxnOp{}, then...), els...) {
+		addKey(op.Key)
+	}
+	for _, key := range keys {
+		if err := ht.checkUnlocked(key); err != nil {
+			return false, err
+		}
+	}
+
+	succeeded := true
+	for _, compare := range compares {
+		if !ht.evaluate(compare) {
+			succeeded = false
+			break
+		}
+	}
+
+	// Keys are locked first so making room for the writes does not evict them, either branch
+	// may be decided
+	txn := &PreparedTxn{ID: id, Then: then, Else: els, Keys: keys, Succeeded: succeeded}
+	ht.lockTxn(txn)
+	growth := max(ht.growth(then), ht.growth(els))
+	if err := ht.reserve(growth, "", RInfo); err != nil {
+		ht.unlockTxn(txn)
+		return false, err
+	}
+	txn.Reserved = growth
+	ht.reserved += growth
+
+	ht.record(RInfo, WALRecord{Operation: "PREPARE", Txn: txn})
+	return succeeded, nil
+}
+
+// lockTxn makes txn prepared. Caller must hold the lock.
+func (ht *HashTable) lockTxn(txn *PreparedTxn) {
+	ht.prepared[txn.ID] = txn
+	ht.reserved += txn.Reserved
+	for _, key := range txn.Keys {
+		ht.locks[key] = txn.ID
+	}
+}
+
+// unlockTxn releases the keys and the memory of a prepared transaction. Caller must hold the lock.
+func (ht *HashTable) unlockTxn(txn *PreparedTxn) {
+	ht.reserved -= txn.Reserved
+	for _, key := range txn.Keys {
+		if ht.locks[key] == txn.ID {
+			delete(ht.locks, key)
+		}
+	}
+	delete(ht.prepared, txn.ID)
+}
+
+// CommitTxn applies the Then operations of a prepared transaction when succeeded is set and the
+// Else operations otherwise, then releases its keys. Committing an unknown ID does nothing so the
+// coordinator can retry after a crash.
+func (ht *HashTable) CommitTxn(id string, succeeded bool, RInfo *CheckpointInfo) ([]TxnOpResult, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	txn, ok := ht.prepared[id]
+	if !ok {
+		return []TxnOpResult{}, nil
+	}
+
+	ops := txn.Else
+	if succeeded {
+		ops = txn.Then
+	}
+
+	// The writes take the memory held since prepare, no other writer runs until they are applied
+	ht.unlockTxn(txn)
+	results, err := ht.applyGroup(ops, RInfo)
+	ht.record(RInfo, WALRecord{Operation: "COMMIT", Txn: &PreparedTxn{ID: id}})
+	return results, err
+}
+
+// AbortTxn releases the keys of a prepared transaction without applying anything
+func (ht *HashTable) AbortTxn(id string, RInfo *CheckpointInfo) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	txn, ok := ht.prepared[id]
+	if !ok {
+		return
+	}
+	ht.record(RInfo, WALRecord{Operation: "ABORT", Txn: &PreparedTxn{ID: id}})
+	ht.unlockTxn(txn)
+}
+
+// applyOps runs transaction operations in order. Returns the first error but applies every
+// operation. Caller must hold the lock.
+func (ht *HashTable) applyOps(ops []TxnOp, RInfo *CheckpointInfo) ([]TxnOpResult, error) {
+	var firstErr error
+	results := make([]TxnOpResult, 0, len(ops))
+	for _, op := range ops {
+		result := TxnOpResult{Key: op.Key}
+		switch op.Type {
+		case "PUT":
+			_, err := ht.put(op.Key, op.Value, PutOptions{}, RInfo)
+			if err != nil && nil == firstErr {
+				firstErr = err
+			}
+			if node, isFound := ht.buckets[hashKey(op.Key, ht.bucketSize)].search(op.Key); isFound && err == nil {
+				result.Found = true
+				result.Version = node.entry.Version
+			}
+		case "DELETE":
+			result.Found = ht.deleteKey(op.Key, RInfo)
+		case "GET":
+			node, isFound := ht.buckets[hashKey(op.Key, ht.bucketSize)].search(op.Key)
+			if isFound && !isExpired(&node.entry, time.Now().UnixNano()) && node.entry.Type == TypeString {
+				result.Found = true
+				result.Value = append([]byte{}, node.entry.Value...)
+				result.Version = node.entry.Version
+			}
+		}
+		results = append(results, result)
+	}
+	return results, firstErr
+}
+
+// applyGroup runs transaction operations and logs their writes as a single TXN record, so a
+// replay applies all of them or none. Caller must hold the lock.
+func (ht *HashTable) applyGroup(ops []TxnOp, RInfo *CheckpointInfo) ([]TxnOpResult, error) {
+	ht.grouping = true
+	defer ht.endGroup(RInfo)
+
+	return ht.applyOps(ops, RInfo)
+}
+
+// endGroup logs the records collected since grouping started as one TXN record
+func (ht *HashTable) endGroup(RInfo *CheckpointInfo) {
+	group := ht.group
+	ht.grouping, ht.group = false, nil
+
+	if len(group) > 0 {
+		ht.record(RInfo, WALRecord{Operation: "TXN", Group: group})
+	}
+}
+
+// growth is the memory the writes of ops need at most. Caller must hold the lock.
+func (ht *HashTable) growth(ops []TxnOp) int64 {
+	var total int64
+	for _, op := range ops {
+		if op.Type != "PUT" {
+			continue
+		}
+		delta := entrySize(op.Key, op.Value)
+		if node, isFound := ht.buckets[hashKey(op.Key, ht.bucketSize)].search(op.Key); isFound {
+			delta -= node.entry.size()
+		}
+		if delta > 0 {
+			total += delta
+		}
+	}
+	return total
+}
+
+// Txn atomically evaluates the compares and applies then when they all hold and els otherwise.
+// Every key has to live on this node, keys locked by a prepared transaction fail with ErrTxnConflict.
+func (ht *HashTable) Txn(compares []Compare, then, els []TxnOp, RInfo *CheckpointInfo) (bool, []TxnOpResult, error) {
+	if err := validateTxn(compares, then, els); err != nil {
+		return false, nil, err
+	}
+
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	for _, compare := range compares {
+		if err := ht.checkUnlocked(compare.Key); err != nil {
+			return false, nil, err
+		}
+	}
+	for _, op := range append(append([]TxnOp{}, then...), els...) {
+		if err := ht.checkUnlocked(op.Key); err != nil {
+			return false, nil, err
+		}
+	}
+
+	succeeded := true
+	for _, compare := range compares {
+		if !ht.evaluate(compare) {
+			succeeded = false
+			break
+		}
+	}
+	ops := els
+	if succeeded {
+		ops = then
+	}
+
+	// Make room for every write first so the group is applied whole, evictions join the group
+	ht.grouping = true
+	defer ht.endGroup(RInfo)
+	if err := ht.reserve(ht.growth(ops), "", RInfo); err != nil {
+		return false, nil, err
+	}
+	results, err := ht.applyOps(ops, RInfo)
+	return succeeded, results, err
+}
+
+// PreparedTxns returns the IDs of the transactions waiting for a decision
+func (ht *HashTable) PreparedTxns() []string {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	ids := make([]string, 0, len(ht.prepared))
+	for id := range ht.prepared {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// restoreTxn makes a logged transaction prepared again while recovering, its decision is
+// replayed from the records that follow or resent by the coordinator
+func (ht *HashTable) restoreTxn(txn *PreparedTxn) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	ht.lockTxn(txn)
+}
+
+// finishTxn drops a prepared transaction while recovering, its writes were logged before
+func (ht *HashTable) finishTxn(id string) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if txn, ok := ht.prepared[id]; ok {
+		ht.unlockTxn(txn)
+	}
+}
@@ -0,0 +1,572 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ValueType is the kind of value held by a key
+type ValueType int
+
+const (
+	TypeString ValueType = iota
+	TypeList
+	TypeSet
+	TypeHash
+	TypeVersioned // Concurrent values kept as siblings under vector clocks
+)
+
+// Approximate overhead of a single list element, set member or hash field
+const itemOverhead = 16
+
+var ErrWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+func (t ValueType) String() string {
+	switch t {
+	case TypeList:
+		return "list"
+	case TypeSet:
+		return "set"
+	case TypeHash:
+		return "hash"
+	case TypeVersioned:
+		return "versioned"
+	default:
+		return "string"
+	}
+}
+
+// ParseValueType converts the WAL and checkpoint encoding back to a ValueType, empty means string
+func ParseValueType(valueType string) (ValueType, error) {
+	switch valueType {
+	case "", "string":
+		return TypeString, nil
+	case "list":
+		return TypeList, nil
+	case "set":
+		return TypeSet, nil
+	case "hash":
+		return TypeHash, nil
+	case "versioned":
+		return TypeVersioned, nil
+	}
+	return TypeString, fmt.Errorf("Unknown value type: %s", valueType)
+}
+
+// size is the memory accounted for the entry
+func (e *Entry) size() int64 {
+	size := entrySize(e.Key, e.Value)
+	for _, item := range e.List {
+		size += int64(len(item) + itemOverhead)
+	}
+	for member := range e.Set {
+		size += int64(len(member) + itemOverhead)
+	}
+	for field, value := range e.Hash {
+		size += int64(len(field) + len(value) + itemOverhead)
+	}
+	for _, sibling := range e.Siblings {
+		size += int64(len(sibling.Value) + (len(sibling.Context)+1)*itemOverhead)
+	}
+	return size
+}
+
+func (e *Entry) isEmpty() bool {
+	switch e.Type {
+	case TypeList:
+		return len(e.List) == 0
+	case TypeSet:
+		return len(e.Set) == 0
+	case TypeHash:
+		return len(e.Hash) == 0
+	case TypeVersioned:
+		return len(e.Siblings) == 0
+	}
+	return false
+}
+
+// items encodes a list or set for the WAL and checkpoints, set members are sorted
+func (e *Entry) items() [][]byte {
+	switch e.Type {
+	case TypeList:
+		return e.List
+	case TypeSet:
+		members := make([]string, 0, len(e.Set))
+		for member := range e.Set {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+
+		items := make([][]byte, 0, len(members))
+		for _, member := range members {
+			items = append(items, []byte(member))
+		}
+		return items
+	}
+	return nil
+}
+
+// typedNode looks up a key for a list, set or hash operation. Caller must hold the lock.
+// Returns nil when the key is missing or expired and ErrWrongType when it holds another type.
+func (ht *HashTable) typedNode(key string, valueType ValueType) (*TreeNode, error) {
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound || isExpired(&node.entry, time.Now().UnixNano()) {
+		return nil, nil
+	}
+	if node.entry.Type != valueType {
+		return nil, ErrWrongType
+	}
+	return node, nil
+}
+
+// prepareTyped reserves growth bytes and returns the node for key, creating an empty value when it is missing.
+// Caller must hold the write lock and have checked the type with typedNode.
+func (ht *HashTable) prepareTyped(key string, valueType ValueType, growth int64, RInfo *CheckpointInfo) (*TreeNode, error) {
+	bucketIndex := hashKey(key, ht.bucketSize)
+
+	node, isFound := ht.buckets[bucketIndex].search(key)
+	if isFound && isExpired(&node.entry, time.Now().UnixNano()) {
+		ht.record(RInfo, WALRecord{Operation: "EXPIRE", Key: key})
+		ht.remove(key)
+		ht.expirations++
+		isFound = false
+	}
+
+	if !isFound {
+		growth += entrySize(key, nil)
+	}
+	if err := ht.reserve(growth, key, RInfo); err != nil {
+		return nil, err
+	}
+
+	if !isFound {
+		entry := Entry{Key: key, Type: valueType}
+		switch valueType {
+		case TypeSet:
+			entry.Set = make(map[string]struct{})
+		case TypeHash:
+			entry.Hash = make(map[string][]byte)
+		}
+		ht.add(bucketIndex, entry)
+		ht.usedMemory += entrySize(key, nil)
+	}
+
+	// Evictions and inserts rebalance the trees, look the node up again
+	node, _ = ht.buckets[bucketIndex].search(key)
+	ht.touch(&node.entry)
+	return node, nil
+}
+
+// typedOps are the list, set and hash operations, their records hold the operation and not the
+// resulting value
+var typedOps = map[string]bool{
+	"LPUSH": true, "RPUSH": true, "LPOP": true, "RPOP": true,
+	"SADD": true, "SREM": true,
+	"HSET": true, "HDEL": true,
+}
+
+// commitTyped logs the operation of a list, set or hash mutation, or deletes the key once it is
+// empty. A replay skips the operations the checkpoint already holds, see CheckpointRestore. Caller
+// must hold the write lock.
+func (ht *HashTable) commitTyped(node *TreeNode, record WALRecord, RInfo *CheckpointInfo) {
+	key := node.entry.Key
+	if node.entry.isEmpty() {
+		ht.record(RInfo, WALRecord{Operation: "DELETE", Key: key})
+		ht.remove(key)
+		return
+	}
+
+	record.Key = key
+	node.entry.Version = ht.record(RInfo, record)
+}
+
+// putTyped replaces key with a list, set or hash, used by recovery with the LSN set by replayAt
+func (ht *HashTable) putTyped(key string, valueType ValueType, items [][]byte, fields map[string][]byte, siblings []Sibling, expiresAt int64) {
+	bucketIndex := hashKey(key, ht.bucketSize)
+
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	ht.remove(key)
+
+	entry := Entry{Key: key, Type: valueType, ExpiresAt: expiresAt, Version: ht.replayLSN}
+	switch valueType {
+	case TypeList:
+		entry.List = items
+	case TypeSet:
+		entry.Set = make(map[string]struct{}, len(items))
+		for _, item := range items {
+			entry.Set[string(item)] = struct{}{}
+		}
+	case TypeHash:
+		entry.Hash = make(map[string][]byte, len(fields))
+		for field, value := range fields {
+			entry.Hash[field] = value
+		}
+	case TypeVersioned:
+		entry.Siblings = siblings
+	}
+	if entry.isEmpty() {
+		return
+	}
+
+	ht.add(bucketIndex, entry)
+	ht.usedMemory += entry.size()
+}
+
+// appendTyped adds the items or fields of a checkpoint line to the list, set or hash restored
+// before it, used by recovery
+func (ht *HashTable) appendTyped(key string, items [][]byte, fields map[string][]byte) error {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound {
+		return fmt.Errorf("Error restoring %s : the checkpoint appends to a missing key", key)
+	}
+	switch node.entry.Type {
+	case TypeList:
+		for _, item := range items {
+			node.entry.List = append(node.entry.List, item)
+			ht.usedMemory += int64(len(item) + itemOverhead)
+		}
+	case TypeSet:
+		for _, item := range items {
+			if _, ok := node.entry.Set[string(item)]; !ok {
+				node.entry.Set[string(item)] = struct{}{}
+				ht.usedMemory += int64(len(item) + itemOverhead)
+			}
+		}
+	case TypeHash:
+		for field, value := range fields {
+			if old, ok := node.entry.Hash[field]; ok {
+				ht.usedMemory -= int64(len(field) + len(old) + itemOverhead)
+			}
+			node.entry.Hash[field] = value
+			ht.usedMemory += int64(len(field) + len(value) + itemOverhead)
+		}
+	default:
+		return fmt.Errorf("Error restoring %s : %w", key, ErrWrongType)
+	}
+	return nil
+}
+
+func copyFields(fields map[string][]byte) map[string][]byte {
+	if nil == fields {
+		return nil
+	}
+	copied := make(map[string][]byte, len(fields))
+	for field, value := range fields {
+		copied[field] = value
+	}
+	return copied
+}
+
+func copyItems(items [][]byte) [][]byte {
+	copied := make([][]byte, 0, len(items))
+	for _, item := range items {
+		copied = append(copied, append([]byte{}, item...))
+	}
+	return copied
+}
+
+// ListPush appends values to the head (left) or tail of the list at key and returns the new length
+func (ht *HashTable) ListPush(key string, values [][]byte, left bool, RInfo *CheckpointInfo) (int, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return 0, err
+	}
+
+	if _, err := ht.typedNode(key, TypeList); err != nil {
+		return 0, err
+	}
+
+	var growth int64
+	for _, value := range values {
+		growth += int64(len(value) + itemOverhead)
+	}
+	node, err := ht.prepareTyped(key, TypeList, growth, RInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	values = copyItems(values)
+	if left {
+		// Like redis LPUSH a b c leaves c at the head
+		list := make([][]byte, 0, len(values)+len(node.entry.List))
+		for i := len(values) - 1; i >= 0; i-- {
+			list = append(list, values[i])
+		}
+		node.entry.List = append(list, node.entry.List...)
+	} else {
+		node.entry.List = append(node.entry.List, values...)
+	}
+	ht.usedMemory += growth
+
+	length := len(node.entry.List)
+	operation := "RPUSH"
+	if left {
+		operation = "LPUSH"
+	}
+	ht.commitTyped(node, WALRecord{Operation: operation, Items: values}, RInfo)
+	return length, nil
+}
+
+// ListPop removes up to count values from the head (left) or tail of the list at key
+func (ht *HashTable) ListPop(key string, count int, left bool, RInfo *CheckpointInfo) ([][]byte, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return nil, err
+	}
+
+	node, err := ht.typedNode(key, TypeList)
+	if err != nil || nil == node {
+		return nil, err
+	}
+	ht.touch(&node.entry)
+
+	if count > len(node.entry.List) {
+		count = len(node.entry.List)
+	}
+
+	popped := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		var value []byte
+		if left {
+			value = node.entry.List[0]
+			node.entry.List = node.entry.List[1:]
+		} else {
+			value = node.entry.List[len(node.entry.List)-1]
+			node.entry.List = node.entry.List[:len(node.entry.List)-1]
+		}
+		ht.usedMemory -= int64(len(value) + itemOverhead)
+		popped = append(popped, value)
+	}
+
+	if count > 0 {
+		operation := "RPOP"
+		if left {
+			operation = "LPOP"
+		}
+		ht.commitTyped(node, WALRecord{Operation: operation, Count: count}, RInfo)
+	}
+	return popped, nil
+}
+
+// ListRange returns the values between start and stop inclusive, negative indexes count from the tail
+func (ht *HashTable) ListRange(key string, start, stop int) ([][]byte, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	node, err := ht.typedNode(key, TypeList)
+	if err != nil || nil == node {
+		return [][]byte{}, err
+	}
+	ht.touch(&node.entry)
+
+	length := len(node.entry.List)
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop {
+		return [][]byte{}, nil
+	}
+	return copyItems(node.entry.List[start : stop+1]), nil
+}
+
+// SetAdd adds members to the set at key and returns how many were not present
+func (ht *HashTable) SetAdd(key string, members [][]byte, RInfo *CheckpointInfo) (int, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return 0, err
+	}
+
+	existing, err := ht.typedNode(key, TypeSet)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only members that are new take memory
+	var growth int64
+	newMembers := make(map[string]struct{})
+	for _, member := range members {
+		if nil != existing {
+			if _, ok := existing.entry.Set[string(member)]; ok {
+				continue
+			}
+		}
+		if _, ok := newMembers[string(member)]; !ok {
+			newMembers[string(member)] = struct{}{}
+			growth += int64(len(member) + itemOverhead)
+		}
+	}
+
+	node, err := ht.prepareTyped(key, TypeSet, growth, RInfo)
+	if err != nil {
+		return 0, err
+	}
+	added := make([][]byte, 0, len(newMembers))
+	for member := range newMembers {
+		node.entry.Set[member] = struct{}{}
+		added = append(added, []byte(member))
+	}
+	ht.usedMemory += growth
+
+	ht.commitTyped(node, WALRecord{Operation: "SADD", Items: added}, RInfo)
+	return len(newMembers), nil
+}
+
+// SetRemove removes members from the set at key and returns how many were present
+func (ht *HashTable) SetRemove(key string, members [][]byte, RInfo *CheckpointInfo) (int, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return 0, err
+	}
+
+	node, err := ht.typedNode(key, TypeSet)
+	if err != nil || nil == node {
+		return 0, err
+	}
+	ht.touch(&node.entry)
+
+	removed := [][]byte{}
+	for _, member := range members {
+		if _, ok := node.entry.Set[string(member)]; ok {
+			delete(node.entry.Set, string(member))
+			ht.usedMemory -= int64(len(member) + itemOverhead)
+			removed = append(removed, member)
+		}
+	}
+
+	if len(removed) > 0 {
+		ht.commitTyped(node, WALRecord{Operation: "SREM", Items: copyItems(removed)}, RInfo)
+	}
+	return len(removed), nil
+}
+
+// SetMembers returns the members of the set at key in sorted order
+func (ht *HashTable) SetMembers(key string) ([][]byte, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	node, err := ht.typedNode(key, TypeSet)
+	if err != nil || nil == node {
+		return [][]byte{}, err
+	}
+	ht.touch(&node.entry)
+	return node.entry.items(), nil
+}
+
+func (ht *HashTable) SetIsMember(key string, member []byte) (bool, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	node, err := ht.typedNode(key, TypeSet)
+	if err != nil || nil == node {
+		return false, err
+	}
+	ht.touch(&node.entry)
+
+	_, ok := node.entry.Set[string(member)]
+	return ok, nil
+}
+
+// HashSet sets field in the hash at key, returns true if the field is new
+func (ht *HashTable) HashSet(key, field string, value []byte, RInfo *CheckpointInfo) (bool, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
+	}
+
+	existing, err := ht.typedNode(key, TypeHash)
+	if err != nil {
+		return false, err
+	}
+
+	growth := int64(len(field) + len(value) + itemOverhead)
+	isNew := true
+	if nil != existing {
+		if old, ok := existing.entry.Hash[field]; ok {
+			growth = int64(len(value) - len(old))
+			isNew = false
+		}
+	}
+
+	node, err := ht.prepareTyped(key, TypeHash, growth, RInfo)
+	if err != nil {
+		return false, err
+	}
+	node.entry.Hash[field] = append([]byte{}, value...)
+	ht.usedMemory += growth
+
+	ht.commitTyped(node, WALRecord{Operation: "HSET", Fields: map[string][]byte{field: node.entry.Hash[field]}}, RInfo)
+	return isNew, nil
+}
+
+func (ht *HashTable) HashGet(key, field string) ([]byte, bool, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	node, err := ht.typedNode(key, TypeHash)
+	if err != nil || nil == node {
+		return nil, false, err
+	}
+	ht.touch(&node.entry)
+
+	value, ok := node.entry.Hash[field]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte{}, value...), true, nil
+}
+
+// HashDelete removes fields from the hash at key and returns how many were present
+func (ht *HashTable) HashDelete(key string, fields []string, RInfo *CheckpointInfo) (int, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return 0, err
+	}
+
+	node, err := ht.typedNode(key, TypeHash)
+	if err != nil || nil == node {
+		return 0, err
+	}
+	ht.touch(&node.entry)
+
+	deleted := [][]byte{}
+	for _, field := range fields {
+		if value, ok := node.entry.Hash[field]; ok {
+			delete(node.entry.Hash, field)
+			ht.usedMemory -= int64(len(field) + len(value) + itemOverhead)
+			deleted = append(deleted, []byte(field))
+		}
+	}
+
+	if len(deleted) > 0 {
+		ht.commitTyped(node, WALRecord{Operation: "HDEL", Items: deleted}, RInfo)
+	}
+	return len(deleted), nil
+}
@@ -0,0 +1,276 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VectorClock counts the writes of a key coordinated by each node
+type VectorClock map[string]uint64
+
+// Dot is the write that created a sibling, the Counter-th of the key coordinated by Node. A value
+// written without vector clocks has the zero dot, any write replaces it.
+type Dot struct {
+	Node    string `json:"node,omitempty"`
+	Counter uint64 `json:"counter,omitempty"`
+}
+
+// Sibling is one of the concurrent values of a key versioned with vector clocks. Context is the
+// clock of the values its writer had read, the sibling replaces the ones whose dot it covers.
+type Sibling struct {
+	Value   []byte      `json:"value"`
+	Context VectorClock `json:"context,omitempty"`
+	Dot     Dot         `json:"dot"`
+}
+
+// Covers reports whether the clock holds the write of a dot
+func (c VectorClock) Covers(dot Dot) bool {
+	return c[dot.Node] >= dot.Counter
+}
+
+// Descends reports whether the clock holds every write other holds
+func (c VectorClock) Descends(other VectorClock) bool {
+	for node, counter := range other {
+		if c[node] < counter {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge returns a clock holding the writes of both clocks
+func (c VectorClock) Merge(other VectorClock) VectorClock {
+	merged := make(VectorClock, len(c))
+	for node, counter := range c {
+		merged[node] = counter
+	}
+	for node, counter := range other {
+		merged[node] = max(merged[node], counter)
+	}
+	return merged
+}
+
+// Clock returns the clock of the sibling, its context and its own write
+func (s Sibling) Clock() VectorClock {
+	clock := s.Context.Merge(nil)
+	if s.Dot.Node != "" {
+		clock[s.Dot.Node] = max(clock[s.Dot.Node], s.Dot.Counter)
+	}
+	return clock
+}
+
+// SiblingsClock returns the clock of every sibling, the context of a write replacing them all
+func SiblingsClock(siblings []Sibling) VectorClock {
+	clock := VectorClock{}
+	for _, sibling := range siblings {
+		clock = clock.Merge(sibling.Clock())
+	}
+	return clock
+}
+
+// MergeSiblings returns the siblings of both sets whose write no other sibling replaces, ordered
+// by dot. A write known to both sets is kept once.
+func MergeSiblings(siblings []Sibling, incoming ...Sibling) []Sibling {
+	all := make([]Sibling, 0, len(siblings)+len(incoming))
+	seen := make(map[Dot]bool, cap(all))
+	for _, sibling := range append(append([]Sibling{}, siblings...), incoming...) {
+		if !seen[sibling.Dot] {
+			seen[sibling.Dot] = true
+			all = append(all, sibling)
+		}
+	}
+
+	merged := []Sibling{}
+	for _, sibling := range all {
+		replaced := false
+		for _, other := range all {
+			if other.Dot != sibling.Dot && other.Context.Covers(sibling.Dot) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, sibling)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Dot.Node != merged[j].Dot.Node {
+			return merged[i].Dot.Node < merged[j].Dot.Node
+		}
+		return merged[i].Dot.Counter < merged[j].Dot.Counter
+	})
+	return merged
+}
+
+// EncodeContext returns the opaque token of a clock handed to clients
+func EncodeContext(clock VectorClock) string {
+	if len(clock) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(clock)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeContext reads a token of EncodeContext, an empty token is an empty clock
+func DecodeContext(token string) (VectorClock, error) {
+	clock := VectorClock{}
+	if token == "" {
+		return clock, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, &clock)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid context %q : %w", token, err)
+	}
+	return clock, nil
+}
+
+func copySiblings(siblings []Sibling) []Sibling {
+	if nil == siblings {
+		return nil
+	}
+	copied := make([]Sibling, 0, len(siblings))
+	for _, sibling := range siblings {
+		copied = append(copied, Sibling{Value: append([]byte{}, sibling.Value...), Context: sibling.Context.Merge(nil), Dot: sibling.Dot})
+	}
+	return copied
+}
+
+// siblingsOf returns the siblings of a live key, a string is a sibling with the zero dot. Caller
+// must hold the lock.
+func (ht *HashTable) siblingsOf(key string) ([]Sibling, error) {
+	node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key)
+	if !isFound || isExpired(&node.entry, time.Now().UnixNano()) {
+		return nil, nil
+	}
+	switch node.entry.Type {
+	case TypeString:
+		return []Sibling{{Value: append([]byte{}, node.entry.Value...)}}, nil
+	case TypeVersioned:
+		return copySiblings(node.entry.Siblings), nil
+	}
+	return nil, ErrWrongType
+}
+
+// deletedClock returns the clock of the tombstone of a deleted key, nil when the key is not
+// deleted. Caller must hold the lock.
+func (ht *HashTable) deletedClock(key string) VectorClock {
+	if stamp := ht.merkle.stamps[key]; stamp.Deleted {
+		return stamp.Clock
+	}
+	return nil
+}
+
+// tombstoneClock returns the clock a delete of a key leaves on its tombstone: clock, the siblings
+// of the key and the tombstone it may hold already. The writes counted by a key survive its delete
+// so a later write doesn't reuse their dots. Caller must hold the lock.
+func (ht *HashTable) tombstoneClock(key string, clock VectorClock) VectorClock {
+	clock = clock.Merge(ht.deletedClock(key))
+	if node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key); isFound && node.entry.Type == TypeVersioned {
+		clock = clock.Merge(SiblingsClock(node.entry.Siblings))
+	}
+	if len(clock) == 0 {
+		return nil
+	}
+	return clock
+}
+
+// GetSiblings returns the concurrent values of a key, see siblingsOf. Fails with ErrWrongType when
+// the key holds a list, set or hash.
+func (ht *HashTable) GetSiblings(key string) ([]Sibling, bool, error) {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	siblings, err := ht.siblingsOf(key)
+	if err != nil || len(siblings) == 0 {
+		return nil, false, err
+	}
+	if node, isFound := ht.buckets[hashKey(key, ht.bucketSize)].search(key); isFound {
+		ht.touch(&node.entry)
+	}
+	return siblings, true, nil
+}
+
+// PutSibling writes a value of a key versioned with vector clocks as the coordinator of the write,
+// nodeID names the node in the clocks. The value replaces the siblings context covers and is kept
+// next to the others, a deleted key counts on from the clock of its tombstone and the value
+// replaces the deleted siblings. A timestamp other than 0 is the time of the write for the repairs.
+// Returns the new sibling for the other replicas to merge.
+func (ht *HashTable) PutSibling(key string, value []byte, context VectorClock, nodeID string, timestamp int64, RInfo *CheckpointInfo) (Sibling, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return Sibling{}, err
+	}
+	siblings, err := ht.siblingsOf(key)
+	if err != nil {
+		return Sibling{}, err
+	}
+
+	context = context.Merge(ht.deletedClock(key))
+	counter := max(SiblingsClock(siblings)[nodeID], context[nodeID]) + 1
+	sibling := Sibling{Value: value, Context: context, Dot: Dot{Node: nodeID, Counter: counter}}
+	if err := ht.writeSiblings(key, MergeSiblings(siblings, sibling), timestamp, RInfo); err != nil {
+		return Sibling{}, err
+	}
+	return sibling, nil
+}
+
+// MergeSibling adds a sibling written on another replica, it replaces the siblings its context
+// covers. Returns false when the sibling is known or replaced already, or deleted: the tombstone
+// of the key covers its dot or was written after it.
+func (ht *HashTable) MergeSibling(key string, sibling Sibling, timestamp int64, RInfo *CheckpointInfo) (bool, error) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	if err := ht.checkUnlocked(key); err != nil {
+		return false, err
+	}
+	if stamp := ht.merkle.stamps[key]; stamp.Deleted {
+		if stamp.Clock.Covers(sibling.Dot) || timestamp != 0 && stamp.Timestamp >= timestamp {
+			return false, nil
+		}
+	}
+	siblings, err := ht.siblingsOf(key)
+	if err != nil {
+		return false, err
+	}
+	return ht.mergeSiblings(key, siblings, []Sibling{sibling}, timestamp, RInfo)
+}
+
+// mergeSiblings writes the merge of the local and incoming siblings when it adds one of the
+// incoming. Caller must hold the write lock.
+func (ht *HashTable) mergeSiblings(key string, siblings, incoming []Sibling, timestamp int64, RInfo *CheckpointInfo) (bool, error) {
+	known := make(map[Dot]bool, len(siblings))
+	for _, sibling := range siblings {
+		known[sibling.Dot] = true
+	}
+	merged := MergeSiblings(siblings, incoming...)
+	for _, sibling := range merged {
+		if !known[sibling.Dot] {
+			return true, ht.writeSiblings(key, merged, timestamp, RInfo)
+		}
+	}
+	return false, nil
+}
+
+// writeSiblings replaces the value of a key with siblings. The time of the write is the later of
+// timestamp and the previous write of the key, so replicas holding the same siblings agree on it.
+// Caller must hold the write lock.
+func (ht *HashTable) writeSiblings(key string, siblings []Sibling, timestamp int64, RInfo *CheckpointInfo) error {
+	if timestamp != 0 {
+		if previous, ok := ht.merkle.stamps[key]; ok {
+			timestamp = max(timestamp, previous.Timestamp)
+		}
+		ht.writeStamp = timestamp
+		defer func() { ht.writeStamp = 0 }()
+	}
+	_, err := ht.write(Entry{Key: key, Type: TypeVersioned, Siblings: siblings}, RInfo)
+	return err
+}
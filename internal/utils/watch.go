@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Events buffered per watcher before it is dropped as lagging
+const watchBufferSize = 256
+
+// Recent records kept in memory so a watch can resume without reading the WAL file
+const feedRetention = 1024
+
+var ErrWatchLagged = errors.New("watcher fell behind and was dropped")
+
+// Subscription receives the records of every mutation on a key or key prefix
+type Subscription struct {
+	Key    string
+	Prefix bool
+	C      chan WALRecord // Closed when the subscription ends
+
+	feed   *changeFeed
+	lagged bool
+}
+
+// changeFeed fans out logged records to the subscriptions and keeps the most recent ones
+type changeFeed struct {
+	mtx           sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	recent        []WALRecord
+}
+
+func newChangeFeed() *changeFeed {
+	return &changeFeed{
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+func (s *Subscription) Matches(key string) bool {
+	if s.Prefix {
+		return strings.HasPrefix(key, s.Key)
+	}
+	return key == s.Key
+}
+
+// Accepts reports whether a record changes a watched key, lease and transaction records are not key changes
+func (s *Subscription) Accepts(record WALRecord) bool {
+	for _, change := range record.Changes() {
+		if s.Matches(change.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Changes returns the key changes of a record, the records of a TXN group carry the LSN of the group
+func (record WALRecord) Changes() []WALRecord {
+	switch record.Operation {
+	case "GRANT", "REVOKE", "PREPARE", "COMMIT", "ABORT":
+		return nil
+	case "TXN":
+		changes := make([]WALRecord, 0, len(record.Group))
+		for _, change := range record.Group {
+			change.LSN = record.LSN
+			changes = append(changes, change)
+		}
+		return changes
+	}
+	return []WALRecord{record}
+}
+
+// Close stops the subscription, C is closed
+func (s *Subscription) Close() {
+	s.feed.mtx.Lock()
+	defer s.feed.mtx.Unlock()
+
+	if _, ok := s.feed.subscriptions[s]; ok {
+		delete(s.feed.subscriptions, s)
+		close(s.C)
+	}
+}
+
+// Err reports why C was closed, nil if it was closed with Close
+func (s *Subscription) Err() error {
+	s.feed.mtx.Lock()
+	defer s.feed.mtx.Unlock()
+
+	if s.lagged {
+		return ErrWatchLagged
+	}
+	return nil
+}
+
+// publish never blocks, subscriptions that can't keep up are dropped
+func (f *changeFeed) publish(record WALRecord) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.recent = append(f.recent, record)
+	if len(f.recent) > feedRetention {
+		f.recent = f.recent[len(f.recent)-feedRetention:]
+	}
+
+	for subscription := range f.subscriptions {
+		if !subscription.Accepts(record) {
+			continue
+		}
+		select {
+		case subscription.C <- record:
+		default:
+			subscription.lagged = true
+			delete(f.subscriptions, subscription)
+			close(subscription.C)
+		}
+	}
+}
+
+// record assigns the next LSN to a mutation, writes it to the WAL and notifies watchers.
+// Returns the LSN which is also the new version of the key. Caller must hold the write lock.
+func (ht *HashTable) record(RInfo *CheckpointInfo, record WALRecord) uint64 {
+	// Replayed records keep the LSN they were logged with
+	if ht.replaying {
+		return ht.replayLSN
+	}
+	ht.stamp(&record)
+
+	// Grouped records share the LSN of the TXN record logged at the end of the group
+	if ht.grouping {
+		ht.group = append(ht.group, record)
+		return ht.lsn + 1
+	}
+
+	ht.lsn++
+	record.LSN = ht.lsn
+	if nil != RInfo {
+		RInfo.WC <- record
+	}
+	ht.feed.publish(record)
+	return record.LSN
+}
+
+// replayAt makes the following mutations reuse lsn instead of logging, used while recovering
+func (ht *HashTable) replayAt(lsn uint64) {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	ht.replaying = true
+	ht.replayLSN = lsn
+	if lsn > ht.lsn {
+		ht.lsn = lsn
+	}
+}
+
+func (ht *HashTable) endReplay() {
+	ht.mtx.Lock()
+	defer ht.mtx.Unlock()
+
+	ht.replaying = false
+}
+
+// LSN returns the sequence number of the last logged mutation
+func (ht *HashTable) LSN() uint64 {
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	return ht.lsn
+}
+
+// Watch subscribes to changes of key, or of every key starting with key when prefix is set.
+// Records from startLSN that are still in memory are returned as backlog, retainedFrom is the
+// oldest LSN held in memory so older records have to be read from the WAL. A startLSN of 0 only
+// watches new changes.
+func (ht *HashTable) Watch(key string, prefix bool, startLSN uint64) (subscription *Subscription, backlog []WALRecord, retainedFrom uint64) {
+	// Holding the table lock keeps LSNs from moving while the backlog is taken
+	ht.mtx.RLock()
+	defer ht.mtx.RUnlock()
+
+	f := ht.feed
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	subscription = &Subscription{
+		Key:    key,
+		Prefix: prefix,
+		C:      make(chan WALRecord, watchBufferSize),
+		feed:   f,
+	}
+	f.subscriptions[subscription] = struct{}{}
+
+	retainedFrom = ht.lsn + 1
+	if len(f.recent) > 0 {
+		retainedFrom = f.recent[0].LSN
+	}
+
+	backlog = []WALRecord{}
+	if startLSN == 0 {
+		return subscription, backlog, retainedFrom
+	}
+	for _, record := range f.recent {
+		if record.LSN >= startLSN && subscription.Accepts(record) {
+			backlog = append(backlog, record)
+		}
+	}
+	return subscription, backlog, retainedFrom
+}
@@ -0,0 +1,355 @@
+// Package client sends requests straight to the storage node owning each key. The placement, a
+// ring or the weights of the nodes, is fetched from the coordinator admin service and keys are
+// placed locally the same way the coordinator does, a node reporting that it does not own a key
+// makes the client refresh it and retry.
+// Keys still moving to a new owner after a ring change read as missing until their migration is
+// done, the coordinator serves them from the previous owner meanwhile.
+// Replicas, hinted handoff and vector clocks are left to the coordinator: writes fail with
+// ErrReplicated when the replication factor is above 1, and every request on a key of a vector
+// clock keyspace fails with ErrVersioned.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 50 * time.Millisecond
+	DefaultMaxBackoff     = 2 * time.Second
+)
+
+var (
+	ErrNoCoordinator = errors.New("no coordinator reachable")
+	ErrReplicated    = errors.New("key is replicated, write it through the coordinator")
+	ErrVersioned     = errors.New("key is versioned with vector clocks, send it through the coordinator")
+)
+
+// Config of a client, zero values use the defaults
+type Config struct {
+	Coordinators   []string // Admin host:port of the coordinator replicas, tried in order
+	MaxAttempts    int      // Tries of a request, the first included
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Client routes requests by key with a placement refreshed from the coordinator, it is safe for
+// concurrent use
+type Client struct {
+	config Config
+
+	// The placement and the nodes it was fetched with, guarded by mtx
+	mtx               sync.RWMutex
+	placement         utils.Placement
+	version           uint64
+	replicationFactor int64
+	keyspaces         []*pb.CoordinatorKeyspace   // Longest prefix first
+	addresses         map[string]string           // Node ID to host:port
+	conns             map[string]*grpc.ClientConn // Node ID to connection, dialed on first use
+
+	// Serializes the refreshes so concurrent wrong-owner errors fetch the ring once
+	refreshMtx sync.Mutex
+}
+
+// New fetches the ring from the first coordinator that answers
+func New(ctx context.Context, config Config) (*Client, error) {
+	if len(config.Coordinators) == 0 {
+		return nil, fmt.Errorf("%w: none configured", ErrNoCoordinator)
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+
+	c := &Client{config: config, conns: make(map[string]*grpc.ClientConn)}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connections to the nodes
+func (c *Client) Close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for nodeID, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, nodeID)
+	}
+	return nil
+}
+
+// RingVersion returns the version of the placement the client routes with
+func (c *Client) RingVersion() uint64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.version
+}
+
+// Owner returns the node a key is sent to
+func (c *Client) Owner(key string) (string, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.placement.GetNode(key)
+}
+
+// Refresh fetches the placement from the coordinators, an older one than the current one is ignored
+func (c *Client) Refresh(ctx context.Context) error {
+	c.refreshMtx.Lock()
+	defer c.refreshMtx.Unlock()
+
+	var errs []error
+	for _, address := range c.config.Coordinators {
+		res, err := fetchRing(ctx, address)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s : %w", address, err))
+			continue
+		}
+		return c.setRing(res)
+	}
+	return fmt.Errorf("%w: %v", ErrNoCoordinator, errors.Join(errs...))
+}
+
+func fetchRing(ctx context.Context, address string) (*pb.CoordinatorGetRingResponse, error) {
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return pb.NewCoordinatorClient(conn).GetRing(ctx, &pb.CoordinatorGetRingRequest{})
+}
+
+// setRing routes with a fetched placement and closes the connections to the nodes that left or moved
+func (c *Client) setRing(res *pb.CoordinatorGetRingResponse) error {
+	var placement utils.Placement
+	var err error
+	if nil != res.Ring {
+		placement, err = utils.RingFromProto(res.Ring)
+	} else {
+		placement, err = utils.PlacementFromProto(res.Placement)
+	}
+	if err != nil {
+		return err
+	}
+	addresses := make(map[string]string, len(res.Nodes))
+	for _, node := range res.Nodes {
+		addresses[node.NodeID] = fmt.Sprintf("%s:%d", node.Host, node.Port)
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if nil != c.placement && res.RingVersion < c.version {
+		return nil
+	}
+	for nodeID, conn := range c.conns {
+		if addresses[nodeID] != c.addresses[nodeID] {
+			conn.Close()
+			delete(c.conns, nodeID)
+		}
+	}
+	c.placement, c.version, c.addresses = placement, res.RingVersion, addresses
+	c.replicationFactor, c.keyspaces = res.ReplicationFactor, res.Keyspaces
+	return nil
+}
+
+// check refuses the keys the coordinator has to handle: the ones of a vector clock keyspace, and
+// writes when the keys are replicated
+func (c *Client) check(key string, write bool) error {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	for _, keyspace := range c.keyspaces {
+		if strings.HasPrefix(key, keyspace.Prefix) {
+			if keyspace.VectorClocks {
+				return fmt.Errorf("%w: %s", ErrVersioned, key)
+			}
+			break
+		}
+	}
+	if write && c.replicationFactor > 1 {
+		return fmt.Errorf("%w: %s on %d replicas", ErrReplicated, key, c.replicationFactor)
+	}
+	return nil
+}
+
+// node returns the owner of a key and a client connected to it, the write lock is only taken to
+// dial a node the client has no connection to
+func (c *Client) node(key string) (string, pb.StorageClient, error) {
+	c.mtx.RLock()
+	nodeID, err := c.placement.GetNode(key)
+	conn, ok := c.conns[nodeID]
+	c.mtx.RUnlock()
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		if conn, err = c.dial(nodeID); err != nil {
+			return "", nil, err
+		}
+	}
+	return nodeID, pb.NewStorageClient(conn), nil
+}
+
+// dial connects to a node, unless a concurrent request already did
+func (c *Client) dial(nodeID string) (*grpc.ClientConn, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if conn, ok := c.conns[nodeID]; ok {
+		return conn, nil
+	}
+	address, known := c.addresses[nodeID]
+	if !known {
+		return nil, fmt.Errorf("No address for node %s", nodeID)
+	}
+	// Dialing without blocking only fails on a malformed address, the RPCs report it
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	c.conns[nodeID] = conn
+	return conn, nil
+}
+
+// WrongOwner returns the detail of an error of a node that does not own the key
+func WrongOwner(err error) (*pb.StorageWrongOwner, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		return nil, false
+	}
+	for _, detail := range st.Details() {
+		if wrongOwner, ok := detail.(*pb.StorageWrongOwner); ok {
+			return wrongOwner, true
+		}
+	}
+	return nil, false
+}
+
+// Do runs call on the node owning key. A node that does not own the key rejects the call before
+// running it, the placement is refreshed and the call retried. An unreachable node is retried with
+// backoff when idempotent is set. Stops at the deadline of ctx.
+// Keys of a vector clock keyspace fail with ErrVersioned, a write made by call reaches the owner
+// only, Put, Delete and Increment refuse it when the keys are replicated.
+func (c *Client) Do(ctx context.Context, key string, idempotent bool, call func(ctx context.Context, node pb.StorageClient) error) error {
+	if err := c.check(key, false); err != nil {
+		return err
+	}
+	backoff := c.config.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var node pb.StorageClient
+		if _, node, err = c.node(key); err != nil {
+			return err
+		}
+		if err = call(ctx, node); err == nil {
+			return nil
+		}
+
+		wrongOwner, isWrongOwner := WrongOwner(err)
+		retry := isWrongOwner || idempotent && status.Code(err) == codes.Unavailable
+		if !retry || attempt == c.config.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		// A newer ring routes the key elsewhere at once. A node with an older ring than the client
+		// waits for the coordinator to push it, an unreachable node for the ring to change.
+		if !isWrongOwner || wrongOwner.RingVersion > c.RingVersion() {
+			version := c.RingVersion()
+			if c.Refresh(ctx) == nil && c.RingVersion() > version && isWrongOwner {
+				continue
+			}
+		}
+
+		// Full jitter
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(rand.Int63n(int64(backoff)) + 1)):
+		}
+		backoff = min(backoff*2, c.config.MaxBackoff)
+	}
+}
+
+// Get reads a key, found is false when it is missing
+func (c *Client) Get(ctx context.Context, key string) (value []byte, found bool, err error) {
+	err = c.Do(ctx, key, true, func(ctx context.Context, node pb.StorageClient) error {
+		res, err := node.Get(ctx, &pb.StorageGetRequest{Key: key})
+		if err != nil {
+			return err
+		}
+		value, found = res.Value, res.Found
+		return nil
+	})
+	return value, found, err
+}
+
+// Put writes a key, a ttl of 0 keeps it until it is deleted
+func (c *Client) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.check(key, true); err != nil {
+		return err
+	}
+	request := &pb.StoragePutRequest{Key: key, Value: value}
+	if ttl > 0 {
+		seconds := int64(max(ttl/time.Second, 1))
+		request.TTLSeconds = &seconds
+	}
+	return c.Do(ctx, key, true, func(ctx context.Context, node pb.StorageClient) error {
+		_, err := node.Put(ctx, request)
+		return err
+	})
+}
+
+// Delete deletes a key, returns whether it was present
+func (c *Client) Delete(ctx context.Context, key string) (present bool, err error) {
+	if err := c.check(key, true); err != nil {
+		return false, err
+	}
+	err = c.Do(ctx, key, true, func(ctx context.Context, node pb.StorageClient) error {
+		res, err := node.Delete(ctx, &pb.StorageDeleteRequest{Key: key})
+		if err != nil {
+			return err
+		}
+		present = res.IsKeyPresent
+		return nil
+	})
+	return present, err
+}
+
+// Increment adds delta to an integer key and returns the new value. It is not retried once the
+// node may have applied it.
+func (c *Client) Increment(ctx context.Context, key string, delta int64) (value int64, err error) {
+	if err := c.check(key, true); err != nil {
+		return 0, err
+	}
+	err = c.Do(ctx, key, false, func(ctx context.Context, node pb.StorageClient) error {
+		res, err := node.Increment(ctx, &pb.StorageIncrementRequest{Key: key, Delta: delta})
+		if err != nil {
+			return err
+		}
+		value = res.Value
+		return nil
+	})
+	return value, err
+}
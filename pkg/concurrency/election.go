@@ -0,0 +1,78 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+)
+
+var ErrNoLeader = errors.New("election has no leader")
+
+// Election elects a single leader among the sessions campaigning on a key, the value of
+// the key is the proposal of the leader
+type Election struct {
+	session *Session
+	key     string
+}
+
+func NewElection(session *Session, key string) *Election {
+	return &Election{session: session, key: key}
+}
+
+// Campaign blocks until this session is the leader or ctx is done
+func (e *Election) Campaign(ctx context.Context, value string) error {
+	return acquire(ctx, e.session, e.key, []byte(value))
+}
+
+// Resign gives up leadership so another campaign can win
+func (e *Election) Resign(ctx context.Context) error {
+	return release(ctx, e.session, e.key)
+}
+
+// Leader returns the value proposed by the current leader
+func (e *Election) Leader(ctx context.Context) (string, error) {
+	res, err := e.session.client.Get(ctx, &pb.StorageGetRequest{Key: e.key})
+	if err != nil {
+		return "", err
+	}
+	if !res.Found {
+		return "", ErrNoLeader
+	}
+	return string(res.GetValue()), nil
+}
+
+// Observe sends the value of every new leader until ctx is done or the watch fails
+func (e *Election) Observe(ctx context.Context) <-chan string {
+	leaders := make(chan string)
+	go func() {
+		defer close(leaders)
+
+		stream, err := e.session.client.Watch(ctx, &pb.StorageWatchRequest{Key: e.key})
+		if err != nil {
+			return
+		}
+		if leader, err := e.Leader(ctx); err == nil {
+			select {
+			case leaders <- leader:
+			case <-ctx.Done():
+				return
+			}
+		}
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if event.Operation != "PUT" {
+				continue
+			}
+			select {
+			case leaders <- string(event.GetValue()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return leaders
+}
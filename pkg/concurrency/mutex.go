@@ -0,0 +1,125 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Longest wait for a delete event before trying to take the key again
+const lockRetryInterval = time.Second
+
+var ErrLocked = errors.New("mutex is held by another session")
+var ErrNotHeld = errors.New("mutex is not held by this session")
+
+// Mutex is a lock on a single key, held while the key exists attached to the session lease
+type Mutex struct {
+	session *Session
+	key     string
+}
+
+func NewMutex(session *Session, key string) *Mutex {
+	return &Mutex{session: session, key: key}
+}
+
+func (m *Mutex) Key() string {
+	return m.key
+}
+
+// Lock blocks until the key is created by this session or ctx is done
+func (m *Mutex) Lock(ctx context.Context) error {
+	return acquire(ctx, m.session, m.key, []byte(strconv.FormatInt(m.session.Lease(), 10)))
+}
+
+// TryLock takes the lock if it is free and fails with ErrLocked otherwise
+func (m *Mutex) TryLock(ctx context.Context) error {
+	return create(ctx, m.session, m.key, []byte(strconv.FormatInt(m.session.Lease(), 10)))
+}
+
+// Unlock deletes the key if this session still holds it
+func (m *Mutex) Unlock(ctx context.Context) error {
+	return release(ctx, m.session, m.key)
+}
+
+// create puts the key only if it is absent
+func create(ctx context.Context, session *Session, key string, value []byte) error {
+	select {
+	case <-session.Done():
+		return ErrSessionExpired
+	default:
+	}
+
+	_, err := session.client.Put(ctx, &pb.StoragePutRequest{
+		Key:      key,
+		Value:    value,
+		Lease:    session.Lease(),
+		IfAbsent: true,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return ErrLocked
+	}
+	if status.Code(err) == codes.NotFound {
+		session.expire()
+		return ErrSessionExpired
+	}
+	return err
+}
+
+// acquire retries create each time the key is deleted until it succeeds
+func acquire(ctx context.Context, session *Session, key string, value []byte) error {
+	for {
+		err := create(ctx, session, key, value)
+		if !errors.Is(err, ErrLocked) {
+			return err
+		}
+		if err := waitDelete(ctx, session, key); err != nil {
+			return err
+		}
+	}
+}
+
+func release(ctx context.Context, session *Session, key string) error {
+	lease := session.Lease()
+	res, err := session.client.Delete(ctx, &pb.StorageDeleteRequest{Key: key, Lease: &lease})
+	if err != nil {
+		return err
+	}
+	if !res.IsKeyPresent {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// waitDelete returns once key is removed or lockRetryInterval passed, as the delete may
+// happen before the watch is registered on the node
+func waitDelete(ctx context.Context, session *Session, key string) error {
+	watchCtx, cancel := context.WithTimeout(ctx, lockRetryInterval)
+	defer cancel()
+
+	stream, err := session.client.Watch(watchCtx, &pb.StorageWatchRequest{Key: key})
+	if err == nil {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				break
+			}
+			if event.Operation == "DELETE" || event.Operation == "EXPIRE" || event.Operation == "EVICT" {
+				return nil
+			}
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-session.Done():
+		return ErrSessionExpired
+	default:
+		return nil
+	}
+}
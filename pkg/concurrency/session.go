@@ -0,0 +1,98 @@
+// Package concurrency builds mutexes and leader election on the leases of a storage node.
+// Every key used by a session has to live on the node the session was created on. Leases are local
+// to that node, the keys of a session are not replicated whatever the replication factor.
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const DefaultSessionTTLSeconds = 10
+
+var ErrSessionExpired = errors.New("session lease expired")
+
+// Session holds a lease and keeps it alive until it is closed
+type Session struct {
+	client pb.StorageClient
+	lease  int64
+	ttl    int64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewSession grants a lease of ttlSeconds on the node and keeps it alive in the background,
+// a ttlSeconds of 0 uses DefaultSessionTTLSeconds
+func NewSession(ctx context.Context, client pb.StorageClient, ttlSeconds int64) (*Session, error) {
+	if ttlSeconds <= 0 {
+		ttlSeconds = DefaultSessionTTLSeconds
+	}
+	res, err := client.LeaseGrant(ctx, &pb.StorageLeaseGrantRequest{TTLSeconds: ttlSeconds})
+	if err != nil {
+		return nil, err
+	}
+
+	keepAliveCtx, cancel := context.WithCancel(context.Background())
+	session := &Session{
+		client: client,
+		lease:  res.ID,
+		ttl:    res.TTLSeconds,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go session.keepAlive(keepAliveCtx)
+	return session, nil
+}
+
+// Lease returns the ID of the lease the keys of the session are attached to
+func (s *Session) Lease() int64 {
+	return s.lease
+}
+
+// Done is closed when the session is closed or its lease expired
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the keep alives and revokes the lease, deleting every key held by the session
+func (s *Session) Close(ctx context.Context) error {
+	s.cancel()
+	s.expire()
+
+	_, err := s.client.LeaseRevoke(ctx, &pb.StorageLeaseRevokeRequest{ID: s.lease})
+	if status.Code(err) == codes.NotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *Session) expire() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// keepAlive renews the lease three times per TTL, a lease the node no longer knows ends the session
+func (s *Session) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.ttl) * time.Second / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := s.client.LeaseKeepAlive(ctx, &pb.StorageLeaseKeepAliveRequest{ID: s.lease})
+			if status.Code(err) == codes.NotFound {
+				s.expire()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+)
+
+// readEvents decodes the change events the file sink wrote for a node
+func readEvents(t *testing.T, path string) []coordinator.ChangeEvent {
+	t.Helper()
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	events := []coordinator.ChangeEvent{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event coordinator.ChangeEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Error decoding %q : %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func readOffsets(t *testing.T, path string) map[string]uint64 {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offsets := map[string]uint64{}
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		t.Fatal(err)
+	}
+	return offsets
+}
+
+// startCDC starts a coordinator whose streams are cancelled before the nodes stop, even when the
+// test fails. The returned function closes it earlier.
+func startCDC(t *testing.T, config *coordinator.Config) (*coordinator.Coordinator, func()) {
+	t.Helper()
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var once sync.Once
+	stop := func() { once.Do(c.Close) }
+	t.Cleanup(stop)
+	return c, stop
+}
+
+// seedOffsets makes the streams start from the first change of the nodes, a stream without an
+// offset only sends the changes made once it is connected
+func seedOffsets(t *testing.T, path string, nodeIDs ...string) {
+	t.Helper()
+	offsets := map[string]uint64{}
+	for _, nodeID := range nodeIDs {
+		offsets[nodeID] = 0
+	}
+	data, _ := json.Marshal(offsets)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readLine reads an event from a socket sink connection
+func readLine(t *testing.T, reader *bufio.Reader) coordinator.ChangeEvent {
+	t.Helper()
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event coordinator.ChangeEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func eventKeys(events []coordinator.ChangeEvent) []string {
+	keys := []string{}
+	for _, event := range events {
+		keys = append(keys, event.Key)
+	}
+	return keys
+}
+
+// Changes reach the file sink, and a restarted coordinator resumes after the saved offset
+func TestCDCFileSink(t *testing.T) {
+	dir := t.TempDir()
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 1}
+	config.CDC.Enabled = true
+	config.CDC.Sink = "file"
+	config.CDC.Directory = dir
+	config.CDC.OffsetsFile = filepath.Join(dir, "offsets.json")
+	processes := startProcesses(t, config, "n1")
+	events := filepath.Join(dir, "n1.jsonl")
+	seedOffsets(t, config.CDC.OffsetsFile, "n1")
+
+	c, stop := startCDC(t, config)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, key := range []string{"a", "b"} {
+		if _, err := c.Put(ctx, key, []byte(key), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "the events of a and b", func() bool { return len(readEvents(t, events)) == 2 })
+	stop()
+
+	written := readEvents(t, events)
+	if keys := eventKeys(written); keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("Expected the events of a and b, got %v", keys)
+	}
+	if written[0].NodeID != "n1" || written[0].LSN >= written[1].LSN || string(written[1].Value) != "b" {
+		t.Errorf("Unexpected events %+v", written)
+	}
+	if offsets := readOffsets(t, config.CDC.OffsetsFile); offsets["n1"] != written[1].LSN {
+		t.Fatalf("Expected offset %d saved for Node[n1], got %v", written[1].LSN, offsets)
+	}
+
+	// A change made while the coordinator is down is delivered once it is back, the earlier ones are not repeated
+	processes["n1"].storage.HashTable.Put("c", []byte("c"), nil)
+	_, stop = startCDC(t, config)
+	waitFor(t, "the event of c", func() bool { return len(readEvents(t, events)) >= 3 })
+	stop()
+
+	written = readEvents(t, events)
+	if keys := eventKeys(written); len(keys) != 3 || keys[2] != "c" {
+		t.Fatalf("Expected only the event of c after a restart, got %v", keys)
+	}
+	if offsets := readOffsets(t, config.CDC.OffsetsFile); offsets["n1"] != written[2].LSN {
+		t.Errorf("Expected offset %d saved for Node[n1], got %v", written[2].LSN, offsets)
+	}
+
+	// Rewinding the offset delivers the later events again
+	rewound, _ := json.Marshal(map[string]uint64{"n1": written[0].LSN})
+	if err := os.WriteFile(config.CDC.OffsetsFile, rewound, 0644); err != nil {
+		t.Fatal(err)
+	}
+	startCDC(t, config)
+	waitFor(t, "the events after a", func() bool { return len(readEvents(t, events)) >= 5 })
+	if keys := eventKeys(readEvents(t, events)); len(keys) != 5 || keys[3] != "b" || keys[4] != "c" {
+		t.Errorf("Expected b and c again after the rewind, got %v", keys)
+	}
+}
+
+// Changes are written to a local unix socket
+func TestCDCSocketSink(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cdc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	listener, err := net.Listen("unix", filepath.Join(dir, "cdc.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 1}
+	config.CDC.Enabled = true
+	config.CDC.Sink = "socket"
+	config.CDC.SocketPath = filepath.Join(dir, "cdc.sock")
+	config.CDC.OffsetsFile = filepath.Join(dir, "offsets.json")
+	startProcesses(t, config, "n1")
+	seedOffsets(t, config.CDC.OffsetsFile, "n1")
+	c, _ := startCDC(t, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.Put(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	listener.(*net.UnixListener).SetDeadline(time.Now().Add(10 * time.Second))
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if event := readLine(t, bufio.NewReader(conn)); event.NodeID != "n1" || event.Key != "a" || string(event.Value) != "1" {
+		t.Errorf("Expected the event of a from Node[n1], got %+v", event)
+	}
+}
+
+// Events a sink error dropped are sent again, the streams restart from the saved offsets
+func TestCDCSinkError(t *testing.T) {
+	dir, err := os.MkdirTemp("", "cdc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	listener, err := net.Listen("unix", filepath.Join(dir, "cdc.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	listener.(*net.UnixListener).SetDeadline(time.Now().Add(10 * time.Second))
+
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 1}
+	config.CDC.Enabled = true
+	config.CDC.Sink = "socket"
+	config.CDC.SocketPath = filepath.Join(dir, "cdc.sock")
+	config.CDC.OffsetsFile = filepath.Join(dir, "offsets.json")
+	startProcesses(t, config, "n1")
+	seedOffsets(t, config.CDC.OffsetsFile, "n1")
+	c, _ := startCDC(t, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.Put(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	first := readLine(t, bufio.NewReader(conn))
+	waitFor(t, "the offset of a", func() bool { return readOffsets(t, config.CDC.OffsetsFile)["n1"] == first.LSN })
+
+	// b is buffered, the write of the large c fails on the closed connection and drops it
+	conn.Close()
+	if _, err := c.Put(ctx, "b", []byte("2"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(ctx, "c", make([]byte, 64*1024), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err = listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	reader := bufio.NewReader(conn)
+	keys := []string{}
+	for len(keys) == 0 || keys[len(keys)-1] != "c" {
+		keys = append(keys, readLine(t, reader).Key)
+	}
+	if keys[0] != "b" {
+		t.Errorf("Expected b and c after the error, got %v", keys)
+	}
+}
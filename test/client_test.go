@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"github.com/b1acktothefuture/dht-system/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The client sends keys straight to their owner and follows the ring when it changes
+func TestClientRouting(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, AdminPort: freePort(t)}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	joining := config.Nodes["n3"]
+	delete(config.Nodes, "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cl, err := client.New(ctx, client.Config{Coordinators: []string{"127.0.0.1:1", fmt.Sprintf("127.0.0.1:%d", config.AdminPort)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	keys := []string{}
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if err := cl.Put(ctx, key, []byte(key), 0); err != nil {
+			t.Fatalf("Put %s failed : %v", key, err)
+		}
+		keys = append(keys, key)
+	}
+	expectPlaced(t, "Client put", c, map[string]*utils.HashTable{"n1": tables["n1"], "n2": tables["n2"]}, keys)
+	if value, found, err := cl.Get(ctx, "key1"); err != nil || !found || string(value) != "key1" {
+		t.Errorf("Expected key1, got %q %v (%v)", value, found, err)
+	}
+	if value, err := cl.Increment(ctx, "counter", 5); err != nil || value != 5 {
+		t.Errorf("Expected 5, got %d (%v)", value, err)
+	}
+	if present, err := cl.Delete(ctx, "counter"); err != nil || !present {
+		t.Errorf("Expected counter to be deleted, got %v (%v)", present, err)
+	}
+
+	// A wrong-owner error of a newer ring makes the client fetch it and retry at once
+	if err := c.AddNode("n3", joining); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	_, version, err := c.Ring()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cl.RingVersion() == version {
+		t.Fatalf("Expected the client to still route with the old ring")
+	}
+	calls := 0
+	err = cl.Do(ctx, "key1", false, func(ctx context.Context, node pb.StorageClient) error {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		st, _ := status.New(codes.FailedPrecondition, "wrong owner").WithDetails(&pb.StorageWrongOwner{RingVersion: version, Owner: mustOwner(t, c, "key1")})
+		return st.Err()
+	})
+	if err != nil || calls != 2 || cl.RingVersion() != version {
+		t.Errorf("Expected a retry with ring version %d, got %d calls at %d (%v)", version, calls, cl.RingVersion(), err)
+	}
+	// Concurrent requests share the connections, the one to n3 is dialed by one of them
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		if owner, _ := cl.Owner(key); owner != mustOwner(t, c, key) {
+			t.Errorf("Client routes %s to %s, owner is %s", key, owner, mustOwner(t, c, key))
+		}
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if value, found, err := cl.Get(ctx, key); err != nil || !found || string(value) != key {
+				t.Errorf("Expected %s after the ring change, got %q %v (%v)", key, value, found, err)
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	// Unavailable nodes are retried until the deadline, other errors are not retried
+	short, cancelShort := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancelShort()
+	start := time.Now()
+	calls = 0
+	err = cl.Do(short, "key1", true, func(ctx context.Context, node pb.StorageClient) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	})
+	if status.Code(err) != codes.Unavailable || calls < 2 || time.Since(start) > time.Second {
+		t.Errorf("Expected Unavailable after retries within the deadline, got %v after %d calls in %v", err, calls, time.Since(start))
+	}
+	calls = 0
+	err = cl.Do(ctx, "key1", false, func(ctx context.Context, node pb.StorageClient) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	})
+	if calls != 1 || status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected a single call of a non idempotent request, got %d (%v)", calls, err)
+	}
+}
+
+// The client places keys with any placement, and leaves replicated writes and vector clock keyspaces
+// to the coordinator
+func TestClientPlacements(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{
+		Nodes:     map[string]coordinator.Node{},
+		AdminPort: freePort(t),
+		Keyspaces: []coordinator.Keyspace{{Prefix: "cart:", Versioning: coordinator.VersioningVectorClock}},
+	}
+	config.Placement.Strategy = utils.PlacementRendezvous
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cl, err := client.New(ctx, client.Config{Coordinators: []string{fmt.Sprintf("127.0.0.1:%d", config.AdminPort)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	keys := []string{}
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if err := cl.Put(ctx, key, []byte(key), 0); err != nil {
+			t.Fatalf("Put %s failed : %v", key, err)
+		}
+		keys = append(keys, key)
+	}
+	expectPlaced(t, "Rendezvous put", c, tables, keys)
+	if _, _, err := cl.Get(ctx, "cart:1"); !errors.Is(err, client.ErrVersioned) {
+		t.Errorf("Expected ErrVersioned, got %v", err)
+	}
+
+	// Writes are refused once the keys are replicated, reads still go to the owner
+	replicated := &coordinator.Config{Nodes: config.Nodes, AdminPort: freePort(t), ReplicationFactor: 2}
+	replicated.Placement.Strategy = utils.PlacementJump
+	r, err := coordinator.NewCoordinator(replicated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	rcl, err := client.New(ctx, client.Config{Coordinators: []string{fmt.Sprintf("127.0.0.1:%d", replicated.AdminPort)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcl.Close()
+	if err := rcl.Put(ctx, "key1", []byte("x"), 0); !errors.Is(err, client.ErrReplicated) {
+		t.Errorf("Expected ErrReplicated, got %v", err)
+	}
+	if _, err := rcl.Increment(ctx, "counter", 1); !errors.Is(err, client.ErrReplicated) {
+		t.Errorf("Expected ErrReplicated, got %v", err)
+	}
+	if owner, _ := rcl.Owner("key1"); owner != mustOwner(t, r, "key1") {
+		t.Errorf("Client routes key1 to %s, owner is %s", owner, mustOwner(t, r, "key1"))
+	}
+}
@@ -1,9 +1,12 @@
 package test
 
 import (
+	"fmt"
 	"testing"
 
+	pb "github.com/b1acktothefuture/dht-system/gen"
 	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestConsistentHashing(t *testing.T) {
@@ -57,3 +60,196 @@ func TestConsistentHashing(t *testing.T) {
 		t.Error("No keys were redistributed after removing a node")
 	}
 }
+
+// A key changes owner exactly when its position falls in a moved range
+func TestMovedRanges(t *testing.T) {
+	before := utils.NewConsistentHash(10)
+	before.AddNode("NodeA")
+	before.AddNode("NodeB")
+	after := before.Clone()
+	after.AddNode("NodeC")
+	after.RemoveNode("NodeA")
+
+	moves := utils.MovedRanges(before, after)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		from, _ := before.GetNode(key)
+		to, _ := after.GetNode(key)
+
+		var move *utils.RangeMove
+		for j := range moves {
+			if moves[j].Range.Contains(utils.KeyHash(key)) {
+				move = &moves[j]
+			}
+		}
+		if (from != to) != (move != nil) {
+			t.Fatalf("Key %s moved from %s to %s, range %+v", key, from, to, move)
+		}
+		if move != nil && (move.From != from || move.To != to) {
+			t.Errorf("Key %s moves from %s to %s, range says %+v", key, from, to, *move)
+		}
+	}
+
+	// The clone is not affected by the changes
+	if nodes := before.ListNodes(); len(nodes) != 2 {
+		t.Errorf("Expected 2 nodes in the original ring, got %v", nodes)
+	}
+}
+
+// Nodes get a share of the keys proportional to their weight
+func TestWeightedDistribution(t *testing.T) {
+	ring := utils.NewConsistentHash(100)
+	weights := map[string]int{"NodeA": 1, "NodeB": 2, "NodeC": 4}
+	total := 0
+	for node, weight := range weights {
+		ring.AddWeightedNode(node, weight)
+		total += weight
+	}
+
+	const keys = 70000
+	counts := map[string]int{}
+	for i := 0; i < keys; i++ {
+		node, _ := ring.GetNode(fmt.Sprintf("key%d", i))
+		counts[node]++
+	}
+	for node, weight := range weights {
+		expected := float64(keys) * float64(weight) / float64(total)
+		if deviation := (float64(counts[node]) - expected) / expected; deviation > 0.2 || deviation < -0.2 {
+			t.Errorf("%s of weight %d got %d keys, expected about %.0f", node, weight, counts[node], expected)
+		}
+	}
+}
+
+// Changing a weight only moves the ranges of the virtual nodes added or removed
+func TestSetWeight(t *testing.T) {
+	ring := utils.NewConsistentHash(10)
+	ring.AddNode("NodeA")
+	ring.AddNode("NodeB")
+	ring.AddNode("NodeC")
+	before := ring.Clone()
+
+	ring.SetWeight("NodeA", 3)
+	if ring.Weight("NodeA") != 3 {
+		t.Errorf("Expected weight 3, got %d", ring.Weight("NodeA"))
+	}
+	grown := utils.MovedRanges(before, ring)
+	if len(grown) == 0 {
+		t.Fatalf("Expected ranges to move to NodeA")
+	}
+	for _, move := range grown {
+		if move.To != "NodeA" {
+			t.Errorf("Range %+v should only move to NodeA", move)
+		}
+	}
+
+	ring.SetWeight("NodeA", 1)
+	if moves := utils.MovedRanges(before, ring); len(moves) != 0 {
+		t.Errorf("Expected the original ring back, got %+v", moves)
+	}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		want, _ := before.GetNode(key)
+		got, _ := ring.GetNode(key)
+		if want != got {
+			t.Fatalf("Key %s moved from %s to %s", key, want, got)
+		}
+	}
+}
+
+// Lookups read a snapshot while the ring changes, every change publishes a new version
+func TestRingSnapshots(t *testing.T) {
+	ring := utils.NewConsistentHash(10)
+	ring.AddNode("NodeA")
+	ring.AddNode("NodeA")
+	if ring.Version() != 1 {
+		t.Errorf("Expected version 1 after adding one node, got %d", ring.Version())
+	}
+	snapshot := ring.Snapshot()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			ring.AddNode(fmt.Sprintf("Node%d", i))
+			ring.SetWeight(fmt.Sprintf("Node%d", i), 2)
+			ring.RemoveNode(fmt.Sprintf("Node%d", i))
+		}
+	}()
+	last := uint64(0)
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		current := ring.Snapshot()
+		if current.Version() < last {
+			t.Fatalf("Version went back from %d to %d", last, current.Version())
+		}
+		last = current.Version()
+		if _, err := current.GetNode("key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if ring.Version() != 151 {
+		t.Errorf("Expected version 151, got %d", ring.Version())
+	}
+	if node, _ := snapshot.GetNode("key"); node != "NodeA" || snapshot.Version() != 1 {
+		t.Errorf("The snapshot changed to %s at version %d", node, snapshot.Version())
+	}
+	if moves := snapshot.Diff(ring.Snapshot()); len(moves) != 0 {
+		t.Errorf("Expected the same ring back, got %+v", moves)
+	}
+}
+
+// A serialized ring places keys as the original at its version
+func TestRingProto(t *testing.T) {
+	ring, err := utils.NewBoundedConsistentHash(10, utils.HashXXHash, 1.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring.AddWeightedNode("NodeA", 1)
+	ring.AddWeightedNode("NodeB", 2)
+	ring.AddWeightedNode("NodeC", 3)
+
+	data, err := proto.Marshal(ring.Snapshot().ToProto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var message pb.StorageRing
+	if err := proto.Unmarshal(data, &message); err != nil {
+		t.Fatal(err)
+	}
+	copied, err := utils.RingFromProto(&message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if copied.Version() != 3 || copied.Config() != ring.Config() {
+		t.Errorf("Expected %+v at version 3, got %+v at %d", ring.Config(), copied.Config(), copied.Version())
+	}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		want, _ := ring.GetNode(key)
+		got, _ := copied.GetNode(key)
+		if want != got {
+			t.Fatalf("Key %s placed on %s, expected %s", key, got, want)
+		}
+	}
+
+	// Versions compare to the ranges that moved in between
+	ring.RemoveNode("NodeB")
+	moves := copied.Snapshot().Diff(ring.Snapshot())
+	if len(moves) == 0 {
+		t.Fatalf("Expected ranges to move from NodeB")
+	}
+	if moves := utils.MovedRanges(copied, ring); len(moves) == 0 {
+		t.Errorf("Expected MovedRanges to match Diff")
+	}
+
+	message.Tokens = append(message.Tokens, &pb.StorageRingToken{Position: 1, Node: "NodeZ"})
+	if _, err := utils.RingFromProto(&message); err == nil {
+		t.Errorf("Expected a token of an unknown node to fail")
+	}
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func TestIncrement(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	// Missing key starts at 0
+	if value, err := ht.Increment("counter", 5, nil); err != nil || value != 5 {
+		t.Fatalf("Expected 5, got %d (%v)", value, err)
+	}
+	if value, err := ht.Increment("counter", -7, nil); err != nil || value != -2 {
+		t.Fatalf("Expected -2, got %d (%v)", value, err)
+	}
+	if got, _ := ht.Get("counter"); string(got) != "-2" {
+		t.Errorf("Counter should be stored as a decimal string, got %s", got)
+	}
+
+	ht.Put("text", []byte("abc"), nil)
+	if _, err := ht.Increment("text", 1, nil); !errors.Is(err, utils.ErrNotInteger) {
+		t.Errorf("Expected ErrNotInteger, got %v", err)
+	}
+
+	ht.Increment("max", math.MaxInt64, nil)
+	if _, err := ht.Increment("max", 1, nil); !errors.Is(err, utils.ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestIncrementConcurrency(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	const numThreads = 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ht.Increment("counter", 1, nil)
+		}()
+	}
+	wg.Wait()
+
+	if got, _ := ht.Get("counter"); string(got) != "1000" {
+		t.Errorf("Lost increments, expected 1000, got %s", got)
+	}
+}
@@ -0,0 +1,376 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func values(items [][]byte) []string {
+	converted := make([]string, 0, len(items))
+	for _, item := range items {
+		converted = append(converted, string(item))
+	}
+	return converted
+}
+
+func TestList(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	ht.ListPush("queue", [][]byte{[]byte("b"), []byte("c")}, false, nil)
+	if length, _ := ht.ListPush("queue", [][]byte{[]byte("a")}, true, nil); length != 3 {
+		t.Errorf("Expected length 3, got %d", length)
+	}
+
+	items, _ := ht.ListRange("queue", 0, -1)
+	expectKeys(t, "Full range", values(items), "a", "b", "c")
+	items, _ = ht.ListRange("queue", -2, 10)
+	expectKeys(t, "Negative start", values(items), "b", "c")
+
+	popped, _ := ht.ListPop("queue", 1, true, nil)
+	expectKeys(t, "Left pop", values(popped), "a")
+	popped, _ = ht.ListPop("queue", 5, false, nil)
+	expectKeys(t, "Right pop", values(popped), "c", "b")
+
+	// Empty lists are removed
+	if stats := ht.Stats(); stats.NumKeys != 0 || stats.UsedMemory != 0 {
+		t.Errorf("Empty list should be deleted, got %+v", stats)
+	}
+}
+
+func TestSetAndHash(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	if added, _ := ht.SetAdd("tags", [][]byte{[]byte("go"), []byte("db"), []byte("go")}, nil); added != 2 {
+		t.Errorf("Expected 2 members added, got %d", added)
+	}
+	members, _ := ht.SetMembers("tags")
+	expectKeys(t, "Members", values(members), "db", "go")
+	if isMember, _ := ht.SetIsMember("tags", []byte("go")); !isMember {
+		t.Errorf("go should be a member")
+	}
+	if removed, _ := ht.SetRemove("tags", [][]byte{[]byte("go"), []byte("rust")}, nil); removed != 1 {
+		t.Errorf("Expected 1 member removed, got %d", removed)
+	}
+
+	if isNew, _ := ht.HashSet("user", "name", []byte("ana"), nil); !isNew {
+		t.Errorf("Field should be new")
+	}
+	if isNew, _ := ht.HashSet("user", "name", []byte("bob"), nil); isNew {
+		t.Errorf("Field should not be new")
+	}
+	if value, ok, _ := ht.HashGet("user", "name"); !ok || string(value) != "bob" {
+		t.Errorf("Expected bob, got %s", value)
+	}
+	if deleted, _ := ht.HashDelete("user", []string{"name", "age"}, nil); deleted != 1 {
+		t.Errorf("Expected 1 field deleted, got %d", deleted)
+	}
+	if _, ok, _ := ht.HashGet("user", "name"); ok {
+		t.Errorf("Field should be deleted")
+	}
+}
+
+func TestWrongType(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	ht.Put("string", []byte("value"), nil)
+	ht.SetAdd("set", [][]byte{[]byte("member")}, nil)
+
+	if _, err := ht.ListPush("string", [][]byte{[]byte("a")}, true, nil); !errors.Is(err, utils.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+	if _, _, err := ht.HashGet("set", "field"); !errors.Is(err, utils.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+	if _, _, err := ht.GetValue("set"); !errors.Is(err, utils.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+	if _, err := ht.Increment("set", 1, nil); !errors.Is(err, utils.ErrWrongType) {
+		t.Errorf("Expected ErrWrongType, got %v", err)
+	}
+
+	// Put replaces a value of any type
+	ht.Put("set", []byte("value"), nil)
+	if value, ok := ht.Get("set"); !ok || string(value) != "value" {
+		t.Errorf("Put should overwrite the set")
+	}
+}
+
+// Lists, sets and hashes survive a checkpoint and a WAL replay
+func TestTypedRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.ListPush("list", [][]byte{[]byte("a"), []byte("b")}, false, nil)
+	live.SetAdd("set", [][]byte{[]byte("x")}, nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+
+	// Changes after the checkpoint only live in the WAL
+	live.ListPop("list", 1, true, rInfo)
+	live.HashSet("hash", "field", []byte("value"), rInfo)
+	live.SetRemove("set", [][]byte{[]byte("x")}, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	walFile := filepath.Join(dir, "node.wal")
+	file, err := os.Create(walFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &walFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+
+	items, _ := recovered.ListRange("list", 0, -1)
+	expectKeys(t, "Recovered list", values(items), "b")
+	if value, ok, _ := recovered.HashGet("hash", "field"); !ok || string(value) != "value" {
+		t.Errorf("Recovered hash field : %s", value)
+	}
+	members, _ := recovered.SetMembers("set")
+	expectKeys(t, "Recovered set", values(members))
+	if fmt.Sprint(live.Stats()) != fmt.Sprint(recovered.Stats()) {
+		t.Errorf("Stats mismatch : live %+v, recovered %+v", live.Stats(), recovered.Stats())
+	}
+}
+
+// Operations of lists, sets and hashes are logged alone, their replay over a checkpoint that holds
+// some of them is idempotent and large values are checkpointed in several lines
+func TestTypedOpsRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(64)
+	large := make([]byte, 600*1024)
+	for i := 0; i < 200; i++ {
+		live.ListPush("list", [][]byte{[]byte(fmt.Sprint(i))}, i%2 == 0, rInfo)
+		live.SetAdd("set", [][]byte{[]byte(fmt.Sprint(i % 50))}, rInfo)
+		live.HashSet("hash", fmt.Sprint(i%30), []byte(fmt.Sprint(i)), rInfo)
+	}
+	for i := 0; i < 3; i++ {
+		live.ListPush("large", [][]byte{large}, false, rInfo)
+		live.HashSet("fields", fmt.Sprint(i), large, rInfo)
+	}
+	// The WAL keeps the records the checkpoint holds, they are not applied twice
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	live.ListPop("list", 5, true, rInfo)
+	live.ListPop("list", 3, false, rInfo)
+	live.SetRemove("set", [][]byte{[]byte("1"), []byte("2")}, rInfo)
+	live.HashDelete("hash", []string{"3", "4"}, rInfo)
+	live.ListPush("list", [][]byte{[]byte("x"), []byte("y")}, true, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	walFile := filepath.Join(dir, "node.wal")
+	file, err := os.Create(walFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		if len(data) > len(large)*2 {
+			t.Errorf("Record %s of %s holds %d bytes", record.Operation, record.Key, len(data))
+		}
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+	checkpoint, err := os.ReadFile(rInfo.CheckPointFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(checkpoint), "\n"); lines < 8 {
+		t.Errorf("Expected the large values on several lines, got %d lines", lines)
+	}
+
+	recovered := utils.NewHashTable(64)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &walFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	for _, key := range []string{"list", "large"} {
+		expected, _ := live.ListRange(key, 0, -1)
+		items, _ := recovered.ListRange(key, 0, -1)
+		if !reflect.DeepEqual(expected, items) {
+			t.Errorf("Recovered %s has %d items, expected %d", key, len(items), len(expected))
+		}
+	}
+	expected, _ := live.SetMembers("set")
+	members, _ := recovered.SetMembers("set")
+	expectKeys(t, "Recovered set", values(members), values(expected)...)
+	for _, key := range []string{"hash", "fields"} {
+		for i := 0; i < 30; i++ {
+			value, ok, _ := live.HashGet(key, fmt.Sprint(i))
+			restored, restoredOk, _ := recovered.HashGet(key, fmt.Sprint(i))
+			if ok != restoredOk || string(value) != string(restored) {
+				t.Errorf("Recovered %s field %d : %v, expected %v", key, i, restoredOk, ok)
+			}
+		}
+	}
+	if fmt.Sprint(live.Stats()) != fmt.Sprint(recovered.Stats()) {
+		t.Errorf("Stats mismatch : live %+v, recovered %+v", live.Stats(), recovered.Stats())
+	}
+}
+
+// Writes computed from the previous value run once on the owner, the replicas import the result
+func TestReplicatedDataTypes(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2, WriteQuorum: 2}
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	calls := []struct {
+		key  string
+		call func(client pb.StorageClient) error
+	}{
+		{"counter", func(client pb.StorageClient) error {
+			_, err := client.Increment(ctx, &pb.StorageIncrementRequest{Key: "counter", Delta: 5})
+			return err
+		}},
+		{"counter", func(client pb.StorageClient) error {
+			_, err := client.Decrement(ctx, &pb.StorageDecrementRequest{Key: "counter", Delta: 2})
+			return err
+		}},
+		{"queue", func(client pb.StorageClient) error {
+			_, err := client.ListPush(ctx, &pb.StorageListPushRequest{Key: "queue", Values: [][]byte{[]byte("a"), []byte("b"), []byte("c")}})
+			return err
+		}},
+		{"queue", func(client pb.StorageClient) error {
+			_, err := client.ListPop(ctx, &pb.StorageListPopRequest{Key: "queue", Count: 1, Left: true})
+			return err
+		}},
+		{"tags", func(client pb.StorageClient) error {
+			_, err := client.SetAdd(ctx, &pb.StorageSetAddRequest{Key: "tags", Members: [][]byte{[]byte("x"), []byte("y")}})
+			return err
+		}},
+		{"tags", func(client pb.StorageClient) error {
+			_, err := client.SetRemove(ctx, &pb.StorageSetRemoveRequest{Key: "tags", Members: [][]byte{[]byte("x")}})
+			return err
+		}},
+		{"user", func(client pb.StorageClient) error {
+			_, err := client.HashSet(ctx, &pb.StorageHashSetRequest{Key: "user", Field: "name", Value: []byte("ada")})
+			return err
+		}},
+		{"user", func(client pb.StorageClient) error {
+			_, err := client.HashSet(ctx, &pb.StorageHashSetRequest{Key: "user", Field: "age", Value: []byte("36")})
+			return err
+		}},
+		{"user", func(client pb.StorageClient) error {
+			_, err := client.HashDelete(ctx, &pb.StorageHashDeleteRequest{Key: "user", Fields: []string{"age"}})
+			return err
+		}},
+		{"name", func(client pb.StorageClient) error {
+			_, err := client.Put(ctx, &pb.StoragePutRequest{Key: "name", Value: []byte("old")})
+			return err
+		}},
+		{"name", func(client pb.StorageClient) error {
+			_, err := client.Update(ctx, &pb.StorageUpdateRequest{Key: "name", Value: []byte("new")})
+			return err
+		}},
+		{"single", func(client pb.StorageClient) error {
+			_, err := client.ListPush(ctx, &pb.StorageListPushRequest{Key: "single", Values: [][]byte{[]byte("a")}})
+			return err
+		}},
+		{"single", func(client pb.StorageClient) error {
+			_, err := client.ListPop(ctx, &pb.StorageListPopRequest{Key: "single", Count: 1})
+			return err
+		}},
+	}
+	for _, call := range calls {
+		result, err := c.Mutate(ctx, call.key, call.call)
+		if err != nil || len(result.Acked) != 2 {
+			t.Fatalf("Expected %s written on both replicas, got %+v (%v)", call.key, result, err)
+		}
+	}
+	expectReplicasEqual(t, c, processes, "counter", "queue", "tags", "user", "name", "single")
+
+	owner, _ := c.KeyOwner("counter")
+	if value, _ := processes[owner.Replicas[1]].storage.HashTable.Get("counter"); string(value) != "3" {
+		t.Errorf("Expected the counter to be applied once on the replica, got %q", value)
+	}
+	owner, _ = c.KeyOwner("single")
+	if stamp, ok := processes[owner.Replicas[1]].storage.HashTable.Stamp("single"); !ok || !stamp.Deleted {
+		t.Errorf("Expected the emptied list deleted on the replica, got %+v", stamp)
+	}
+
+	if _, err := c.Mutate(ctx, "queue", func(client pb.StorageClient) error {
+		_, err := client.Increment(ctx, &pb.StorageIncrementRequest{Key: "queue", Delta: 1})
+		return err
+	}); err == nil {
+		t.Errorf("Expected an increment of a list to fail")
+	}
+}
+
+// expectReplicasEqual checks that every replica of the keys holds the same value at the same time
+func expectReplicasEqual(t *testing.T, c *coordinator.Coordinator, processes map[string]*storageProcess, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		owner, err := c.KeyOwner(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected []utils.ReplicaEntry
+		for i, nodeID := range owner.Replicas {
+			entries := processes[nodeID].storage.HashTable.ExportNamed([]string{key})
+			for j := range entries {
+				// Versions are numbered by each node
+				entries[j].Version = 0
+			}
+			if i == 0 {
+				expected = entries
+			} else if !reflect.DeepEqual(entries, expected) {
+				t.Errorf("Expected Node[%s] to hold %s as %+v, got %+v", nodeID, key, expected, entries)
+			}
+		}
+		if len(expected) == 0 {
+			t.Errorf("Expected %s on Node[%s]", key, owner.Replicas[0])
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// Every entry below uses a 2 byte key and a 10 byte value
+var sampleValue = []byte("0123456789")
+
+const sampleEntrySize = 2 + 10 + 64
+
+func TestNoEviction(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.SetMemoryLimit(2*sampleEntrySize, utils.NoEviction)
+
+	for _, key := range []string{"k1", "k2"} {
+		if _, err := ht.PutWithExpiry(key, sampleValue, 0, nil); err != nil {
+			t.Fatalf("Put failed for key %s : %v", key, err)
+		}
+	}
+	if _, err := ht.PutWithExpiry("k3", sampleValue, 0, nil); !errors.Is(err, utils.ErrOutOfMemory) {
+		t.Fatalf("Expected ErrOutOfMemory, got %v", err)
+	}
+	if _, ok := ht.Get("k3"); ok {
+		t.Errorf("Rejected key should not be stored")
+	}
+
+	// Overwriting with a value of the same size still fits
+	if _, err := ht.PutWithExpiry("k1", []byte("9876543210"), 0, nil); err != nil {
+		t.Errorf("Overwrite failed : %v", err)
+	}
+
+	stats := ht.Stats()
+	if stats.NumKeys != 2 || stats.UsedMemory != 2*sampleEntrySize || stats.Evictions != 0 {
+		t.Errorf("Unexpected stats : %+v", stats)
+	}
+}
+
+func TestEvictionLRU(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.SetMemoryLimit(3*sampleEntrySize, utils.AllKeysLRU)
+
+	ht.Put("k1", sampleValue, nil)
+	ht.Put("k2", sampleValue, nil)
+	ht.Put("k3", sampleValue, nil)
+
+	// k2 becomes the least recently used
+	ht.Get("k1")
+	ht.Get("k3")
+
+	if _, err := ht.PutWithExpiry("k4", sampleValue, 0, nil); err != nil {
+		t.Fatalf("Put failed : %v", err)
+	}
+	if _, ok := ht.Get("k2"); ok {
+		t.Errorf("k2 should have been evicted")
+	}
+	for _, key := range []string{"k1", "k3", "k4"} {
+		if _, ok := ht.Get(key); !ok {
+			t.Errorf("%s should not have been evicted", key)
+		}
+	}
+	if stats := ht.Stats(); stats.Evictions != 1 || stats.NumKeys != 3 {
+		t.Errorf("Unexpected stats : %+v", stats)
+	}
+}
+
+func TestEvictionLFU(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.SetMemoryLimit(3*sampleEntrySize, utils.AllKeysLFU)
+
+	ht.Put("k1", sampleValue, nil)
+	ht.Put("k2", sampleValue, nil)
+	ht.Put("k3", sampleValue, nil)
+
+	// k3 is the most recent but the least frequently used
+	for i := 0; i < 3; i++ {
+		ht.Get("k1")
+		ht.Get("k2")
+	}
+
+	ht.Put("k4", sampleValue, nil)
+	if _, ok := ht.Get("k3"); ok {
+		t.Errorf("k3 should have been evicted")
+	}
+	if _, ok := ht.Get("k1"); !ok {
+		t.Errorf("k1 should not have been evicted")
+	}
+}
+
+func TestEvictionVolatileTTL(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.SetMemoryLimit(3*sampleEntrySize, utils.VolatileTTL)
+
+	now := time.Now()
+	ht.Put("k1", sampleValue, nil)
+	ht.PutWithExpiry("k2", sampleValue, now.Add(time.Hour).UnixNano(), nil)
+	ht.PutWithExpiry("k3", sampleValue, now.Add(time.Minute).UnixNano(), nil)
+
+	ht.Put("k4", sampleValue, nil)
+	if _, ok := ht.Get("k3"); ok {
+		t.Errorf("k3 has the nearest expiry and should have been evicted")
+	}
+
+	ht.Put("k5", sampleValue, nil)
+	if _, ok := ht.Get("k2"); ok {
+		t.Errorf("k2 should have been evicted")
+	}
+
+	// Only keys without a TTL are left
+	if _, err := ht.PutWithExpiry("k6", sampleValue, 0, nil); !errors.Is(err, utils.ErrOutOfMemory) {
+		t.Errorf("Expected ErrOutOfMemory, got %v", err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	ht.PutWithExpiry("k1", sampleValue, time.Now().Add(-time.Second).UnixNano(), nil)
+	ht.Put("k2", sampleValue, nil)
+
+	if _, ok := ht.Get("k1"); ok {
+		t.Errorf("Expired key should not be returned")
+	}
+	if removed := ht.DeleteExpired(nil); removed != 1 {
+		t.Errorf("Expected 1 expired key, got %d", removed)
+	}
+	if stats := ht.Stats(); stats.NumKeys != 1 || stats.Expirations != 1 {
+		t.Errorf("Unexpected stats : %+v", stats)
+	}
+
+	// A key written again keeps its new expiry, not the one it was scheduled with
+	past := time.Now().Add(-time.Second).UnixNano()
+	ht.PutWithExpiry("k3", sampleValue, past, nil)
+	ht.Put("k3", sampleValue, nil)
+	ht.PutWithExpiry("k4", sampleValue, time.Now().Add(time.Hour).UnixNano(), nil)
+	ht.PutWithExpiry("k4", sampleValue, past, nil)
+	if removed := ht.DeleteExpired(nil); removed != 1 {
+		t.Errorf("Expected only k4 to expire, got %d", removed)
+	}
+	if _, ok := ht.Get("k3"); !ok {
+		t.Errorf("k3 was written again without a TTL and should be kept")
+	}
+
+	// A key keeps one schedule however often its TTL is refreshed, it leaves with the TTL or the key
+	for i := 0; i < 1000; i++ {
+		ht.PutWithExpiry("k5", sampleValue, time.Now().Add(time.Hour).UnixNano(), nil)
+	}
+	ht.PutWithExpiry("k6", sampleValue, time.Now().Add(time.Hour).UnixNano(), nil)
+	if stats := ht.Stats(); stats.Expiring != 2 {
+		t.Errorf("Expected 2 keys scheduled, got %+v", stats)
+	}
+	ht.Put("k5", sampleValue, nil)
+	ht.Delete("k6", nil)
+	if stats := ht.Stats(); stats.Expiring != 0 {
+		t.Errorf("Expected no key scheduled, got %+v", stats)
+	}
+}
+
+// Evictions go to the WAL so a replay ends with the same keys
+func TestEvictionRecovery(t *testing.T) {
+	rInfo := &utils.CheckpointInfo{WC: make(chan utils.WALRecord)}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.SetMemoryLimit(2*sampleEntrySize, utils.AllKeysLRU)
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		live.Put(key, sampleValue, rInfo)
+	}
+	close(rInfo.WC)
+	<-collected
+
+	walFile := filepath.Join(t.TempDir(), "node.wal")
+	file, err := os.Create(walFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.RecoverFromWAL(recovered, walFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		_, liveOk := live.Get(key)
+		_, recoveredOk := recovered.Get(key)
+		if liveOk != recoveredOk {
+			t.Errorf("Key %s : live present %v, recovered present %v", key, liveOk, recoveredOk)
+		}
+	}
+	if live.Stats().Evictions != recovered.Stats().Evictions {
+		t.Errorf("Eviction count mismatch : live %d, recovered %d", live.Stats().Evictions, recovered.Stats().Evictions)
+	}
+}
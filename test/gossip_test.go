@@ -0,0 +1,188 @@
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+func memberStates(members []utils.Member) map[string]string {
+	states := make(map[string]string, len(members))
+	for _, member := range members {
+		states[member.ID] = member.State
+	}
+	return states
+}
+
+func TestSwimMembership(t *testing.T) {
+	m := utils.NewMembership("a", "addr-a", 50*time.Millisecond)
+	if changed := m.Apply([]utils.Member{{ID: "b", Address: "addr-b", State: utils.MemberAlive}, {ID: "c", Address: "addr-c", State: utils.MemberAlive}}); changed != 2 {
+		t.Errorf("Expected 2 members added, got %d", changed)
+	}
+
+	// Alive only overrides a suspicion with a newer incarnation
+	m.Suspect("b")
+	m.Apply([]utils.Member{{ID: "b", Address: "addr-b", State: utils.MemberAlive}})
+	if state := memberStates(m.Members())["b"]; state != utils.MemberSuspect {
+		t.Errorf("Expected b suspected, got %s", state)
+	}
+	m.Apply([]utils.Member{{ID: "b", Address: "addr-b", State: utils.MemberAlive, Incarnation: 1}})
+	if state := memberStates(m.Members())["b"]; state != utils.MemberAlive {
+		t.Errorf("Expected b alive after refuting, got %s", state)
+	}
+
+	// The node refutes a suspicion of itself
+	m.Apply([]utils.Member{{ID: "a", Address: "addr-a", State: utils.MemberSuspect}})
+	if self := m.Self(); self.State != utils.MemberAlive || self.Incarnation != 1 {
+		t.Errorf("Expected a alive at incarnation 1, got %+v", self)
+	}
+	if piggyback := m.Piggyback(2); len(piggyback) != 2 || piggyback[0].ID != "a" {
+		t.Errorf("Expected the node first of 2 updates, got %+v", piggyback)
+	}
+
+	// A dead member only comes back with a newer incarnation
+	before := m.Version()
+	m.Apply([]utils.Member{{ID: "c", Address: "addr-c", State: utils.MemberDead}})
+	m.Apply([]utils.Member{{ID: "c", Address: "addr-c", State: utils.MemberAlive}})
+	if state := memberStates(m.Members())["c"]; state != utils.MemberDead {
+		t.Errorf("Expected c dead, got %s", state)
+	}
+	if m.Version() == before || len(m.Live()) != 2 {
+		t.Errorf("Expected the version to change without c")
+	}
+	m.Apply([]utils.Member{{ID: "c", Address: "addr-c", State: utils.MemberAlive, Incarnation: 1}})
+	if m.Version() != before {
+		t.Errorf("Expected the version back once c rejoined")
+	}
+
+	// Suspects are declared dead after the suspicion timeout
+	m.Suspect("c")
+	if dead := m.ExpireSuspects(time.Now()); len(dead) != 0 {
+		t.Errorf("Expected no dead member before the timeout, got %v", dead)
+	}
+	if dead := m.ExpireSuspects(time.Now().Add(100 * time.Millisecond)); len(dead) != 1 || dead[0] != "c" {
+		t.Errorf("Expected c dead after the timeout, got %v", dead)
+	}
+
+	// Every live member is probed once per round
+	probed := map[string]int{}
+	for i := 0; i < 4; i++ {
+		member, ok := m.NextProbe()
+		if !ok {
+			t.Fatalf("Expected a member to probe")
+		}
+		probed[member.ID]++
+	}
+	if probed["b"] != 4 {
+		t.Errorf("Expected only b probed, got %v", probed)
+	}
+}
+
+type gossipNode struct {
+	storage *node.StorageServer
+	server  *grpc.Server
+	address string
+}
+
+// startGossip serves a storage node gossiping with the seeds until it is stopped or the test ends
+func startGossip(t *testing.T, nodeID string, seeds ...string) *gossipNode {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &node.Config{NodeID: nodeID}
+	config.Gossip.Address = listener.Addr().String()
+	config.Gossip.Seeds = seeds
+	config.Gossip.IntervalMillis = 50
+	config.Gossip.PingTimeoutMillis = 50
+	config.Gossip.SuspicionTimeoutMillis = 300
+
+	n := &gossipNode{
+		storage: &node.StorageServer{NodeID: nodeID, HashTable: utils.NewHashTable(10), Gossip: node.NewGossip(config)},
+		server:  grpc.NewServer(),
+		address: listener.Addr().String(),
+	}
+	pb.RegisterStorageServer(n.server, n.storage)
+	go n.server.Serve(listener)
+	n.storage.Gossip.Start()
+	t.Cleanup(n.stop)
+	return n
+}
+
+func (n *gossipNode) stop() {
+	if nil == n.server {
+		return
+	}
+	n.server.Stop()
+	n.storage.Gossip.Stop()
+	n.server = nil
+}
+
+// waitGossip waits until every node sees the expected states and the same version
+func waitGossip(t *testing.T, nodes []*gossipNode, expected map[string]string) uint64 {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		converged := true
+		version := nodes[0].storage.Gossip.Membership.Version()
+		for _, n := range nodes {
+			states := memberStates(n.storage.Gossip.Membership.Members())
+			for nodeID, state := range expected {
+				converged = converged && states[nodeID] == state
+			}
+			converged = converged && n.storage.Gossip.Membership.Version() == version
+		}
+		if converged {
+			return version
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, n := range nodes {
+		t.Logf("Node[%v] sees %+v", n.storage.NodeID, n.storage.Gossip.Membership.Members())
+	}
+	t.Fatalf("Gossip did not converge to %v", expected)
+	return 0
+}
+
+// Nodes join through a seed, a crashed node is suspected then declared dead and a leaving node
+// tells the others
+func TestGossip(t *testing.T) {
+	n1 := startGossip(t, "n1")
+	n2 := startGossip(t, "n2", n1.address)
+	n3 := startGossip(t, "n3", n1.address)
+	n4 := startGossip(t, "n4", n2.address)
+
+	alive := map[string]string{"n1": utils.MemberAlive, "n2": utils.MemberAlive, "n3": utils.MemberAlive, "n4": utils.MemberAlive}
+	joined := waitGossip(t, []*gossipNode{n1, n2, n3, n4}, alive)
+
+	n4.stop()
+	alive["n4"] = utils.MemberDead
+	crashed := waitGossip(t, []*gossipNode{n1, n2, n3}, alive)
+	if crashed == joined {
+		t.Errorf("Expected the version to change once n4 is dead")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	members, version, err := coordinator.GossipMembers(ctx, n3.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != crashed || len(members) != 4 || memberStates(members)["n4"] != utils.MemberDead {
+		t.Errorf("Expected the membership of n3 at version %v, got %v at %v", crashed, members, version)
+	}
+
+	n3.storage.Gossip.Leave(ctx)
+	n3.stop()
+	alive["n3"] = utils.MemberDead
+	waitGossip(t, []*gossipNode{n1, n2}, alive)
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// storageProcess is a node that can be stopped and started again on the same port
+type storageProcess struct {
+	storage *node.StorageServer
+	port    uint64
+	server  *grpc.Server
+}
+
+func (p *storageProcess) start(t *testing.T) {
+	t.Helper()
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", p.port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.port = uint64(listener.Addr().(*net.TCPAddr).Port)
+	p.server = grpc.NewServer()
+	pb.RegisterStorageServer(p.server, p.storage)
+	go p.server.Serve(listener)
+}
+
+func (p *storageProcess) stop() {
+	p.server.Stop()
+}
+
+func startProcesses(t *testing.T, config *coordinator.Config, nodeIDs ...string) map[string]*storageProcess {
+	t.Helper()
+	processes := map[string]*storageProcess{}
+	for _, nodeID := range nodeIDs {
+		p := &storageProcess{storage: &node.StorageServer{NodeID: nodeID, HashTable: utils.NewHashTable(10)}}
+		p.start(t)
+		t.Cleanup(func() { p.stop() })
+		processes[nodeID] = p
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: p.port}
+	}
+	return processes
+}
+
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Writes missed by a replica while it is down are replayed once it is back
+func TestHintedHandoff(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 3, WriteQuorum: 3, HeartbeatIntervalMillis: 100}
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if result, err := c.Put(ctx, "a", []byte("1"), 0); err != nil || len(result.Acked) != 3 {
+		t.Fatalf("Expected 3 replicas to acknowledge, got %+v (%v)", result, err)
+	}
+
+	processes["n3"].stop()
+	waitFor(t, "Node[n3] to be down", func() bool { return c.NodeDown("n3") })
+
+	result, err := c.Put(ctx, "b", []byte("2"), 0)
+	if !errors.Is(err, coordinator.ErrQuorum) || len(result.Acked) != 2 || len(result.Hinted) != 1 || result.Hinted[0] != "n3" {
+		t.Fatalf("Expected the put to be hinted for Node[n3] short of the quorum, got %+v (%v)", result, err)
+	}
+	result, err = c.Delete(ctx, "a")
+	if !errors.Is(err, coordinator.ErrQuorum) || len(result.Hinted) != 1 {
+		t.Fatalf("Expected the delete to be hinted for Node[n3], got %+v (%v)", result, err)
+	}
+	if stats := c.Hints(); stats.Pending["n3"] != 2 || stats.Stored != 2 {
+		t.Fatalf("Expected 2 hints for Node[n3], got %+v", stats)
+	}
+
+	processes["n3"].start(t)
+	waitFor(t, "the hints to be replayed", func() bool {
+		stats := c.Hints()
+		return stats.Replayed == 2 && len(stats.Pending) == 0
+	})
+	if c.NodeDown("n3") {
+		t.Errorf("Expected Node[n3] to be up")
+	}
+
+	ht := processes["n3"].storage.HashTable
+	if value, found := ht.Get("b"); !found || string(value) != "2" {
+		t.Errorf("Expected the hinted put of b, got %q", value)
+	}
+	if stamp, ok := ht.Stamp("a"); !ok || !stamp.Deleted {
+		t.Errorf("Expected a tombstone of a, got %+v", stamp)
+	}
+	for nodeID, p := range processes {
+		first, _ := p.storage.HashTable.Stamp("b")
+		second, _ := processes["n1"].storage.HashTable.Stamp("b")
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("Expected Node[%s] to hold b at the time of Node[n1], got %+v and %+v", nodeID, first, second)
+		}
+	}
+}
+
+// Hints past the limit are dropped oldest first, the rest survive a restart of the coordinator
+func TestHintLimit(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, HeartbeatIntervalMillis: 100}
+	config.HintedHandoff.File = filepath.Join(t.TempDir(), "coordinator.hints")
+	config.HintedHandoff.MaxHints = 2
+	processes := startProcesses(t, config, "n1")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processes["n1"].stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if result, _ := c.Put(ctx, fmt.Sprintf("key%d", i), []byte("v"), 0); len(result.Hinted) != 1 {
+			t.Fatalf("Expected the put to be hinted, got %+v", result)
+		}
+	}
+	if stats := c.Hints(); stats.Pending["n1"] != 2 || stats.Dropped != 1 {
+		t.Fatalf("Expected 2 hints kept and 1 dropped, got %+v", stats)
+	}
+	c.Close()
+
+	processes["n1"].start(t)
+	c, err = coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	waitFor(t, "the hints to be replayed", func() bool { return c.Hints().Replayed == 2 })
+
+	ht := processes["n1"].storage.HashTable
+	if _, found := ht.Get("key0"); found {
+		t.Errorf("Expected the oldest hint to be dropped")
+	}
+	for _, key := range []string{"key1", "key2"} {
+		if _, found := ht.Get(key); !found {
+			t.Errorf("Expected the hint of %s to be replayed", key)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"github.com/b1acktothefuture/dht-system/pkg/concurrency"
+	"google.golang.org/grpc"
+)
+
+func TestLeases(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	lease := ht.GrantLease(60, nil)
+	if _, err := ht.PutWithOptions("lock", []byte("a"), utils.PutOptions{Lease: lease.ID, IfAbsent: true}, nil); err != nil {
+		t.Fatalf("Put with lease failed : %v", err)
+	}
+	if _, err := ht.PutWithOptions("lock", []byte("b"), utils.PutOptions{IfAbsent: true}, nil); !errors.Is(err, utils.ErrKeyExists) {
+		t.Errorf("Expected ErrKeyExists, got %v", err)
+	}
+	if _, err := ht.PutWithOptions("other", []byte("c"), utils.PutOptions{Lease: 12345}, nil); !errors.Is(err, utils.ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound, got %v", err)
+	}
+	ht.PutWithOptions("session", []byte("d"), utils.PutOptions{Lease: lease.ID}, nil)
+
+	// Counters keep the lease, a plain Put detaches the key
+	ht.PutWithOptions("count", []byte("1"), utils.PutOptions{Lease: lease.ID}, nil)
+	ht.Increment("count", 1, nil)
+	ht.Put("session", []byte("e"), nil)
+
+	if deleted, _ := ht.DeleteOwned("lock", lease.ID+1, nil); deleted {
+		t.Errorf("Key should only be deleted by its lease")
+	}
+	if deleted, err := ht.RevokeLease(lease.ID, nil); err != nil || deleted != 2 {
+		t.Errorf("Expected 2 keys deleted, got %d (%v)", deleted, err)
+	}
+	expectKeys(t, "After revoke", rangeKeys(ht.Range("", "", 0, false)), "session")
+	if _, err := ht.KeepAliveLease(lease.ID); !errors.Is(err, utils.ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound, got %v", err)
+	}
+
+	// Expired leases take their keys with them
+	short := ht.GrantLease(1, nil)
+	ht.PutWithOptions("ephemeral", []byte("x"), utils.PutOptions{Lease: short.ID}, nil)
+	time.Sleep(1100 * time.Millisecond)
+	if _, err := ht.PutWithOptions("late", []byte("y"), utils.PutOptions{Lease: short.ID}, nil); !errors.Is(err, utils.ErrLeaseNotFound) {
+		t.Errorf("Expected ErrLeaseNotFound for an expired lease, got %v", err)
+	}
+	if expired := ht.ExpireLeases(nil); expired != 1 {
+		t.Errorf("Expected 1 lease expired, got %d", expired)
+	}
+	if _, ok := ht.Get("ephemeral"); ok {
+		t.Errorf("Key of an expired lease should be deleted")
+	}
+}
+
+// Leases and their keys survive a checkpoint and a WAL replay with the same IDs
+func TestLeaseRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+		WALFile:        filepath.Join(dir, "node.wal"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	kept := live.GrantLease(60, nil)
+	live.PutWithOptions("a", []byte("1"), utils.PutOptions{Lease: kept.ID}, nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	revoked := live.GrantLease(60, rInfo)
+	live.PutWithOptions("b", []byte("2"), utils.PutOptions{Lease: revoked.ID}, rInfo)
+	live.PutWithOptions("c", []byte("3"), utils.PutOptions{Lease: kept.ID}, rInfo)
+	live.RevokeLease(revoked.ID, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	expectKeys(t, "Recovered keys", rangeKeys(recovered.Range("", "", 0, false)), "a", "c")
+	if leases := recovered.Leases(); len(leases) != 1 || leases[0].ID != kept.ID {
+		t.Fatalf("Expected lease %d, got %+v", kept.ID, leases)
+	}
+	if deleted, _ := recovered.RevokeLease(kept.ID, nil); deleted != 2 {
+		t.Errorf("Recovered keys should stay attached, %d deleted", deleted)
+	}
+}
+
+// serveStorage runs an in-memory storage node until the test ends and returns its port
+func serveStorage(t *testing.T, storage *node.StorageServer) uint64 {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterStorageServer(server, storage)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return uint64(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func startStorage(t *testing.T) pb.StorageClient {
+	t.Helper()
+	port := serveStorage(t, &node.StorageServer{HashTable: utils.NewHashTable(10)})
+
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewStorageClient(conn)
+}
+
+func TestMutexAndElection(t *testing.T) {
+	client := startStorage(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first, err := concurrency.NewSession(ctx, client, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := concurrency.NewSession(ctx, client, 5)
+
+	m1, m2 := concurrency.NewMutex(first, "lock"), concurrency.NewMutex(second, "lock")
+	if err := m1.Lock(ctx); err != nil {
+		t.Fatalf("Lock failed : %v", err)
+	}
+	if err := m2.TryLock(ctx); !errors.Is(err, concurrency.ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- m2.Lock(ctx) }()
+	time.Sleep(100 * time.Millisecond)
+	if err := m1.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock failed : %v", err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatalf("Second lock failed : %v", err)
+	}
+	if err := m1.Unlock(ctx); !errors.Is(err, concurrency.ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld, got %v", err)
+	}
+
+	// Closing a session releases everything it holds
+	e1, e2 := concurrency.NewElection(first, "leader"), concurrency.NewElection(second, "leader")
+	if err := e2.Campaign(ctx, "second"); err != nil {
+		t.Fatal(err)
+	}
+	won := make(chan error)
+	go func() { won <- e1.Campaign(ctx, "first") }()
+	time.Sleep(100 * time.Millisecond)
+	if err := second.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-won; err != nil {
+		t.Fatalf("Campaign failed : %v", err)
+	}
+	if leader, err := e1.Leader(ctx); err != nil || leader != "first" {
+		t.Errorf("Expected leader first, got %s (%v)", leader, err)
+	}
+	first.Close(ctx)
+}
@@ -0,0 +1,370 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// memberIDs returns the sorted IDs of the members
+func memberIDs(members map[string]coordinator.Node) []string {
+	ids := []string{}
+	for nodeID := range members {
+		ids = append(ids, nodeID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Nodes added and removed at runtime are kept across a coordinator restart
+func TestMembership(t *testing.T) {
+	dir := t.TempDir()
+	first := serveStorage(t, &node.StorageServer{NodeID: "n1", HashTable: utils.NewHashTable(10)})
+	second := serveStorage(t, &node.StorageServer{NodeID: "n2", HashTable: utils.NewHashTable(10)})
+
+	config := &coordinator.Config{
+		Nodes:                map[string]coordinator.Node{"n1": {Host: "127.0.0.1", Port: first}},
+		NumberOfVirtualNodes: 10,
+		MembershipFile:       filepath.Join(dir, "members"),
+	}
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.AddNode("n2", coordinator.Node{Host: "127.0.0.1", Port: second}); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	if err := c.AddNode("n2", coordinator.Node{Host: "127.0.0.1", Port: second}); !errors.Is(err, coordinator.ErrNodeExists) {
+		t.Errorf("Expected ErrNodeExists, got %v", err)
+	}
+	if err := c.RemoveNode("n3"); !errors.Is(err, coordinator.ErrUnknownNode) {
+		t.Errorf("Expected ErrUnknownNode, got %v", err)
+	}
+	expectKeys(t, "Members", memberIDs(c.Members()), "n1", "n2")
+	c.Close()
+
+	// The saved membership wins over the config
+	restarted, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+	expectKeys(t, "Restarted", memberIDs(restarted.Members()), "n1", "n2")
+
+	if err := restarted.RemoveNode("n1"); err != nil {
+		t.Fatalf("Remove node failed : %v", err)
+	}
+	waitMigrations(t, restarted)
+	if err := restarted.RemoveNode("n2"); !errors.Is(err, coordinator.ErrLastNode) {
+		t.Errorf("Expected ErrLastNode, got %v", err)
+	}
+	expectKeys(t, "After remove", memberIDs(restarted.Members()), "n2")
+}
+
+// waitMigrations waits for the keys moved by the last membership change
+func waitMigrations(t *testing.T, c *coordinator.Coordinator) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		done := true
+		for _, status := range c.Migrations() {
+			done = done && status.State == "DONE"
+		}
+		if done {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Keys still moving : %+v", c.Migrations())
+}
+
+// Keys follow the ring when nodes join and leave
+func TestRebalance(t *testing.T) {
+	tables := map[string]*utils.HashTable{"n1": utils.NewHashTable(10), "n2": utils.NewHashTable(10)}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10}
+	for nodeID, ht := range tables {
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: ht})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	second := config.Nodes["n2"]
+	delete(config.Nodes, "n2")
+
+	keys := []string{}
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables["n1"].Put(key, []byte(key), nil)
+		keys = append(keys, key)
+	}
+	tables["n1"].ListPush("list", [][]byte{[]byte("a"), []byte("b")}, false, nil)
+	keys = append(keys, "list")
+
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.AddNode("n2", second); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	statuses := c.Migrations()
+	if len(statuses) != 1 || statuses[0].From != "n1" || statuses[0].To != "n2" || statuses[0].Imported == 0 || statuses[0].Imported != statuses[0].Dropped {
+		t.Fatalf("Unexpected migrations %+v", statuses)
+	}
+	expectPlaced(t, "After add", c, tables, keys)
+	if items, _ := tables[mustOwner(t, c, "list")].ListRange("list", 0, -1); len(items) != 2 {
+		t.Errorf("List should move whole, got %q", items)
+	}
+
+	// A heavier node only takes keys
+	if err := c.SetNodeWeight("n2", 3); err != nil {
+		t.Fatalf("Set weight failed : %v", err)
+	}
+	waitMigrations(t, c)
+	for _, status := range c.Migrations() {
+		if status.From != "n1" || status.To != "n2" {
+			t.Errorf("Unexpected migration %+v", status)
+		}
+	}
+	expectPlaced(t, "After weight", c, tables, keys)
+
+	if err := c.RemoveNode("n1"); err != nil {
+		t.Fatalf("Remove node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	expectPlaced(t, "After remove", c, tables, keys)
+	if _, res, err := c.Get(context.Background(), "key7"); err != nil || string(res.GetValue()) != "key7" {
+		t.Errorf("Expected key7, got %v (%v)", res, err)
+	}
+}
+
+// Writes to keys still moving start from the moved value, the transfer does not undo them
+func TestWritesWhileMoving(t *testing.T) {
+	tables := map[string]*utils.HashTable{"n1": utils.NewHashTable(10), "n2": utils.NewHashTable(10)}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10}
+	for nodeID, ht := range tables {
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: ht})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	second := config.Nodes["n2"]
+	delete(config.Nodes, "n2")
+
+	// Keys locked on the new owner keep the transfer failing until the lock is released
+	blocked := []utils.TxnOp{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables["n1"].Put(key, []byte(key), nil)
+		blocked = append(blocked, utils.TxnOp{Type: "PUT", Key: key, Value: []byte(key)})
+	}
+	if _, err := tables["n2"].PrepareTxn("block", nil, blocked, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("moving%d", i)
+		tables["n1"].Put(key, []byte("old"), nil)
+	}
+
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.AddNode("n2", second); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	moving := []string{}
+	for i := 0; i < 100 && len(moving) < 2; i++ {
+		if key := fmt.Sprintf("moving%d", i); mustOwner(t, c, key) == "n2" {
+			moving = append(moving, key)
+		}
+	}
+	if len(moving) < 2 {
+		t.Fatalf("Expected keys moving to Node[n2], got %v", moving)
+	}
+	if statuses := c.Migrations(); len(statuses) != 1 || statuses[0].State != "TRANSFERRING" {
+		t.Fatalf("Expected the transfer to be blocked, got %+v", statuses)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	written, gone := moving[0], moving[1]
+	res, err := c.Txn(ctx, &coordinator.TxnRequest{
+		Compares: []*pb.TxnCompare{{Key: written, Target: "VALUE", Result: "=", Value: []byte("old")}},
+		Then:     []*pb.TxnOp{{Type: "PUT", Key: written, Value: []byte("new")}},
+	})
+	if err != nil || !res.Succeeded {
+		t.Fatalf("Expected the compare to see the moved value, got %+v (%v)", res, err)
+	}
+	if _, err := c.Delete(ctx, gone); err != nil {
+		t.Fatal(err)
+	}
+
+	tables["n2"].AbortTxn("block", nil)
+	waitMigrations(t, c)
+	if _, res, err := c.Get(ctx, written); err != nil || string(res.GetValue()) != "new" {
+		t.Errorf("Expected the write to survive the transfer, got %v (%v)", res, err)
+	}
+	if _, res, err := c.Get(ctx, gone); err != nil || res.Found {
+		t.Errorf("Expected the delete to survive the transfer, got %v (%v)", res, err)
+	}
+}
+
+// With replication a joining node takes a copy of each key it now replicates, the others keep theirs
+// unless the key left their replica set
+func TestReplicaMigrations(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	joining := config.Nodes["n3"]
+	delete(config.Nodes, "n3")
+
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	keys := []string{}
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables["n1"].Put(key, []byte(key), nil)
+		tables["n2"].Put(key, []byte(key), nil)
+		keys = append(keys, key)
+	}
+
+	if err := c.AddNode("n3", joining); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	expectReplicated(t, "After add", c, tables, keys, 2)
+
+	if err := c.RemoveNode("n1"); err != nil {
+		t.Fatalf("Remove node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	delete(tables, "n1")
+	expectReplicated(t, "After remove", c, tables, keys, 2)
+}
+
+// expectReplicated checks that each key lives on its replicas only
+func expectReplicated(t *testing.T, name string, c *coordinator.Coordinator, tables map[string]*utils.HashTable, keys []string, replicationFactor int) {
+	t.Helper()
+	for _, key := range keys {
+		replicas, err := c.Placement.GetNodes(key, replicationFactor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for nodeID, ht := range tables {
+			value, present, _ := ht.GetValue(key)
+			if present != slices.Contains(replicas, nodeID) {
+				t.Fatalf("%s: key %s on Node[%s] : %v, replicas are %v", name, key, nodeID, present, replicas)
+			}
+			if present && string(value) != key {
+				t.Fatalf("%s: key %s on Node[%s] is %q", name, key, nodeID, value)
+			}
+		}
+	}
+}
+
+// expectPlaced checks that each key lives on its owner only
+func expectPlaced(t *testing.T, name string, c *coordinator.Coordinator, tables map[string]*utils.HashTable, keys []string) {
+	t.Helper()
+	for _, key := range keys {
+		owner := mustOwner(t, c, key)
+		for nodeID, ht := range tables {
+			// Lists are reported as the wrong type
+			_, present, err := ht.GetValue(key)
+			present = present || err != nil
+			if present != (nodeID == owner) {
+				t.Fatalf("%s: key %s on Node[%s] : %v, owner is %s", name, key, nodeID, present, owner)
+			}
+		}
+	}
+}
+
+func mustOwner(t *testing.T, c *coordinator.Coordinator, key string) string {
+	t.Helper()
+	owner, err := c.Placement.GetNode(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return owner
+}
+
+// A drained node hands its keys to the rest of the ring and shuts down once it holds none
+func TestDrain(t *testing.T) {
+	dir := t.TempDir()
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, MembershipFile: filepath.Join(dir, "members")}
+	stopped := make(chan struct{})
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		storage := &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]}
+		if nodeID == "n2" {
+			storage.Shutdown = func() { close(stopped) }
+		}
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: serveStorage(t, storage)}
+	}
+
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	keys := []string{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables[mustOwner(t, c, key)].Put(key, []byte(key), nil)
+		keys = append(keys, key)
+	}
+
+	// A key locked by a prepared transaction is not dropped, the drain stops short of the shutdown
+	var locked string
+	for _, key := range keys {
+		if mustOwner(t, c, key) == "n2" {
+			locked = key
+			break
+		}
+	}
+	tables["n2"].PrepareTxn("pending", nil, []utils.TxnOp{{Type: "GET", Key: locked}}, nil, nil)
+	if err := c.DrainNode(context.Background(), "n2"); !errors.Is(err, coordinator.ErrDrainIncomplete) {
+		t.Fatalf("Expected ErrDrainIncomplete, got %v", err)
+	}
+	if members := c.Members(); !members["n2"].Leaving {
+		t.Errorf("n2 should be leaving, got %+v", members)
+	}
+
+	tables["n2"].AbortTxn("pending", nil)
+	if err := c.DrainNode(context.Background(), "n2"); err != nil {
+		t.Fatalf("Drain failed : %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Drained node was not shut down")
+	}
+	if stats := tables["n2"].Stats(); stats.NumKeys != 0 {
+		t.Errorf("Drained node still holds %d keys", stats.NumKeys)
+	}
+	delete(tables, "n2")
+	expectPlaced(t, "After drain", c, tables, keys)
+	expectKeys(t, "Members", memberIDs(c.Members()), "n1", "n3")
+}
@@ -0,0 +1,201 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"github.com/b1acktothefuture/dht-system/pkg/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// storageClient connects to a storage node
+func storageClient(t *testing.T, port uint64) pb.StorageClient {
+	t.Helper()
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewStorageClient(conn)
+}
+
+// waitRejected waits until a node rejects a key as owned by owner at the ring version
+func waitRejected(t *testing.T, storage pb.StorageClient, key, owner string, version uint64) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	var err error
+	for time.Now().Before(deadline) {
+		_, err = storage.Get(context.Background(), &pb.StorageGetRequest{Key: key})
+		if detail, ok := client.WrongOwner(err); ok && detail.Owner == owner && detail.RingVersion == version {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Expected %s to be rejected as owned by %s at ring version %d, got %v", key, owner, version, err)
+}
+
+// Nodes reject the keys they do not own once the coordinator pushed the ring
+func TestWrongOwner(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	servers := map[string]*node.StorageServer{}
+	ports := map[string]uint64{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, AdminPort: freePort(t)}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		servers[nodeID] = &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]}
+		ports[nodeID] = serveStorage(t, servers[nodeID])
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: ports[nodeID]}
+	}
+	joining := config.Nodes["n3"]
+	delete(config.Nodes, "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if servers["n1"].RingVersion() != 1 || servers["n2"].RingVersion() != 1 || servers["n3"].RingVersion() != 0 {
+		t.Fatalf("Expected the ring to be pushed to n1 and n2")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	keys := map[string]string{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		keys[key] = mustOwner(t, c, key)
+	}
+	for key, owner := range keys {
+		other := "n1"
+		if owner == "n1" {
+			other = "n2"
+		}
+		_, err := storageClient(t, ports[other]).Put(ctx, &pb.StoragePutRequest{Key: key, Value: []byte(key)})
+		if detail, ok := client.WrongOwner(err); !ok || status.Code(err) != codes.FailedPrecondition || detail.Owner != owner || detail.RingVersion != 1 {
+			t.Fatalf("Expected %s to be rejected by %s as owned by %s, got %v", key, other, owner, err)
+		}
+		break
+	}
+
+	cl, err := client.New(ctx, client.Config{Coordinators: []string{fmt.Sprintf("127.0.0.1:%d", config.AdminPort)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	for key := range keys {
+		if err := cl.Put(ctx, key, []byte(key), 0); err != nil {
+			t.Fatalf("Put %s failed : %v", key, err)
+		}
+	}
+
+	// The old owner rejects the keys that moved once they did, the client follows
+	if err := c.AddNode("n3", joining); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMigrations(t, c)
+	moved := ""
+	for key, owner := range keys {
+		if mustOwner(t, c, key) == "n3" {
+			moved = key
+			waitRejected(t, storageClient(t, ports[owner]), key, "n3", 2)
+			break
+		}
+	}
+	if moved == "" {
+		t.Fatalf("Expected keys to move to n3")
+	}
+	if err := cl.Put(ctx, moved, []byte("new"), 0); err != nil || cl.RingVersion() != 2 {
+		t.Fatalf("Expected the client to follow the ring to version 2, got %d (%v)", cl.RingVersion(), err)
+	}
+	if value, _, _ := tables["n3"].GetValue(moved); string(value) != "new" {
+		t.Errorf("Expected the write on n3, got %q", value)
+	}
+	c.Close()
+
+	// A restarted coordinator continues the ring version of the nodes
+	restarted, err := coordinator.NewCoordinator(&coordinator.Config{Nodes: map[string]coordinator.Node{"n1": config.Nodes["n1"], "n2": config.Nodes["n2"], "n3": joining}, NumberOfVirtualNodes: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+	if version := restarted.Metadata().RingVersion; version != 3 || servers["n3"].RingVersion() != 3 {
+		t.Errorf("Expected ring version 3, got %d and %d on n3", version, servers["n3"].RingVersion())
+	}
+
+	// A node ahead of the coordinator names the owner the request goes to
+	ahead := utils.NewConsistentHash(10)
+	ahead.AddNode("n2")
+	for _, nodeID := range []string{"n1", "n2"} {
+		request := &pb.StorageSetRingRequest{NodeID: nodeID, RingVersion: 10, Ring: ahead.Snapshot().ToProto()}
+		if _, err := storageClient(t, ports[nodeID]).SetRing(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key := range keys {
+		if mustOwner(t, restarted, key) != "n1" {
+			continue
+		}
+		tables["n2"].Put(key, []byte("from n2"), nil)
+		if _, res, err := restarted.Get(ctx, key); err != nil || string(res.Value) != "from n2" {
+			t.Errorf("Expected %s to be read from n2, got %v (%v)", key, res, err)
+		}
+		break
+	}
+}
+
+// A restarted node rejects the keys it does not own with the ring it saved, before any push
+func TestRingRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.ring")
+	ring := utils.NewConsistentHash(10)
+	ring.AddNode("n1")
+	ring.AddNode("n2")
+	before := utils.NewConsistentHash(10)
+	before.AddNode("n1")
+
+	ctx := context.Background()
+	first := &node.StorageServer{NodeID: "n1", HashTable: utils.NewHashTable(10), RingFile: path}
+	for version, r := range []*utils.ConsistentHash{before, ring} {
+		request := &pb.StorageSetRingRequest{NodeID: "n1", RingVersion: uint64(version + 1), Ring: r.Snapshot().ToProto(), Moving: version == 1}
+		if _, err := first.SetRing(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restarted := &node.StorageServer{NodeID: "n1", HashTable: utils.NewHashTable(10), RingFile: path}
+	if err := restarted.LoadRing(); err != nil {
+		t.Fatalf("Load failed : %v", err)
+	}
+	if restarted.RingVersion() != 2 {
+		t.Fatalf("Expected ring version 2, got %d", restarted.RingVersion())
+	}
+	storage := storageClient(t, serveStorage(t, restarted))
+
+	// The keys of n2 are still accepted while they move, rejected once they did
+	key := ""
+	for i := 0; key == ""; i++ {
+		if owner, _ := ring.GetNode(fmt.Sprintf("key%d", i)); owner == "n2" {
+			key = fmt.Sprintf("key%d", i)
+		}
+	}
+	if _, err := storage.Get(ctx, &pb.StorageGetRequest{Key: key}); err != nil {
+		t.Errorf("Expected %s to be accepted while it moves, got %v", key, err)
+	}
+	if _, err := restarted.SetRing(ctx, &pb.StorageSetRingRequest{NodeID: "n1", RingVersion: 2, Ring: ring.Snapshot().ToProto()}); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := &node.StorageServer{NodeID: "n1", HashTable: utils.NewHashTable(10), RingFile: path}
+	if err := reloaded.LoadRing(); err != nil {
+		t.Fatalf("Load failed : %v", err)
+	}
+	_, err := storageClient(t, serveStorage(t, reloaded)).Get(ctx, &pb.StorageGetRequest{Key: key})
+	if detail, ok := client.WrongOwner(err); !ok || detail.Owner != "n2" || detail.RingVersion != 2 {
+		t.Errorf("Expected %s to be rejected as owned by n2, got %v", key, err)
+	}
+}
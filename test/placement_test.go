@@ -0,0 +1,244 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+var placementConfigs = []utils.PlacementConfig{
+	{Strategy: utils.PlacementRing, Hash: utils.HashXXHash, VirtualNodes: 100},
+	{Strategy: utils.PlacementBounded, Hash: utils.HashMurmur3, VirtualNodes: 100, LoadFactor: 1.1},
+	{Strategy: utils.PlacementRendezvous, Hash: utils.HashFNV1a},
+	{Strategy: utils.PlacementJump, Hash: utils.HashXXHash},
+}
+
+func TestHashFuncs(t *testing.T) {
+	expected := map[string]map[string]uint32{
+		utils.HashFNV1a:   {"": 0x811c9dc5, "a": 0xe40c292c},
+		utils.HashXXHash:  {"": 0x02cc5d05, "a": 0x550d7456, "Nobody inspects the spammish repetition": 0xe2293b2f},
+		utils.HashMurmur3: {"": 0, "hello": 0x248bfa47},
+		utils.HashCRC32:   {"a": utils.KeyHash("a")},
+	}
+	for name, hashes := range expected {
+		hash, err := utils.ParseHash(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key, want := range hashes {
+			if got := hash(key); got != want {
+				t.Errorf("%s(%q) = %#x, expected %#x", name, key, got, want)
+			}
+		}
+	}
+	if _, err := utils.ParseHash("md5"); !errors.Is(err, utils.ErrUnknownHash) {
+		t.Errorf("Expected ErrUnknownHash, got %v", err)
+	}
+	if _, err := utils.NewPlacement(utils.PlacementConfig{Strategy: "random"}); !errors.Is(err, utils.ErrUnknownPlacement) {
+		t.Errorf("Expected ErrUnknownPlacement, got %v", err)
+	}
+}
+
+// keyCounts returns the number of keys each node owns
+func keyCounts(t testing.TB, placement utils.Placement, keys int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < keys; i++ {
+		nodeID, err := placement.GetNode(fmt.Sprintf("key%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[nodeID]++
+	}
+	return counts
+}
+
+// Every strategy shares the keys by weight and a joining node only takes keys
+func TestPlacements(t *testing.T) {
+	weights := map[string]int{"n1": 1, "n2": 2, "n3": 4}
+	const keys = 70000
+	for _, config := range placementConfigs {
+		name := config.Strategy + "/" + config.Hash
+		placement, err := utils.NewPlacement(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := placement.Config(); got != config {
+			t.Errorf("%s: expected config %+v, got %+v", name, config, got)
+		}
+		for nodeID, weight := range weights {
+			placement.AddWeightedNode(nodeID, weight)
+		}
+
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("key%d", i)
+			owner, _ := placement.GetNode(key)
+			replicas, err := placement.GetNodes(key, 5)
+			if err != nil || len(replicas) != 3 || replicas[0] != owner || replicas[1] == replicas[2] || replicas[1] == owner || replicas[2] == owner {
+				t.Fatalf("%s: expected 3 distinct replicas of %s led by %s, got %v (%v)", name, key, owner, replicas, err)
+			}
+		}
+
+		counts := keyCounts(t, placement, keys)
+		for nodeID, weight := range weights {
+			expected := float64(keys) * float64(weight) / 7
+			if deviation := (float64(counts[nodeID]) - expected) / expected; deviation > 0.2 || deviation < -0.2 {
+				t.Errorf("%s: %s of weight %d got %d keys, expected about %.0f", name, nodeID, weight, counts[nodeID], expected)
+			}
+		}
+
+		before := placement.Copy()
+		placement.AddNode("n9")
+		moved := 0
+		for i := 0; i < keys; i++ {
+			key := fmt.Sprintf("key%d", i)
+			from, _ := before.GetNode(key)
+			to, _ := placement.GetNode(key)
+			if from == to {
+				continue
+			}
+			moved++
+			// Bounded loads pass the arcs over a full node on
+			if to != "n9" && config.Strategy != utils.PlacementBounded {
+				t.Fatalf("%s: key %s moved from %s to %s", name, key, from, to)
+			}
+		}
+		if share := float64(moved) / keys; share < 0.08 || share > 0.2 {
+			t.Errorf("%s: expected about 1/8 of the keys to move, got %.3f", name, share)
+		}
+		if nodes := before.ListNodes(); len(nodes) != 3 {
+			t.Errorf("%s: the copy should not change, got %v", name, nodes)
+		}
+	}
+}
+
+// Bounded loads cap the share of a node that its virtual nodes alone would overload
+func TestBoundedLoads(t *testing.T) {
+	unbounded, err := utils.NewConsistentHashWithHash(10, utils.HashMurmur3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounded, err := utils.NewBoundedConsistentHash(10, utils.HashMurmur3, 1.1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		unbounded.AddNode(fmt.Sprintf("n%d", i))
+		bounded.AddNode(fmt.Sprintf("n%d", i))
+	}
+
+	const keys = 80000
+	maxShare := func(placement utils.Placement) float64 {
+		most := 0
+		for _, count := range keyCounts(t, placement, keys) {
+			most = max(most, count)
+		}
+		return float64(most) * 8 / keys
+	}
+	if share := maxShare(unbounded); share < 1.3 {
+		t.Fatalf("Expected an overloaded node with few virtual nodes, got %.2f times the fair share", share)
+	}
+	if share := maxShare(bounded); share > 1.15 {
+		t.Errorf("Expected at most 1.1 times the fair share, got %.2f", share)
+	}
+	if _, err := utils.NewBoundedConsistentHash(10, "", 0.5); err == nil {
+		t.Errorf("Expected a load factor below 1 to fail")
+	}
+}
+
+// Keys move between nodes placed by rendezvous and jump hashing
+func TestRebalancePlacements(t *testing.T) {
+	for _, strategy := range []string{utils.PlacementRendezvous, utils.PlacementJump} {
+		tables := map[string]*utils.HashTable{}
+		config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10}
+		config.Placement.Strategy = strategy
+		config.Placement.Hash = utils.HashMurmur3
+		for _, nodeID := range []string{"n1", "n2", "n3"} {
+			tables[nodeID] = utils.NewHashTable(10)
+			port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+			config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+		}
+		joining := config.Nodes["n3"]
+		delete(config.Nodes, "n3")
+
+		c, err := coordinator.NewCoordinator(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := []string{}
+		for i := 0; i < 200; i++ {
+			key := fmt.Sprintf("key%d", i)
+			tables[mustOwner(t, c, key)].Put(key, []byte(key), nil)
+			keys = append(keys, key)
+		}
+
+		if err := c.AddNode("n3", joining); err != nil {
+			t.Fatalf("%s: add node failed : %v", strategy, err)
+		}
+		waitMigrations(t, c)
+		expectPlaced(t, strategy+" after add", c, tables, keys)
+		imported := uint64(0)
+		for _, status := range c.Migrations() {
+			imported += status.Imported
+		}
+		if imported == 0 {
+			t.Errorf("%s: expected keys to move to n3, got %+v", strategy, c.Migrations())
+		}
+
+		if err := c.SetNodeWeight("n1", 3); err != nil {
+			t.Fatalf("%s: set weight failed : %v", strategy, err)
+		}
+		waitMigrations(t, c)
+		expectPlaced(t, strategy+" after weight", c, tables, keys)
+
+		if err := c.RemoveNode("n2"); err != nil {
+			t.Fatalf("%s: remove node failed : %v", strategy, err)
+		}
+		waitMigrations(t, c)
+		delete(tables, "n2")
+		expectPlaced(t, strategy+" after remove", c, tables, keys)
+		c.Close()
+	}
+}
+
+// BenchmarkPlacement compares the lookup speed of the strategies and hash functions over 10 nodes,
+// max/mean is the share of the most loaded node over the fair share
+func BenchmarkPlacement(b *testing.B) {
+	configs := []utils.PlacementConfig{}
+	for _, strategy := range []string{utils.PlacementRing, utils.PlacementBounded, utils.PlacementRendezvous, utils.PlacementJump} {
+		for _, hash := range []string{utils.HashCRC32, utils.HashFNV1a, utils.HashXXHash, utils.HashMurmur3} {
+			configs = append(configs, utils.PlacementConfig{Strategy: strategy, Hash: hash, VirtualNodes: 100})
+		}
+	}
+
+	keys := make([]string, 4096)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d:profile", i)
+	}
+	for _, config := range configs {
+		b.Run(config.Strategy+"/"+config.Hash, func(b *testing.B) {
+			placement, err := utils.NewPlacement(config)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for i := 0; i < 10; i++ {
+				placement.AddNode(fmt.Sprintf("node-%d", i))
+			}
+
+			const sampled = 100000
+			most := 0
+			for _, count := range keyCounts(b, placement, sampled) {
+				most = max(most, count)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				placement.GetNode(keys[i%len(keys)])
+			}
+			b.ReportMetric(float64(most)*10/sampled, "max/mean")
+		})
+	}
+}
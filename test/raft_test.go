@@ -0,0 +1,394 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+func freePort(t *testing.T) uint64 {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return uint64(listener.Addr().(*net.TCPAddr).Port)
+}
+
+// waitMetadata waits until every replica applied the members at the ring version and agrees on a leader
+func waitMetadata(t *testing.T, replicas []*coordinator.Coordinator, members []string, version uint64) string {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		leader := replicas[0].Metadata().Leader
+		agreed := leader != ""
+		for _, c := range replicas {
+			metadata := c.Metadata()
+			agreed = agreed && metadata.Leader == leader && metadata.RingVersion == version && reflect.DeepEqual(memberIDs(metadata.Nodes), members)
+		}
+		if agreed {
+			return leader
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for _, c := range replicas {
+		t.Logf("Replica sees %+v", c.Metadata())
+	}
+	t.Fatalf("Replicas did not agree on %v at version %d", members, version)
+	return ""
+}
+
+// Coordinator replicas agree on the membership through Raft, followers forward admin changes to
+// the leader and route reads, a new leader takes over and a restarted replica catches up
+func TestRaftCoordinators(t *testing.T) {
+	dir := t.TempDir()
+	tables := map[string]*utils.HashTable{}
+	ports := map[string]uint64{}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		ports[nodeID] = serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+	}
+
+	configs := map[string]*coordinator.Config{}
+	peers := map[string]string{}
+	for _, replicaID := range []string{"c1", "c2", "c3"} {
+		config := &coordinator.Config{
+			Nodes: map[string]coordinator.Node{
+				"n1": {Host: "127.0.0.1", Port: ports["n1"]},
+				"n2": {Host: "127.0.0.1", Port: ports["n2"]},
+			},
+			NumberOfVirtualNodes: 10,
+			ReplicationFactor:    2,
+			AdminPort:            freePort(t),
+		}
+		config.Raft.ID = replicaID
+		config.Raft.Peers = peers
+		config.Raft.StateFile = filepath.Join(dir, replicaID+".raft")
+		config.Raft.ElectionTimeoutMillis = 300
+		config.Raft.HeartbeatMillis = 50
+		configs[replicaID] = config
+		peers[replicaID] = fmt.Sprintf("127.0.0.1:%d", config.AdminPort)
+	}
+
+	// Replicas wait for a majority when they start
+	replicas := map[string]*coordinator.Coordinator{}
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for replicaID, config := range configs {
+		wg.Add(1)
+		go func(replicaID string, config *coordinator.Config) {
+			defer wg.Done()
+			c, err := coordinator.NewCoordinator(config)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mtx.Lock()
+			replicas[replicaID] = c
+			mtx.Unlock()
+		}(replicaID, config)
+	}
+	wg.Wait()
+	if len(replicas) != 3 {
+		t.FailNow()
+	}
+	defer func() {
+		for _, c := range replicas {
+			c.Close()
+		}
+	}()
+
+	all := []*coordinator.Coordinator{replicas["c1"], replicas["c2"], replicas["c3"]}
+	leaderID := waitMetadata(t, all, []string{"n1", "n2"}, 1)
+	if metadata := replicas[leaderID].Metadata(); metadata.VirtualNodes != 10 || metadata.ReplicationFactor != 2 {
+		t.Errorf("Expected 10 virtual nodes and a replication factor of 2, got %+v", metadata)
+	}
+	followerID := "c1"
+	if followerID == leaderID {
+		followerID = "c2"
+	}
+
+	// A follower forwards the change to the leader
+	conn, err := grpc.Dial(peers[followerID], grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	admin := pb.NewCoordinatorClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := admin.AddNode(ctx, &pb.CoordinatorAddNodeRequest{Node: &pb.CoordinatorNode{NodeID: "n3", Host: "127.0.0.1", Port: ports["n3"]}}); err != nil {
+		t.Fatalf("Add node through a follower failed : %v", err)
+	}
+	waitMetadata(t, all, []string{"n1", "n2", "n3"}, 2)
+	waitMigrations(t, replicas[leaderID])
+	res, err := admin.ListNodes(ctx, &pb.CoordinatorListNodesRequest{})
+	if err != nil || res.Leader != leaderID || res.RingVersion != 2 || len(res.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes at version 2 led by %s, got %v (%v)", leaderID, res, err)
+	}
+
+	// Every replica routes a key to the same owner
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		for _, ht := range tables {
+			ht.Put(key, []byte("v"), nil)
+		}
+		owners := map[string]bool{}
+		for _, c := range all {
+			nodeID, got, err := c.Get(ctx, key)
+			if err != nil || !got.Found {
+				t.Fatalf("Get %s failed : %v", key, err)
+			}
+			owners[nodeID] = true
+		}
+		if len(owners) != 1 {
+			t.Errorf("Replicas route %s to %v", key, owners)
+		}
+	}
+
+	// The remaining replicas elect a new leader and keep changing the membership
+	replicas[leaderID].Close()
+	delete(replicas, leaderID)
+	remaining := []*coordinator.Coordinator{}
+	for _, c := range replicas {
+		remaining = append(remaining, c)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for remaining[0].Metadata().Leader == leaderID && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if newLeaderID := waitMetadata(t, remaining, []string{"n1", "n2", "n3"}, 2); newLeaderID == leaderID {
+		t.Fatalf("Expected a new leader")
+	}
+	if err := replicas[followerID].RemoveNode("n3"); err != nil {
+		t.Fatalf("Remove node failed : %v", err)
+	}
+	waitMetadata(t, remaining, []string{"n1", "n2"}, 3)
+
+	// The stopped replica catches up from its saved log and the new leader
+	restarted, err := coordinator.NewCoordinator(configs[leaderID])
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicas[leaderID] = restarted
+	waitMetadata(t, append(remaining, restarted), []string{"n1", "n2"}, 3)
+}
+
+// replicaConfigs returns the config of a coordinator replica per ID over the nodes
+func replicaConfigs(t *testing.T, nodes map[string]coordinator.Node, replicaIDs ...string) map[string]*coordinator.Config {
+	t.Helper()
+	dir := t.TempDir()
+	configs := map[string]*coordinator.Config{}
+	peers := map[string]string{}
+	for _, replicaID := range replicaIDs {
+		config := &coordinator.Config{Nodes: nodes, NumberOfVirtualNodes: 10, AdminPort: freePort(t)}
+		config.Raft.ID = replicaID
+		config.Raft.Peers = peers
+		config.Raft.StateFile = filepath.Join(dir, replicaID+".raft")
+		config.Raft.ElectionTimeoutMillis = 300
+		config.Raft.HeartbeatMillis = 50
+		configs[replicaID] = config
+		peers[replicaID] = fmt.Sprintf("127.0.0.1:%d", config.AdminPort)
+	}
+	return configs
+}
+
+// startReplicas starts the replicas of the configs and waits for them all
+func startReplicas(t *testing.T, configs map[string]*coordinator.Config) map[string]*coordinator.Coordinator {
+	t.Helper()
+	replicas := map[string]*coordinator.Coordinator{}
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	for replicaID, config := range configs {
+		wg.Add(1)
+		go func(replicaID string, config *coordinator.Config) {
+			defer wg.Done()
+			c, err := coordinator.NewCoordinator(config)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mtx.Lock()
+			replicas[replicaID] = c
+			mtx.Unlock()
+		}(replicaID, config)
+	}
+	wg.Wait()
+	t.Cleanup(func() {
+		for _, c := range replicas {
+			c.Close()
+		}
+	})
+	if len(replicas) != len(configs) {
+		t.FailNow()
+	}
+	return replicas
+}
+
+// Every replica knows the keys moving, followers read them from the old owner and a new leader
+// finishes moving them
+func TestRaftMigrations(t *testing.T) {
+	tables := map[string]*utils.HashTable{"n1": utils.NewHashTable(10), "n2": utils.NewHashTable(10)}
+	ports := map[string]uint64{}
+	for nodeID, ht := range tables {
+		ports[nodeID] = serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: ht})
+	}
+
+	// Keys locked on the new owner keep the transfer failing until the lock is released
+	keys := []utils.TxnOp{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables["n1"].Put(key, []byte(key), nil)
+		keys = append(keys, utils.TxnOp{Type: "PUT", Key: key, Value: []byte(key)})
+	}
+	if _, err := tables["n2"].PrepareTxn("block", nil, keys, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := map[string]coordinator.Node{"n1": {Host: "127.0.0.1", Port: ports["n1"]}}
+	replicas := startReplicas(t, replicaConfigs(t, nodes, "c1", "c2", "c3"))
+	all := []*coordinator.Coordinator{replicas["c1"], replicas["c2"], replicas["c3"]}
+	leaderID := waitMetadata(t, all, []string{"n1"}, 1)
+	if err := replicas[leaderID].AddNode("n2", coordinator.Node{Host: "127.0.0.1", Port: ports["n2"]}); err != nil {
+		t.Fatalf("Add node failed : %v", err)
+	}
+	waitMetadata(t, all, []string{"n1", "n2"}, 2)
+
+	moving := ""
+	for _, op := range keys {
+		if mustOwner(t, replicas[leaderID], op.Key) == "n2" {
+			moving = op.Key
+			break
+		}
+	}
+	if moving == "" {
+		t.Fatalf("Expected keys moving to Node[n2]")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	remaining := []*coordinator.Coordinator{}
+	for replicaID, c := range replicas {
+		if statuses := c.Migrations(); len(statuses) != 1 || statuses[0].State == "DONE" {
+			t.Fatalf("Expected Replica[%s] to know the migration in flight, got %+v", replicaID, statuses)
+		}
+		if nodeID, res, err := c.Get(ctx, moving); err != nil || nodeID != "n1" || string(res.GetValue()) != moving {
+			t.Errorf("Expected Replica[%s] to read %s from Node[n1], got %v from %s (%v)", replicaID, moving, res, nodeID, err)
+		}
+		if replicaID != leaderID {
+			remaining = append(remaining, c)
+		}
+	}
+
+	// The next leader moves the keys once the old one is gone
+	replicas[leaderID].Close()
+	delete(replicas, leaderID)
+	deadline := time.Now().Add(10 * time.Second)
+	for remaining[0].Metadata().Leader == leaderID && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	waitMetadata(t, remaining, []string{"n1", "n2"}, 2)
+	tables["n2"].AbortTxn("block", nil)
+	for _, c := range remaining {
+		waitMigrations(t, c)
+	}
+	if value, ok := tables["n2"].Get(moving); !ok || string(value) != moving {
+		t.Errorf("Expected %s moved to Node[n2], got %q", moving, value)
+	}
+	if _, ok := tables["n1"].Get(moving); ok {
+		t.Errorf("Expected the old copy of %s dropped", moving)
+	}
+}
+
+// countLines returns the number of lines of a file, 0 when it is missing
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
+
+// Replicas compact their log into a snapshot, a replica behind the compacted entries gets the
+// snapshot of the leader and routes keys like the others
+func TestRaftSnapshot(t *testing.T) {
+	nodes := map[string]coordinator.Node{}
+	for _, nodeID := range []string{"n1", "n2"} {
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: utils.NewHashTable(10)})
+		nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	configs := replicaConfigs(t, nodes, "c1", "c2", "c3")
+	for _, config := range configs {
+		config.Raft.SnapshotEntries = 4
+	}
+	replicas := startReplicas(t, configs)
+	all := []*coordinator.Coordinator{replicas["c1"], replicas["c2"], replicas["c3"]}
+	leaderID := waitMetadata(t, all, []string{"n1", "n2"}, 1)
+
+	stoppedID := "c1"
+	if stoppedID == leaderID {
+		stoppedID = "c2"
+	}
+	replicas[stoppedID].Close()
+	delete(replicas, stoppedID)
+	remaining := []*coordinator.Coordinator{}
+	for _, c := range replicas {
+		remaining = append(remaining, c)
+	}
+
+	changes := 12
+	for i := 0; i < changes; i++ {
+		if err := replicas[leaderID].SetNodeWeight("n2", i%3+1); err != nil {
+			t.Fatalf("Set weight failed : %v", err)
+		}
+		waitMigrations(t, replicas[leaderID])
+	}
+	version := uint64(changes + 1)
+	waitMetadata(t, remaining, []string{"n1", "n2"}, version)
+	for replicaID := range replicas {
+		stateFile := configs[replicaID].Raft.StateFile
+		waitFor(t, "a snapshot of Replica["+replicaID+"]", func() bool {
+			_, err := os.Stat(stateFile + ".snapshot")
+			return err == nil
+		})
+		if lines := countLines(t, stateFile+".log"); lines >= changes {
+			t.Errorf("Expected Replica[%s] to compact its log, it holds %d entries", replicaID, lines)
+		}
+	}
+
+	// The stopped replica misses entries the others dropped
+	restarted, err := coordinator.NewCoordinator(configs[stoppedID])
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicas[stoppedID] = restarted
+	all = append(remaining, restarted)
+	waitMetadata(t, all, []string{"n1", "n2"}, version)
+	if err := restarted.SetNodeWeight("n2", 5); err != nil {
+		t.Fatalf("Set weight through the restarted replica failed : %v", err)
+	}
+	waitMetadata(t, all, []string{"n1", "n2"}, version+1)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if owner, expected := mustOwner(t, restarted, key), mustOwner(t, replicas[leaderID], key); owner != expected {
+			t.Errorf("Restarted replica routes %s to %s, the leader to %s", key, owner, expected)
+		}
+	}
+}
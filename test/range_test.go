@@ -0,0 +1,79 @@
+package test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func rangeKeys(entries []utils.Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func expectKeys(t *testing.T, name string, got []string, expected ...string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("%s : expected %v, got %v", name, expected, got)
+	}
+}
+
+func TestRange(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	for i := 100; i < 110; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		ht.Put(key, []byte(key), nil)
+	}
+	ht.Put("order:1", []byte("order"), nil)
+
+	expectKeys(t, "Bounded", rangeKeys(ht.Range("user:102", "user:105", 0, false)),
+		"user:102", "user:103", "user:104")
+	expectKeys(t, "Limit", rangeKeys(ht.Range("user:", "", 2, false)),
+		"user:100", "user:101")
+	expectKeys(t, "Reverse", rangeKeys(ht.Range("user:102", "user:105", 0, true)),
+		"user:104", "user:103", "user:102")
+	expectKeys(t, "Reverse limit", rangeKeys(ht.Range("", "", 2, true)),
+		"user:109", "user:108")
+	expectKeys(t, "Empty", rangeKeys(ht.Range("user:2", "user:3", 0, false)))
+
+	// The index follows deletes and new keys
+	ht.Delete("user:103", nil)
+	ht.Put("user:1035", []byte("new"), nil)
+	expectKeys(t, "After mutations", rangeKeys(ht.Range("user:102", "user:105", 0, false)),
+		"user:102", "user:1035", "user:104")
+
+	entries := ht.Range("user:1035", "user:1036", 0, false)
+	if len(entries) != 1 || string(entries[0].Value) != "new" {
+		t.Errorf("Range should return values, got %v", entries)
+	}
+}
+
+// Keys returned by several replicas are merged once, with the value of their latest write
+func TestMergeRanges(t *testing.T) {
+	results := [][]*pb.KeyValue{
+		{{Key: "a", Value: []byte("old"), Timestamp: 1}, {Key: "b", Value: []byte("b"), Timestamp: 1}, {Key: "d", Value: []byte("old"), Timestamp: 1}},
+		{{Key: "a", Value: []byte("new"), Timestamp: 2}, {Key: "c", Value: []byte("c"), Timestamp: 1}, {Key: "d", Value: []byte("new"), Timestamp: 2}},
+		{{Key: "b", Value: []byte("b"), Timestamp: 1}, {Key: "c", Value: []byte("c"), Timestamp: 1}},
+	}
+	values := func(entries []*pb.KeyValue) []string {
+		pairs := []string{}
+		for _, entry := range entries {
+			pairs = append(pairs, entry.Key+"="+string(entry.Value))
+		}
+		return pairs
+	}
+	expectKeys(t, "Merged", values(coordinator.MergeRanges(results, 0, false)), "a=new", "b=b", "c=c", "d=new")
+	expectKeys(t, "Limit", values(coordinator.MergeRanges(results, 1, false)), "a=new")
+
+	for _, entries := range results {
+		slices.Reverse(entries)
+	}
+	expectKeys(t, "Reverse", values(coordinator.MergeRanges(results, 2, true)), "d=new", "c=c")
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+// Reads return the newest version of the replicas and write it back to the stale ones
+func TestReadRepair(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 3, ReadQuorum: 2}
+	config.ReadRepair.Chance = 100
+	config.ReadRepair.Mode = coordinator.ReadRepairBlocking
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.Put(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := c.KeyOwner("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := []*utils.HashTable{}
+	for _, nodeID := range owner.Replicas {
+		tables = append(tables, processes[nodeID].storage.HashTable)
+	}
+
+	// The last replica missed an update
+	stamp, _ := tables[0].Stamp("a")
+	for _, ht := range tables[:2] {
+		ht.PutAt("a", []byte("2"), 0, stamp.Timestamp+1, nil)
+	}
+	if _, res, err := c.Get(ctx, "a"); err != nil || string(res.Value) != "2" {
+		t.Fatalf("Expected the newest version, got %+v (%v)", res, err)
+	}
+	if value, _ := tables[2].Get("a"); string(value) != "2" {
+		t.Errorf("Expected the stale replica to be repaired, got %q", value)
+	}
+	if stats := c.ReadRepairs(); stats.Reads != 1 || stats.Mismatches != 1 || stats.Repaired != 1 {
+		t.Errorf("Expected 1 replica repaired, got %+v", stats)
+	}
+
+	// A delete only the second replica got wins over the older writes
+	tables[1].DeleteAt("a", stamp.Timestamp+2, nil)
+	if _, res, err := c.Get(ctx, "a"); err != nil || res.Found || !res.Deleted {
+		t.Fatalf("Expected the delete, got %+v (%v)", res, err)
+	}
+	for i, ht := range tables {
+		if stamp, ok := ht.Stamp("a"); !ok || !stamp.Deleted {
+			t.Errorf("Expected a tombstone on replica %d, got %+v", i, stamp)
+		}
+	}
+	if stats := c.ReadRepairs(); stats.Repaired != 3 || stats.Failed != 0 {
+		t.Errorf("Expected 3 replicas repaired, got %+v", stats)
+	}
+
+	// Without read repair any two replicas return the newest version, the stale owner is left
+	config.ReadRepair.Chance = 0
+	quorum, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer quorum.Close()
+	for _, ht := range tables[1:] {
+		ht.PutAt("b", []byte("1"), 0, stamp.Timestamp, nil)
+	}
+	if _, res, err := quorum.Get(ctx, "b"); err != nil || string(res.Value) != "1" {
+		t.Fatalf("Expected b from the other replicas, got %+v (%v)", res, err)
+	}
+	if stats := quorum.ReadRepairs(); stats.Reads != 0 || stats.Mismatches > 1 {
+		t.Errorf("Expected no read repair, got %+v", stats)
+	}
+	if _, found := tables[0].Get("b"); found {
+		t.Errorf("Expected b to stay missing on the owner")
+	}
+
+	// In the background the read returns first
+	config.ReadRepair.Chance, config.ReadRepair.Mode = 100, coordinator.ReadRepairAsync
+	async, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer async.Close()
+	if _, res, err := async.Get(ctx, "b"); err != nil || string(res.Value) != "1" {
+		t.Fatalf("Expected b, got %+v (%v)", res, err)
+	}
+	waitFor(t, "the read repair of b", func() bool { return async.ReadRepairs().Repaired == 1 })
+	for i, ht := range tables {
+		if value, _ := ht.Get("b"); string(value) != "1" {
+			t.Errorf("Expected b on replica %d, got %q", i, value)
+		}
+	}
+
+	// A replica down does not fail the read while the quorum answers
+	processes[owner.Replicas[2]].stop()
+	if _, res, err := quorum.Get(ctx, "b"); err != nil || string(res.Value) != "1" {
+		t.Fatalf("Expected b with a replica down, got %+v (%v)", res, err)
+	}
+	processes[owner.Replicas[1]].stop()
+	if _, _, err := quorum.Get(ctx, "b"); !errors.Is(err, coordinator.ErrQuorum) {
+		t.Errorf("Expected a quorum error with two replicas down, got %v", err)
+	}
+
+	config.ReadRepair.Mode = "eager"
+	if _, err := coordinator.NewCoordinator(config); err == nil {
+		t.Errorf("Expected an unknown read repair mode to fail")
+	}
+}
@@ -0,0 +1,426 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func merkleRoot(t *testing.T, ht *utils.HashTable) uint64 {
+	t.Helper()
+	digests, err := ht.MerkleTree(nil, "", 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digests[0]
+}
+
+// Write times and tombstones survive a checkpoint and a WAL replay, moved keys leave no tombstone
+func TestMerkleRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+		WALFile:        filepath.Join(dir, "node.wal"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.Put("a", []byte("1"), nil)
+	live.Put("b", []byte("2"), nil)
+	live.Delete("b", nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	live.Put("c", []byte("3"), rInfo)
+	live.Increment("d", 1, rInfo)
+	live.Delete("a", rInfo)
+	live.DropKeys(func(key string) bool { return key == "d" }, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	if merkleRoot(t, live) != merkleRoot(t, recovered) {
+		t.Errorf("Expected the recovered tree to match")
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		stamp, _ := live.Stamp(key)
+		if restored, ok := recovered.Stamp(key); !ok || !reflect.DeepEqual(restored, stamp) {
+			t.Errorf("Expected %s stamped %+v, got %+v", key, stamp, restored)
+		}
+	}
+	if stamp, ok := recovered.Stamp("b"); !ok || !stamp.Deleted {
+		t.Errorf("Expected a tombstone of b, got %+v", stamp)
+	}
+	if _, ok := recovered.Stamp("d"); ok {
+		t.Errorf("Moved key should leave no tombstone")
+	}
+}
+
+// Replicas that took different values at the same time have different trees and converge on one
+func TestMerkleValues(t *testing.T) {
+	timestamp := time.Now().UnixNano()
+	a, b := utils.NewHashTable(10), utils.NewHashTable(10)
+	for _, ht := range []*utils.HashTable{a, b} {
+		if _, err := ht.PutAt("same", []byte("1"), 0, timestamp, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := a.PutAt("k", []byte("1"), 0, timestamp, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutAt("k", []byte("2"), 0, timestamp, nil); err != nil {
+		t.Fatal(err)
+	}
+	if merkleRoot(t, a) == merkleRoot(t, b) {
+		t.Fatalf("Different values should give different trees")
+	}
+
+	leaves := make([]uint32, 1<<utils.MerkleDepth)
+	for i := range leaves {
+		leaves[i] = uint32(i)
+	}
+	for _, pair := range [][2]*utils.HashTable{{a, b}, {b, a}} {
+		entries, err := pair[0].ExportReplica(nil, "", leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if _, err := pair[1].MergeReplica(entry, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if merkleRoot(t, a) != merkleRoot(t, b) {
+		t.Fatalf("Replicas should converge")
+	}
+	va, _, _ := a.GetValue("k")
+	vb, _, _ := b.GetValue("k")
+	if string(va) != string(vb) {
+		t.Errorf("Replicas kept %q and %q", va, vb)
+	}
+}
+
+// Tombstones older than TombstoneTTL are purged, newer ones and rewritten keys are kept
+func TestTombstonePurge(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	now := time.Now().UnixNano()
+	old := now - int64(utils.TombstoneTTL) - int64(time.Hour)
+	for i := 0; i < 100; i++ {
+		if _, _, err := ht.DeleteAt(fmt.Sprintf("old%d", i), old+int64(i), nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := ht.DeleteAt(fmt.Sprintf("new%d", i), now, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ht.PutAt("old0", []byte("1"), 0, old+int64(time.Minute), nil); err != nil {
+		t.Fatal(err)
+	}
+	if tombstones := ht.Stats().Tombstones; tombstones != 199 {
+		t.Fatalf("Expected 199 tombstones, got %d", tombstones)
+	}
+
+	ht.DeleteExpired(nil)
+	if tombstones := ht.Stats().Tombstones; tombstones != 100 {
+		t.Fatalf("Expected 100 tombstones after the purge, got %d", tombstones)
+	}
+	if _, ok := ht.Stamp("old1"); ok {
+		t.Errorf("Old tombstone should be purged")
+	}
+	if stamp, ok := ht.Stamp("old0"); !ok || stamp.Deleted {
+		t.Errorf("Rewritten key should keep its stamp, got %+v", stamp)
+	}
+	if stamp, ok := ht.Stamp("new1"); !ok || !stamp.Deleted {
+		t.Errorf("New tombstone should be kept, got %+v", stamp)
+	}
+}
+
+// Replicas that missed writes and deletes converge on the newer write of every key
+func TestRepair(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+	}
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Replicated by hand, the secondary keeps the time of the primary
+	replicas := map[string][]string{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key%d", i)
+		owner, err := c.KeyOwner(key)
+		if err != nil || len(owner.Replicas) != 2 {
+			t.Fatalf("Expected 2 replicas of %s, got %v (%v)", key, owner.Replicas, err)
+		}
+		replicas[key] = owner.Replicas
+		primary, secondary := tables[owner.Replicas[0]], tables[owner.Replicas[1]]
+		primary.Put(key, []byte(key), nil)
+		stamp, _ := primary.Stamp(key)
+		if merged, err := secondary.MergeReplica(utils.ReplicaEntry{Entry: utils.Entry{Key: key, Value: []byte(key)}, Timestamp: stamp.Timestamp}, nil); !merged || err != nil {
+			t.Fatalf("Merge of %s failed : %v", key, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	results, err := c.Repair(ctx)
+	if err != nil || len(results) != 3 {
+		t.Fatalf("Expected 3 pairs of replicas, got %+v (%v)", results, err)
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Leaves != 0 {
+			t.Errorf("Expected Node[%s] and Node[%s] in sync, got %+v", result.Node, result.Peer, result)
+		}
+	}
+
+	// The primary misses a delete, the secondaries an update and new keys
+	tables[replicas["key1"][1]].Delete("key1", nil)
+	tables[replicas["key2"][0]].Put("key2", []byte("new"), nil)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("extra%d", i)
+		owner, err := c.KeyOwner(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		replicas[key] = owner.Replicas
+		tables[owner.Replicas[0]].Put(key, []byte(key), nil)
+	}
+	results, err = c.Repair(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exchanged uint64
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("Node[%s] repair with Node[%s] failed : %v", result.Node, result.Peer, result.Err)
+		}
+		exchanged += result.Received + result.Sent
+	}
+	if exchanged != 12 {
+		t.Errorf("Expected the delete, the update and the new keys to be exchanged, got %+v", results)
+	}
+
+	for key, nodes := range replicas {
+		first, found := tables[nodes[0]].Get(key)
+		second, _ := tables[nodes[1]].Get(key)
+		if string(first) != string(second) {
+			t.Errorf("Replicas of %s differ : %q and %q", key, first, second)
+		}
+		if key == "key1" && found {
+			t.Errorf("Expected key1 to be deleted on both replicas")
+		}
+	}
+	if value, _ := tables[replicas["key2"][1]].Get("key2"); string(value) != "new" {
+		t.Errorf("Expected the update of key2, got %q", value)
+	}
+	for nodeID, ht := range tables {
+		for key, nodes := range replicas {
+			if _, found := ht.Get(key); found && nodeID != nodes[0] && nodeID != nodes[1] {
+				t.Errorf("Repair copied %s to Node[%s] which is not a replica", key, nodeID)
+			}
+		}
+	}
+
+	results, err = c.Repair(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Leaves != 0 {
+			t.Errorf("Expected Node[%s] and Node[%s] in sync after the repair, got %+v", result.Node, result.Peer, result)
+		}
+	}
+}
+
+// Placements that are not rings repair the keys every pair of nodes replicates
+func TestRepairPlacements(t *testing.T) {
+	for _, strategy := range []string{utils.PlacementRendezvous, utils.PlacementJump} {
+		t.Run(strategy, func(t *testing.T) {
+			tables := map[string]*utils.HashTable{}
+			config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, ReplicationFactor: 2}
+			config.Placement.Strategy = strategy
+			for _, nodeID := range []string{"n1", "n2", "n3"} {
+				tables[nodeID] = utils.NewHashTable(10)
+				port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+				config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port}
+			}
+			c, err := coordinator.NewCoordinator(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			// Written on the primary only, the secondary of key0 misses a delete
+			replicas := map[string][]string{}
+			for i := 0; i < 50; i++ {
+				key := fmt.Sprintf("key%d", i)
+				owner, err := c.KeyOwner(key)
+				if err != nil || len(owner.Replicas) != 2 {
+					t.Fatalf("Expected 2 replicas of %s, got %v (%v)", key, owner.Replicas, err)
+				}
+				replicas[key] = owner.Replicas
+				tables[owner.Replicas[0]].Put(key, []byte(key), nil)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			results, err := c.Repair(ctx)
+			if err != nil || len(results) != 3 {
+				t.Fatalf("Expected 3 pairs of replicas, got %+v (%v)", results, err)
+			}
+			tables[replicas["key0"][0]].Delete("key0", nil)
+			if _, err := c.Repair(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			for key, nodes := range replicas {
+				for nodeID, ht := range tables {
+					_, found := ht.Get(key)
+					if replica := slices.Contains(nodes, nodeID); found != (replica && key != "key0") {
+						t.Errorf("Expected %s on Node[%s] %v, got %v", key, nodeID, replica && key != "key0", found)
+					}
+				}
+			}
+			results, err = c.Repair(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, result := range results {
+				if result.Err != nil || result.Leaves != 0 {
+					t.Errorf("Expected Node[%s] and Node[%s] in sync after the repair, got %+v", result.Node, result.Peer, result)
+				}
+			}
+		})
+	}
+}
+
+// The digests over ranges are those of a table holding only the keys in them, whatever hash
+// function places the keys, and are read while the table is written
+func TestMerkleRanges(t *testing.T) {
+	full := utils.NewHashTable(10)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		full.Put(key, []byte(key), nil)
+		if i%7 == 0 {
+			full.Delete(key, nil)
+		}
+	}
+	// A wrapping range and one inside a single leaf
+	ranges := []utils.HashRange{{Start: 0xf0000000, End: 0x10000000}, {Start: 0x80000010, End: 0x80000fff}, {Start: 0x40000000, End: 0x60000000}}
+	hashes := map[string]utils.HashFunc{}
+	for _, hashName := range []string{utils.HashFNV1a, utils.HashCRC32} {
+		hash, err := utils.ParseHash(hashName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes[hashName] = hash
+	}
+	inRanges := func(hash utils.HashFunc, key string) bool {
+		for _, r := range ranges {
+			if r.Contains(hash(key)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Writes of keys out of the ranges go on while the digests are read
+	others := []string{}
+	for i := 0; len(others) < 50; i++ {
+		key := fmt.Sprintf("other%d", i)
+		if !inRanges(hashes[utils.HashFNV1a], key) && !inRanges(hashes[utils.HashCRC32], key) {
+			others = append(others, key)
+		}
+	}
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			full.Put(others[i%len(others)], []byte("v"), nil)
+			full.Delete(others[i%len(others)], nil)
+		}
+	}()
+	for _, hashName := range []string{utils.HashFNV1a, utils.HashCRC32} {
+		covered := utils.NewHashTable(10)
+		for i := 0; i < 2000; i++ {
+			key := fmt.Sprintf("key%d", i)
+			if !inRanges(hashes[hashName], key) {
+				continue
+			}
+			stamp, _ := full.Stamp(key)
+			entry := utils.ReplicaEntry{Entry: utils.Entry{Key: key, Value: []byte(key)}, Timestamp: stamp.Timestamp, Deleted: stamp.Deleted}
+			if entry.Deleted {
+				entry.Value = nil
+			}
+			if _, err := covered.MergeReplica(entry, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, level := range []int{0, 6, utils.MerkleDepth} {
+			expected, err := covered.MerkleTree(nil, hashName, level, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := full.MerkleTree(ranges, hashName, level, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(expected) {
+				t.Fatalf("Expected %d digests at level %d, got %d", len(expected), level, len(got))
+			}
+			for i := range expected {
+				if got[i] != expected[i] {
+					t.Errorf("Level %d node %d with %s : expected %x, got %x", level, i, hashName, expected[i], got[i])
+					break
+				}
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
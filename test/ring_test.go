@@ -0,0 +1,112 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+	"google.golang.org/grpc"
+)
+
+// adminClient connects to the admin service of a coordinator
+func adminClient(t *testing.T, port uint64) pb.CoordinatorClient {
+	t.Helper()
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewCoordinatorClient(conn)
+}
+
+// The admin service lists the ring tokens, the owner and replicas of a key and the balance of the nodes
+func TestRingInspection(t *testing.T) {
+	tables := map[string]*utils.HashTable{}
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2, AdminPort: freePort(t)}
+	for _, nodeID := range []string{"n1", "n2", "n3"} {
+		tables[nodeID] = utils.NewHashTable(10)
+		port := serveStorage(t, &node.StorageServer{NodeID: nodeID, HashTable: tables[nodeID]})
+		config.Nodes[nodeID] = coordinator.Node{Host: "127.0.0.1", Port: port, Weight: len(config.Nodes) + 1}
+	}
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("key%d", i)
+		tables[mustOwner(t, c, key)].Put(key, []byte(key), nil)
+	}
+
+	admin := adminClient(t, config.AdminPort)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ringRes, err := admin.GetRing(ctx, &pb.CoordinatorGetRingRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ringRes.Ring.Tokens) != 11*6 || ringRes.Ring.Version != 1 || len(ringRes.Nodes) != 3 || ringRes.ReplicationFactor != 2 {
+		t.Errorf("Expected 66 tokens of 3 nodes at version 1, got %d tokens of %d nodes at %d", len(ringRes.Ring.Tokens), len(ringRes.Nodes), ringRes.Ring.Version)
+	}
+	ring, err := utils.RingFromProto(ringRes.Ring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key%d", i)
+		owner, err := admin.GetOwner(ctx, &pb.CoordinatorGetOwnerRequest{Key: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := mustOwner(t, c, key)
+		if owner.Owner != want || owner.TokenNode != want || len(owner.Replicas) != 2 || owner.Replicas[0] != want || owner.Replicas[1] == want {
+			t.Errorf("Key %s owned by %s, got %+v", key, want, owner)
+		}
+		if got, _ := ring.GetNode(key); got != want || owner.Position != ring.Position(key) {
+			t.Errorf("The fetched ring places %s on %s", key, got)
+		}
+		if owner.Token < owner.Position && owner.Token != ringRes.Ring.Tokens[0].Position {
+			t.Errorf("Token %d does not hold position %d", owner.Token, owner.Position)
+		}
+	}
+
+	balance, err := admin.GetBalance(ctx, &pb.CoordinatorGetBalanceRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	share, keys := 0.0, uint64(0)
+	for _, n := range balance.Nodes {
+		share += n.Share
+		keys += n.Keys
+		if n.Error != "" || n.Weight != int64(config.Nodes[n.NodeID].Weight) || n.Keys != uint64(tables[n.NodeID].Stats().NumKeys) {
+			t.Errorf("Unexpected balance %+v", n)
+		}
+	}
+	if len(balance.Nodes) != 3 || keys != 300 || math.Abs(share-1) > 1e-9 {
+		t.Errorf("Expected 300 keys over the whole ring, got %d keys over %.3f", keys, share)
+	}
+
+	// Other placements have no tokens, the weights of their nodes are sent instead
+	config = &coordinator.Config{Nodes: config.Nodes, AdminPort: freePort(t)}
+	config.Placement.Strategy = utils.PlacementJump
+	jump, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jump.Close()
+	jumpRes, err := adminClient(t, config.AdminPort).GetRing(ctx, &pb.CoordinatorGetRingRequest{})
+	if err != nil || nil != jumpRes.Ring || nil == jumpRes.Placement || jumpRes.Placement.Strategy != utils.PlacementJump || len(jumpRes.Placement.Weights) != 3 {
+		t.Errorf("Expected the jump placement, got %+v (%v)", jumpRes, err)
+	}
+	if owner, err := jump.KeyOwner("key1"); err != nil || owner.Token != nil || owner.Owner != mustOwner(t, jump, "key1") {
+		t.Errorf("Unexpected owner %+v (%v)", owner, err)
+	}
+}
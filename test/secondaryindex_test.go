@@ -0,0 +1,52 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func TestSecondaryIndex(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	// Keys present before the index is declared are indexed too
+	ht.Put("user:1", []byte(`{"email":"a@example.com","profile":{"age":30}}`), nil)
+	if err := ht.AddIndex("email", "email"); err != nil {
+		t.Fatalf("AddIndex failed : %v", err)
+	}
+	if err := ht.AddIndex("age", "profile.age"); err != nil {
+		t.Fatalf("AddIndex failed : %v", err)
+	}
+	if err := ht.AddIndex("email", "other"); err == nil {
+		t.Errorf("Duplicate index name should fail")
+	}
+
+	ht.Put("user:2", []byte(`{"email":"b@example.com","profile":{"age":30}}`), nil)
+	ht.Put("user:3", []byte(`{"email":"a@example.com"}`), nil)
+	ht.Put("blob", []byte("not json"), nil)
+
+	entries, err := ht.QueryIndex("email", "a@example.com", 0)
+	if err != nil {
+		t.Fatalf("QueryIndex failed : %v", err)
+	}
+	expectKeys(t, "Email", rangeKeys(entries), "user:1", "user:3")
+
+	entries, _ = ht.QueryIndex("age", "30", 1)
+	expectKeys(t, "Nested with limit", rangeKeys(entries), "user:1")
+
+	// Update moves the key to its new field value
+	ht.Update("user:3", []byte(`{"email":"c@example.com"}`), nil)
+	entries, _ = ht.QueryIndex("email", "a@example.com", 0)
+	expectKeys(t, "After update", rangeKeys(entries), "user:1")
+	entries, _ = ht.QueryIndex("email", "c@example.com", 0)
+	expectKeys(t, "Updated value", rangeKeys(entries), "user:3")
+
+	ht.Delete("user:1", nil)
+	entries, _ = ht.QueryIndex("email", "a@example.com", 0)
+	expectKeys(t, "After delete", rangeKeys(entries))
+
+	if _, err := ht.QueryIndex("missing", "x", 0); !errors.Is(err, utils.ErrUnknownIndex) {
+		t.Errorf("Expected ErrUnknownIndex, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func TestTxnPrepareCommit(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.Put("alice", []byte("100"), nil)
+	version := ht.Range("alice", "alice\x00", 0, false)[0].Version
+
+	compares := []utils.Compare{
+		{Key: "alice", Target: "VERSION", Result: "=", Version: version},
+		{Key: "bob", Target: "VERSION", Result: "=", Version: 0},
+	}
+	then := []utils.TxnOp{{Type: "PUT", Key: "alice", Value: []byte("60")}, {Type: "PUT", Key: "bob", Value: []byte("40")}}
+	els := []utils.TxnOp{{Type: "GET", Key: "alice"}}
+
+	succeeded, err := ht.PrepareTxn("t1", compares, then, els, nil)
+	if err != nil || !succeeded {
+		t.Fatalf("Prepare should succeed, got %v (%v)", succeeded, err)
+	}
+
+	// Prepared keys are locked for everyone else
+	if _, err := ht.PutWithExpiry("alice", []byte("0"), 0, nil); !errors.Is(err, utils.ErrTxnConflict) {
+		t.Errorf("Expected ErrTxnConflict, got %v", err)
+	}
+	if _, err := ht.PrepareTxn("t2", nil, []utils.TxnOp{{Type: "DELETE", Key: "bob"}}, nil, nil); !errors.Is(err, utils.ErrTxnConflict) {
+		t.Errorf("Expected ErrTxnConflict, got %v", err)
+	}
+
+	results, err := ht.CommitTxn("t1", true, nil)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Unexpected commit results %+v (%v)", results, err)
+	}
+	if value, _ := ht.Get("bob"); string(value) != "40" {
+		t.Errorf("Expected bob 40, got %s", value)
+	}
+	if _, err := ht.PutWithExpiry("alice", []byte("0"), 0, nil); err != nil {
+		t.Errorf("Keys should be released after commit, got %v", err)
+	}
+
+	// A stale version takes the else branch, aborts apply nothing
+	if succeeded, _ := ht.PrepareTxn("t3", compares, then, els, nil); succeeded {
+		t.Errorf("Compare on a stale version should fail")
+	}
+	results, _ = ht.CommitTxn("t3", false, nil)
+	if len(results) != 1 || !results[0].Found || string(results[0].Value) != "0" {
+		t.Errorf("Unexpected else results %+v", results)
+	}
+	ht.PrepareTxn("t4", nil, []utils.TxnOp{{Type: "DELETE", Key: "bob"}}, nil, nil)
+	ht.AbortTxn("t4", nil)
+	if _, ok := ht.Get("bob"); !ok {
+		t.Errorf("Aborted transaction should not delete bob")
+	}
+}
+
+// A prepared transaction survives a restart with its keys locked and can be committed after it
+func TestTxnRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+		WALFile:        filepath.Join(dir, "node.wal"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.PrepareTxn("checkpointed", nil, []utils.TxnOp{{Type: "PUT", Key: "a", Value: []byte("1")}}, nil, nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	live.PrepareTxn("logged", nil, []utils.TxnOp{{Type: "PUT", Key: "b", Value: []byte("2")}}, nil, rInfo)
+	live.PrepareTxn("committed", nil, []utils.TxnOp{{Type: "PUT", Key: "c", Value: []byte("3")}}, nil, rInfo)
+	live.CommitTxn("committed", true, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	prepared := recovered.PreparedTxns()
+	sort.Strings(prepared)
+	expectKeys(t, "Prepared", prepared, "checkpointed", "logged")
+	expectKeys(t, "Committed keys", rangeKeys(recovered.Range("", "", 0, false)), "c")
+	if _, err := recovered.PutWithExpiry("b", []byte("x"), 0, nil); !errors.Is(err, utils.ErrTxnConflict) {
+		t.Errorf("Recovered transaction should lock b, got %v", err)
+	}
+
+	recovered.CommitTxn("checkpointed", true, nil)
+	recovered.CommitTxn("logged", true, nil)
+	expectKeys(t, "After commit", rangeKeys(recovered.Range("", "", 0, false)), "a", "b", "c")
+}
+
+func TestTxn(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.Put("stock", []byte("1"), nil)
+
+	watch, _, _ := ht.Watch("order:", true, 0)
+	defer watch.Close()
+
+	compares := []utils.Compare{
+		{Key: "order:1", Target: "EXISTS", Result: "!="},
+		{Key: "stock", Target: "VALUE", Result: "=", Value: []byte("1")},
+	}
+	then := []utils.TxnOp{{Type: "PUT", Key: "order:1", Value: []byte("placed")}, {Type: "PUT", Key: "stock", Value: []byte("0")}}
+	els := []utils.TxnOp{{Type: "GET", Key: "order:1"}}
+
+	succeeded, results, err := ht.Txn(compares, then, els, nil)
+	if err != nil || !succeeded || len(results) != 2 || results[0].Version != results[1].Version {
+		t.Fatalf("Unexpected txn result %v %+v (%v)", succeeded, results, err)
+	}
+
+	// Both writes arrive as one record sharing the version of the keys
+	record := nextRecord(t, watch)
+	changes := record.Changes()
+	if record.Operation != "TXN" || len(changes) != 2 || changes[0].LSN != results[0].Version {
+		t.Errorf("Expected one TXN record, got %+v", record)
+	}
+
+	succeeded, results, _ = ht.Txn(compares, then, els, nil)
+	if succeeded || len(results) != 1 || string(results[0].Value) != "placed" {
+		t.Errorf("Second txn should take the else branch, got %v %+v", succeeded, results)
+	}
+
+	ht.PrepareTxn("pending", nil, []utils.TxnOp{{Type: "DELETE", Key: "stock"}}, nil, nil)
+	if _, _, err := ht.Txn(nil, []utils.TxnOp{{Type: "PUT", Key: "stock", Value: []byte("5")}}, nil, nil); !errors.Is(err, utils.ErrTxnConflict) {
+		t.Errorf("Expected ErrTxnConflict, got %v", err)
+	}
+	if _, _, err := ht.Txn([]utils.Compare{{Key: "stock", Target: "EXISTS", Result: "<"}}, nil, nil, nil); err == nil {
+		t.Errorf("EXISTS should only compare with = or !=")
+	}
+}
+
+// A TXN record is replayed whole with the LSN it was logged with
+func TestTxnGroupRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+		WALFile:        filepath.Join(dir, "node.wal"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.Put("a", []byte("1"), nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	live.Put("d", []byte("4"), rInfo)
+	live.Txn(nil, []utils.TxnOp{{Type: "DELETE", Key: "a"}, {Type: "PUT", Key: "b", Value: []byte("2")}, {Type: "PUT", Key: "c", Value: []byte("3")}}, nil, rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	if len(records) != 2 || records[1].Operation != "TXN" || len(records[1].Group) != 3 {
+		t.Fatalf("Expected a PUT and a TXN of 3 writes, got %+v", records)
+	}
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	entries := recovered.Range("", "", 0, false)
+	expectKeys(t, "Recovered keys", rangeKeys(entries), "b", "c", "d")
+	for _, entry := range entries[:2] {
+		if entry.Version != records[1].LSN {
+			t.Errorf("Expected version %d for %s, got %d", records[1].LSN, entry.Key, entry.Version)
+		}
+	}
+}
+
+// A prepared transaction holds the memory of its writes, one that doesn't fit votes no
+func TestTxnPrepareReservesMemory(t *testing.T) {
+	ht := utils.NewHashTable(10)
+	ht.SetMemoryLimit(2*sampleEntrySize, utils.NoEviction)
+
+	then := []utils.TxnOp{{Type: "PUT", Key: "k1", Value: sampleValue}}
+	if _, err := ht.PrepareTxn("t1", nil, then, nil, nil); err != nil {
+		t.Fatalf("Prepare failed : %v", err)
+	}
+	if _, err := ht.PrepareTxn("t2", nil, []utils.TxnOp{{Type: "PUT", Key: "k2", Value: sampleValue}, {Type: "PUT", Key: "k3", Value: sampleValue}}, nil, nil); !errors.Is(err, utils.ErrOutOfMemory) {
+		t.Fatalf("Expected ErrOutOfMemory, got %v", err)
+	}
+	if _, err := ht.PutWithExpiry("k2", sampleValue, 0, nil); err != nil {
+		t.Fatalf("Put next to the prepared transaction failed : %v", err)
+	}
+	if _, err := ht.PutWithExpiry("k3", sampleValue, 0, nil); !errors.Is(err, utils.ErrOutOfMemory) {
+		t.Errorf("Expected the memory held by t1 to fail the put, got %v", err)
+	}
+
+	if _, err := ht.CommitTxn("t1", true, nil); err != nil {
+		t.Fatalf("Commit failed : %v", err)
+	}
+	expectKeys(t, "After commit", rangeKeys(ht.Range("", "", 0, false)), "k1", "k2")
+	if len(ht.PreparedTxns()) != 0 {
+		t.Errorf("Expected t2 to leave nothing prepared, got %v", ht.PreparedTxns())
+	}
+}
+
+// The keys a transaction writes on their owners are replicated once it is decided
+func TestReplicatedTxn(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2, WriteQuorum: 2}
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	keys := []string{}
+	then := []*pb.TxnOp{}
+	for i := 0; i < 6; i++ {
+		key := fmt.Sprintf("key%d", i)
+		keys = append(keys, key)
+		then = append(then, &pb.TxnOp{Type: "PUT", Key: key, Value: []byte(key)})
+	}
+	if res, err := c.Txn(ctx, &coordinator.TxnRequest{Then: then}); err != nil || !res.Succeeded {
+		t.Fatalf("Expected the txn to commit, got %+v (%v)", res, err)
+	}
+	expectReplicasEqual(t, c, processes, keys...)
+
+	// The else branch writes as well
+	res, err := c.NodeTxn(ctx, &coordinator.TxnRequest{
+		Compares: []*pb.TxnCompare{{Key: "key0", Target: "VALUE", Result: "=", Value: []byte("other")}},
+		Then:     []*pb.TxnOp{{Type: "PUT", Key: "key0", Value: []byte("then")}},
+		Else:     []*pb.TxnOp{{Type: "DELETE", Key: "key0"}},
+	})
+	if err != nil || res.Succeeded {
+		t.Fatalf("Expected the else branch, got %+v (%v)", res, err)
+	}
+	expectReplicasEqual(t, c, processes, "key0")
+	owner, _ := c.KeyOwner("key0")
+	if stamp, ok := processes[owner.Replicas[1]].storage.HashTable.Stamp("key0"); !ok || !stamp.Deleted {
+		t.Errorf("Expected key0 deleted on the replica, got %+v", stamp)
+	}
+}
@@ -0,0 +1,287 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/b1acktothefuture/dht-system/internal/coordinator"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func siblingValues(t *testing.T, ht *utils.HashTable, key string) []string {
+	t.Helper()
+	siblings, _, err := ht.GetSiblings(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []string{}
+	for _, sibling := range siblings {
+		values = append(values, string(sibling.Value))
+	}
+	return values
+}
+
+// Concurrent writes are kept as siblings on every replica whatever the order they arrive in, a
+// write with the context of both replaces them
+func TestSiblings(t *testing.T) {
+	rInfo := &utils.CheckpointInfo{WC: make(chan utils.WALRecord), WALFile: filepath.Join(t.TempDir(), "node.wal")}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	coordinating, replica := utils.NewHashTable(10), utils.NewHashTable(10)
+	coordinating.Put("k", []byte("old"), rInfo)
+	first, err := coordinating.PutSibling("k", []byte("a"), nil, "n1", 0, rInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := coordinating.PutSibling("k", []byte("b"), nil, "n1", 0, rInfo)
+	if values := siblingValues(t, coordinating, "k"); !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Fatalf("Expected the blind writes to replace the plain value and stay siblings, got %v", values)
+	}
+	if _, err := coordinating.ListPush("k", [][]byte{[]byte("x")}, false, nil); !errors.Is(err, utils.ErrWrongType) {
+		t.Errorf("Expected a versioned key to hold another type, got %v", err)
+	}
+
+	for _, sibling := range []utils.Sibling{second, first, second} {
+		replica.MergeSibling("k", sibling, 0, nil)
+	}
+	if values := siblingValues(t, replica, "k"); !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Errorf("Expected the replica to hold both siblings, got %v", values)
+	}
+
+	siblings, _, _ := coordinating.GetSiblings("k")
+	resolved, _ := coordinating.PutSibling("k", []byte("c"), utils.SiblingsClock(siblings), "n1", 0, rInfo)
+	if merged, _ := replica.MergeSibling("k", resolved, 0, nil); !merged {
+		t.Errorf("Expected the resolving write to be merged")
+	}
+	if merged, _ := replica.MergeSibling("k", first, 0, nil); merged {
+		t.Errorf("Expected a replaced sibling to be stale")
+	}
+	for _, ht := range []*utils.HashTable{coordinating, replica} {
+		if values := siblingValues(t, ht, "k"); !reflect.DeepEqual(values, []string{"c"}) {
+			t.Errorf("Expected the siblings resolved, got %v", values)
+		}
+	}
+
+	close(rInfo.WC)
+	<-collected
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, nil, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	restored, _, _ := recovered.GetSiblings("k")
+	if !reflect.DeepEqual(restored, []utils.Sibling{resolved}) {
+		t.Errorf("Expected the sibling recovered with its clock, got %+v", restored)
+	}
+
+	token := utils.EncodeContext(utils.SiblingsClock(restored))
+	if clock, err := utils.DecodeContext(token); err != nil || !clock.Covers(resolved.Dot) {
+		t.Errorf("Expected the context to cover the sibling, got %v (%v)", clock, err)
+	}
+}
+
+// A deleted versioned key keeps its clock on the tombstone, a later write counts on from it and
+// siblings the delete saw stay deleted
+func TestSiblingsAfterDelete(t *testing.T) {
+	rInfo := &utils.CheckpointInfo{WC: make(chan utils.WALRecord), WALFile: filepath.Join(t.TempDir(), "node.wal")}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	coordinating, replica := utils.NewHashTable(10), utils.NewHashTable(10)
+	first, err := coordinating.PutSibling("k", []byte("a"), nil, "n1", 100, rInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := coordinating.PutSibling("k", []byte("b"), nil, "n1", 100, rInfo)
+	replica.MergeSibling("k", first, 100, nil)
+
+	if _, applied, err := coordinating.DeleteAt("k", 200, rInfo); err != nil || !applied {
+		t.Fatalf("Expected the delete applied, got %v", err)
+	}
+	if stamp, ok := coordinating.Stamp("k"); !ok || !stamp.Deleted || !stamp.Clock.Covers(second.Dot) {
+		t.Fatalf("Expected the tombstone to keep the clock, got %+v", stamp)
+	}
+	for _, timestamp := range []int64{0, 300} {
+		if merged, _ := coordinating.MergeSibling("k", second, timestamp, nil); merged {
+			t.Errorf("Expected a deleted sibling to stay deleted at %d", timestamp)
+		}
+	}
+	if _, found, _ := coordinating.GetSiblings("k"); found {
+		t.Fatalf("Expected the key to stay deleted")
+	}
+
+	third, _ := coordinating.PutSibling("k", []byte("c"), nil, "n1", 300, rInfo)
+	if third.Dot.Counter != 3 || !third.Context.Covers(second.Dot) {
+		t.Fatalf("Expected the write to count on from the tombstone, got %+v", third)
+	}
+	if merged, _ := replica.MergeSibling("k", third, 300, nil); !merged {
+		t.Errorf("Expected the later write to be merged")
+	}
+	if values := siblingValues(t, replica, "k"); !reflect.DeepEqual(values, []string{"c"}) {
+		t.Errorf("Expected the write to replace the deleted siblings, got %v", values)
+	}
+
+	// The clock of a tombstone moves with it and survives a restart
+	moved := utils.NewHashTable(10)
+	coordinating.DeleteAt("k", 400, rInfo)
+	for _, entry := range coordinating.ExportKeys(func(string) bool { return true }) {
+		moved.Import(entry, nil)
+	}
+	close(rInfo.WC)
+	<-collected
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, nil, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	for name, ht := range map[string]*utils.HashTable{"moved": moved, "recovered": recovered} {
+		if sibling, _ := ht.PutSibling("k", []byte("d"), nil, "n1", 500, nil); sibling.Dot.Counter != 4 {
+			t.Errorf("Expected the %s tombstone to keep the clock, got %+v", name, sibling)
+		}
+	}
+}
+
+// A vclock keyspace keeps concurrent writes on every replica until a write resolves them, the
+// other keys keep the last write
+func TestVersionedKeyspace(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 2}
+	config.Keyspaces = []coordinator.Keyspace{{Prefix: "cart:", Versioning: coordinator.VersioningVectorClock}, {Prefix: "cart:lww:", Versioning: coordinator.VersioningLWW}}
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if !c.VectorClocks("cart:1") || c.VectorClocks("cart:lww:1") || c.VectorClocks("user:1") {
+		t.Fatalf("Expected the longest prefix to pick the versioning")
+	}
+
+	if result, err := c.Put(ctx, "cart:1", []byte("milk"), 0); err != nil || len(result.Acked) != 2 {
+		t.Fatalf("Expected the write on both replicas, got %+v (%v)", result, err)
+	}
+	if _, err := c.PutVersion(ctx, "cart:1", []byte("eggs"), ""); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := c.GetVersions(ctx, "cart:1")
+	if err != nil || len(versions.Values) != 2 {
+		t.Fatalf("Expected 2 siblings, got %+v (%v)", versions, err)
+	}
+	if _, _, err := c.Get(ctx, "cart:1"); !errors.Is(err, coordinator.ErrVersioned) {
+		t.Errorf("Expected a versioned key to be read with GetVersions, got %v", err)
+	}
+	if _, err := c.Put(ctx, "cart:1", []byte("milk"), 10); !errors.Is(err, coordinator.ErrVersioned) {
+		t.Errorf("Expected a versioned key to take no TTL, got %v", err)
+	}
+
+	if _, err := c.PutVersion(ctx, "cart:1", []byte("milk,eggs"), versions.Context); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := c.KeyOwner("cart:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nodeID := range owner.Replicas {
+		if values := siblingValues(t, processes[nodeID].storage.HashTable, "cart:1"); !reflect.DeepEqual(values, []string{"milk,eggs"}) {
+			t.Errorf("Expected Node[%s] to hold the resolved value, got %v", nodeID, values)
+		}
+	}
+	if _, err := c.PutVersion(ctx, "cart:1", []byte("x"), "not a context"); err == nil {
+		t.Errorf("Expected an invalid context to fail")
+	}
+
+	for _, value := range []string{"1", "2"} {
+		if _, err := c.Put(ctx, "cart:lww:1", []byte(value), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, res, err := c.Get(ctx, "cart:lww:1"); err != nil || string(res.Value) != "2" {
+		t.Errorf("Expected the last write, got %+v (%v)", res, err)
+	}
+
+	// The replicas agree on the time of the siblings
+	results, err := c.Repair(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Err != nil || result.Leaves != 0 {
+			t.Errorf("Expected Node[%s] and Node[%s] in sync, got %+v", result.Node, result.Peer, result)
+		}
+	}
+
+	config.Keyspaces = []coordinator.Keyspace{{Prefix: "cart:", Versioning: "crdt"}}
+	if _, err := coordinator.NewCoordinator(config); err == nil {
+		t.Errorf("Expected an unknown versioning to fail")
+	}
+}
+
+// The siblings are read from the replicas that answer first, a replica down does not fail the read
+func TestVersionsQuorum(t *testing.T) {
+	config := &coordinator.Config{Nodes: map[string]coordinator.Node{}, NumberOfVirtualNodes: 10, ReplicationFactor: 3, ReadQuorum: 2}
+	config.Keyspaces = []coordinator.Keyspace{{Prefix: "cart:", Versioning: coordinator.VersioningVectorClock}}
+	processes := startProcesses(t, config, "n1", "n2", "n3")
+	c, err := coordinator.NewCoordinator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, value := range []string{"milk", "eggs"} {
+		if _, err := c.PutVersion(ctx, "cart:1", []byte(value), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owner, err := c.KeyOwner("cart:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processes[owner.Replicas[0]].stop()
+	if versions, err := c.GetVersions(ctx, "cart:1"); err != nil || len(versions.Values) != 2 {
+		t.Fatalf("Expected 2 siblings with the owner down, got %+v (%v)", versions, err)
+	}
+	processes[owner.Replicas[1]].stop()
+	if _, err := c.GetVersions(ctx, "cart:1"); !errors.Is(err, coordinator.ErrQuorum) {
+		t.Errorf("Expected a quorum error with two replicas down, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/b1acktothefuture/dht-system/gen"
+	"github.com/b1acktothefuture/dht-system/internal/node"
+	"github.com/b1acktothefuture/dht-system/internal/utils"
+)
+
+func nextRecord(t *testing.T, subscription *utils.Subscription) utils.WALRecord {
+	t.Helper()
+	select {
+	case record := <-subscription.C:
+		return record
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a watch event")
+	}
+	return utils.WALRecord{}
+}
+
+func TestWatch(t *testing.T) {
+	ht := utils.NewHashTable(10)
+
+	keyWatch, _, _ := ht.Watch("config:a", false, 0)
+	defer keyWatch.Close()
+	prefixWatch, _, _ := ht.Watch("config:", true, 0)
+	defer prefixWatch.Close()
+
+	ht.Put("config:a", []byte("1"), nil)
+	ht.Put("other", []byte("x"), nil)
+	ht.Update("config:a", []byte("2"), nil)
+	ht.Put("config:b", []byte("3"), nil)
+	ht.Delete("config:a", nil)
+
+	expected := []struct {
+		operation string
+		lsn       uint64
+	}{{"PUT", 1}, {"UPDATE", 3}, {"DELETE", 5}}
+	for _, e := range expected {
+		record := nextRecord(t, keyWatch)
+		if record.Operation != e.operation || record.LSN != e.lsn || record.Key != "config:a" {
+			t.Errorf("Expected %s at LSN %d, got %+v", e.operation, e.lsn, record)
+		}
+	}
+
+	keys := []string{}
+	for i := 0; i < 4; i++ {
+		keys = append(keys, nextRecord(t, prefixWatch).Key)
+	}
+	expectKeys(t, "Prefix watch", keys, "config:a", "config:a", "config:b", "config:a")
+
+	// A resumed watch replays the retained records first
+	resumed, backlog, _ := ht.Watch("config:", true, 3)
+	defer resumed.Close()
+	expectKeys(t, "Backlog", rangeKeys(walEntries(backlog)), "config:a", "config:b", "config:a")
+}
+
+func walEntries(records []utils.WALRecord) []utils.Entry {
+	entries := []utils.Entry{}
+	for _, record := range records {
+		entries = append(entries, utils.Entry{Key: record.Key})
+	}
+	return entries
+}
+
+// LSNs and versions survive a checkpoint and a WAL replay, and the WAL can be read back from an LSN
+func TestLSNRecovery(t *testing.T) {
+	dir := t.TempDir()
+	rInfo := &utils.CheckpointInfo{
+		WC:             make(chan utils.WALRecord),
+		CheckPointFile: filepath.Join(dir, "node.chkpt"),
+		WALFile:        filepath.Join(dir, "node.wal"),
+	}
+	records := []utils.WALRecord{}
+	collected := make(chan struct{})
+	go func() {
+		for record := range rInfo.WC {
+			records = append(records, record)
+		}
+		close(collected)
+	}()
+
+	live := utils.NewHashTable(10)
+	live.Put("a", []byte("1"), nil)
+	live.Put("b", []byte("2"), nil)
+	live.Delete("b", nil)
+	if err := utils.TakeCheckpoint(live, rInfo); err != nil {
+		t.Fatalf("Checkpoint failed : %v", err)
+	}
+	live.Put("c", []byte("3"), rInfo)
+	live.Update("a", []byte("4"), rInfo)
+	close(rInfo.WC)
+	<-collected
+
+	file, err := os.Create(rInfo.WALFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		data, _ := json.Marshal(record)
+		file.Write(append(data, '\n'))
+	}
+	file.Close()
+
+	recovered := utils.NewHashTable(10)
+	if err := utils.CheckpointRestore(recovered, &rInfo.CheckPointFile, &rInfo.WALFile); err != nil {
+		t.Fatalf("Recovery failed : %v", err)
+	}
+	if recovered.LSN() != 5 {
+		t.Errorf("Expected LSN 5, got %d", recovered.LSN())
+	}
+	if entries := recovered.Range("a", "b", 0, false); len(entries) != 1 || entries[0].Version != 5 {
+		t.Errorf("Expected a at version 5, got %+v", entries)
+	}
+
+	// New writes continue the sequence
+	if _, err := recovered.Increment("n", 1, nil); err != nil || recovered.LSN() != 6 {
+		t.Errorf("Expected LSN 6, got %d", recovered.LSN())
+	}
+
+	history, err := utils.ReadWAL(rInfo.WALFile, 4, 6)
+	if err != nil || len(history) != 2 || history[0].Key != "c" {
+		t.Errorf("Unexpected WAL history %+v (%v)", history, err)
+	}
+	if _, err := utils.ReadWAL(rInfo.WALFile, 2, 6); !errors.Is(err, utils.ErrCompacted) {
+		t.Errorf("Expected ErrCompacted for a checkpointed LSN, got %v", err)
+	}
+}
+
+// A watch resumed before the records in memory reads the WAL after syncing it, the unflushed records included
+func TestWatchResumeUnflushed(t *testing.T) {
+	rInfo := &utils.CheckpointInfo{
+		WC:      make(chan utils.WALRecord),
+		SC:      make(chan chan error),
+		WALFile: filepath.Join(t.TempDir(), "node.wal"),
+	}
+
+	// The writer keeps every record in memory until a sync asks for them
+	go func() {
+		pending := []utils.WALRecord{}
+		for {
+			select {
+			case record := <-rInfo.WC:
+				pending = append(pending, record)
+			case done := <-rInfo.SC:
+				file, err := os.OpenFile(rInfo.WALFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+				if err == nil {
+					for _, record := range pending {
+						data, _ := json.Marshal(record)
+						file.Write(append(data, '\n'))
+					}
+					pending = pending[:0]
+					err = file.Close()
+				}
+				done <- err
+			}
+		}
+	}()
+
+	ht := utils.NewHashTable(10)
+	total := 1100
+	for i := 0; i < total; i++ {
+		ht.Put(fmt.Sprintf("key:%d", i), []byte("v"), rInfo)
+	}
+
+	storage := storageClient(t, serveStorage(t, &node.StorageServer{HashTable: ht, RInfo: rInfo}))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := storage.Watch(ctx, &pb.StorageWatchRequest{Key: "key:", Prefix: true, StartLSN: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for lsn := uint64(1); lsn <= uint64(total); lsn++ {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Stream failed at LSN %d : %v", lsn, err)
+		}
+		if event.LSN != lsn {
+			t.Fatalf("Expected LSN %d, got %d", lsn, event.LSN)
+		}
+	}
+}